@@ -0,0 +1,63 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ParseCommands parses inputs with p, using its batch implementation when it
+// is a BatchProcessor and parsing sequentially otherwise
+func ParseCommands(ctx context.Context, p Processor, inputs []string) ([]*NormalizedCommand, error) {
+	if bp, ok := p.(BatchProcessor); ok {
+		return bp.ParseCommands(ctx, inputs)
+	}
+	return ParseConcurrently(ctx, p, inputs, 1)
+}
+
+// ParseConcurrently parses inputs with at most concurrency calls to p in flight.
+// Results keep input order. A failed input leaves a nil entry and its error,
+// annotated with the input index, is joined into the returned error; the
+// remaining inputs are still parsed unless ctx is cancelled.
+func ParseConcurrently(ctx context.Context, p Processor, inputs []string, concurrency int) ([]*NormalizedCommand, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]*NormalizedCommand, len(inputs))
+	errs := make([]error, len(inputs))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			errs[i] = fmt.Errorf("input %d: %w", i, err)
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = fmt.Errorf("input %d: %w", i, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, input string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			cmd, err := p.ParseCommand(ctx, input)
+			if err != nil {
+				errs[i] = fmt.Errorf("input %d: %w", i, err)
+				return
+			}
+			results[i] = cmd
+		}(i, input)
+	}
+
+	wg.Wait()
+	return results, errors.Join(errs...)
+}
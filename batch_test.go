@@ -0,0 +1,96 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// echoProcessor echoes input into Symbol, fails on inputs starting with
+// "fail" and tracks the maximum number of concurrent calls
+type echoProcessor struct {
+	inFlight atomic.Int32
+	maxSeen  atomic.Int32
+}
+
+func (e *echoProcessor) Name() string                 { return "echo" }
+func (e *echoProcessor) SupportedLanguages() []string { return []string{"en"} }
+
+func (e *echoProcessor) ParseCommand(ctx context.Context, input string) (*NormalizedCommand, error) {
+	n := e.inFlight.Add(1)
+	defer e.inFlight.Add(-1)
+	for {
+		seen := e.maxSeen.Load()
+		if n <= seen || e.maxSeen.CompareAndSwap(seen, n) {
+			break
+		}
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if strings.HasPrefix(input, "fail") {
+		return nil, errors.New("backend error")
+	}
+	return &NormalizedCommand{Symbol: input, RawInput: input}, nil
+}
+
+func TestParseConcurrently_OrderAndBound(t *testing.T) {
+	p := &echoProcessor{}
+	inputs := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+	got, err := ParseConcurrently(context.Background(), p, inputs, 3)
+	if err != nil {
+		t.Fatalf("ParseConcurrently error: %v", err)
+	}
+
+	for i, cmd := range got {
+		if cmd.Symbol != inputs[i] {
+			t.Errorf("result[%d].Symbol = %q, want %q", i, cmd.Symbol, inputs[i])
+		}
+	}
+	if max := p.maxSeen.Load(); max > 3 {
+		t.Errorf("max concurrent calls = %d, want <= 3", max)
+	}
+}
+
+func TestParseConcurrently_PartialFailure(t *testing.T) {
+	inputs := []string{"a", "fail-1", "c"}
+
+	got, err := ParseConcurrently(context.Background(), &echoProcessor{}, inputs, 2)
+	if err == nil || !strings.Contains(err.Error(), "input 1") {
+		t.Fatalf("error = %v, want error mentioning input 1", err)
+	}
+	if got[0] == nil || got[2] == nil {
+		t.Error("successful inputs should still be returned")
+	}
+	if got[1] != nil {
+		t.Errorf("result[1] = %v, want nil", got[1])
+	}
+}
+
+func TestParseConcurrently_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ParseConcurrently(ctx, &echoProcessor{}, []string{"a", "b"}, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestParseCommands_FallsBackToSequential(t *testing.T) {
+	p := &echoProcessor{}
+
+	got, err := ParseCommands(context.Background(), p, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("ParseCommands error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d results, want 3", len(got))
+	}
+	if max := p.maxSeen.Load(); max != 1 {
+		t.Errorf("max concurrent calls = %d, want 1", max)
+	}
+}
@@ -0,0 +1,256 @@
+package intent
+
+import (
+	"container/list"
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachingProcessor memoizes ParseCommand results of another Processor.
+// Inputs are keyed case- and whitespace-insensitively, so "Show my  positions"
+// and "show my positions" share the same entry. Entries are scoped to the
+// user in the context (see WithUser) and to the parse options that change
+// the result (reference time, timezone, locale, coordinates and dynamic
+// entities; see WithParseOptions). Errors are never cached.
+type CachingProcessor struct {
+	next       Processor
+	ttl        time.Duration
+	maxEntries int
+	staleFor   time.Duration
+	clock      Clock
+	ids        IDGenerator
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // Front is the most recently used entry
+}
+
+type cacheEntry struct {
+	key     string
+	cmd     *NormalizedCommand
+	expires time.Time
+}
+
+// CacheOption configures a CachingProcessor
+type CacheOption func(*CachingProcessor)
+
+// WithCacheClock sets the clock used for expiry and hit timestamps
+// (default SystemClock)
+func WithCacheClock(clock Clock) CacheOption {
+	return func(c *CachingProcessor) {
+		c.clock = clock
+	}
+}
+
+// WithCacheIDs sets the generator used to give cache hits their own command
+// ID (default RandomIDs)
+func WithCacheIDs(ids IDGenerator) CacheOption {
+	return func(c *CachingProcessor) {
+		c.ids = ids
+	}
+}
+
+// WithStaleOnError keeps expired entries for maxStale and serves them, flagged
+// Stale, when the wrapped processor fails. Only read-only intents (see
+// IsReadOnly) are served stale, so "show positions" keeps working during a
+// provider outage while trading commands still fail.
+func WithStaleOnError(maxStale time.Duration) CacheOption {
+	return func(c *CachingProcessor) {
+		c.staleFor = maxStale
+	}
+}
+
+// NewCachingProcessor wraps next with a result cache.
+// A ttl <= 0 keeps entries until they are evicted, and maxEntries <= 0
+// disables the size bound. When full, the least recently used entry is evicted.
+func NewCachingProcessor(next Processor, ttl time.Duration, maxEntries int, opts ...CacheOption) *CachingProcessor {
+	c := &CachingProcessor{
+		next:       next,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		clock:      SystemClock{},
+		ids:        RandomIDs{},
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Name returns the wrapped processor name
+func (c *CachingProcessor) Name() string {
+	return c.next.Name()
+}
+
+// SupportedLanguages returns the wrapped processor languages
+func (c *CachingProcessor) SupportedLanguages() []string {
+	return c.next.SupportedLanguages()
+}
+
+// ParseCommand returns a cached result for input when available, otherwise
+// delegates to the wrapped processor and caches its result
+func (c *CachingProcessor) ParseCommand(ctx context.Context, input string) (*NormalizedCommand, error) {
+	key := cacheKey(UserIDFromContext(ctx), input, ParseOptionsFromContext(ctx))
+
+	if cmd, ok := c.get(key); ok {
+		return c.hit(cmd, input), nil
+	}
+
+	cmd, err := c.next.ParseCommand(ctx, input)
+	if err != nil {
+		if ctx.Err() == nil {
+			if stale, ok := c.getStale(key); ok {
+				stale = c.hit(stale, input)
+				stale.Stale = true
+				return stale, nil
+			}
+		}
+		return nil, err
+	}
+
+	c.put(key, cmd)
+	return cmd, nil
+}
+
+// hit stamps a cached command as a new command for input
+func (c *CachingProcessor) hit(cmd *NormalizedCommand, input string) *NormalizedCommand {
+	// A hit is a new command: it must not share the cached command's ID
+	if cmd.ID != "" {
+		cmd.ID = c.ids.NewID()
+	}
+	cmd.RawInput = input
+	cmd.Timestamp = c.clock.Now()
+	return cmd
+}
+
+// Unwrap returns the wrapped processor
+func (c *CachingProcessor) Unwrap() Processor {
+	return c.next
+}
+
+// Len returns the number of cached entries, including expired ones not yet evicted
+func (c *CachingProcessor) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Purge removes all cached entries
+func (c *CachingProcessor) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+func (c *CachingProcessor) get(key string) (*NormalizedCommand, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if c.ttl > 0 && c.clock.Now().After(entry.expires) {
+		// Keep the entry while it may still be served stale
+		if c.staleFor <= 0 || c.clock.Now().After(entry.expires.Add(c.staleFor)) {
+			c.order.Remove(elem)
+			delete(c.entries, key)
+		}
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.cmd.Clone(), true
+}
+
+// getStale returns the entry for key, expired or not, if it may be served
+// after a backend failure
+func (c *CachingProcessor) getStale(key string) (*NormalizedCommand, bool) {
+	if c.staleFor <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if !IsReadOnly(entry.cmd.Intent) {
+		return nil, false
+	}
+	if c.ttl > 0 && c.clock.Now().After(entry.expires.Add(c.staleFor)) {
+		return nil, false
+	}
+	return entry.cmd.Clone(), true
+}
+
+func (c *CachingProcessor) put(key string, cmd *NormalizedCommand) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{
+		key:     key,
+		cmd:     cmd.Clone(),
+		expires: c.clock.Now().Add(c.ttl),
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// cacheKey normalizes input so that case and whitespace differences hit the
+// same entry, scoped to userID and to the options that affect the result
+func cacheKey(userID, input string, opts ParseOptions) string {
+	var b strings.Builder
+	b.WriteString(userID)
+	b.WriteByte(0)
+	b.WriteString(strings.ToLower(strings.Join(strings.Fields(input), " ")))
+	b.WriteByte(0)
+	if !opts.ReferenceTime.IsZero() {
+		b.WriteString(opts.ReferenceTime.Format(time.RFC3339Nano))
+	}
+	b.WriteByte(0)
+	b.WriteString(opts.Timezone)
+	b.WriteByte(0)
+	b.WriteString(opts.Locale)
+	b.WriteByte(0)
+	if opts.Coords != nil {
+		b.WriteString(strconv.FormatFloat(opts.Coords.Lat, 'g', -1, 64))
+		b.WriteByte(',')
+		b.WriteString(strconv.FormatFloat(opts.Coords.Long, 'g', -1, 64))
+	}
+	for _, name := range slices.Sorted(maps.Keys(opts.DynamicEntities)) {
+		b.WriteByte(0)
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(strings.Join(slices.Sorted(slices.Values(opts.DynamicEntities[name])), ","))
+	}
+	return b.String()
+}
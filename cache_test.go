@@ -0,0 +1,291 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubProcessor returns a fixed command per call and counts invocations
+type stubProcessor struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (s *stubProcessor) Name() string                 { return "stub" }
+func (s *stubProcessor) SupportedLanguages() []string { return []string{"en"} }
+
+func (s *stubProcessor) ParseCommand(ctx context.Context, input string) (*NormalizedCommand, error) {
+	s.calls.Add(1)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &NormalizedCommand{
+		Intent:     IntentViewPositions,
+		Confidence: 0.9,
+		RawInput:   input,
+		Timestamp:  time.Now(),
+		Valid:      true,
+		Missing:    []string{},
+		Errors:     []string{},
+	}, nil
+}
+
+func TestCachingProcessor_NormalizedKeys(t *testing.T) {
+	stub := &stubProcessor{}
+	cache := NewCachingProcessor(stub, time.Minute, 10)
+	ctx := context.Background()
+
+	inputs := []string{"show my positions", "Show my  positions", "  SHOW MY POSITIONS\t"}
+	for _, input := range inputs {
+		cmd, err := cache.ParseCommand(ctx, input)
+		if err != nil {
+			t.Fatalf("ParseCommand(%q) error: %v", input, err)
+		}
+		if cmd.RawInput != input {
+			t.Errorf("RawInput = %q, want %q", cmd.RawInput, input)
+		}
+	}
+
+	if got := stub.calls.Load(); got != 1 {
+		t.Errorf("backend calls = %d, want 1", got)
+	}
+}
+
+func TestCachingProcessor_ReturnsCopies(t *testing.T) {
+	stub := &stubProcessor{}
+	cache := NewCachingProcessor(stub, time.Minute, 10)
+	ctx := context.Background()
+
+	first, _ := cache.ParseCommand(ctx, "show my positions")
+	first.Intent = IntentUnknown
+	first.Errors = append(first.Errors, "mutated")
+
+	second, _ := cache.ParseCommand(ctx, "show my positions")
+	if second.Intent != IntentViewPositions {
+		t.Errorf("Intent = %v, want %v", second.Intent, IntentViewPositions)
+	}
+	if len(second.Errors) != 0 {
+		t.Errorf("Errors = %v, want empty", second.Errors)
+	}
+}
+
+func TestCachingProcessor_TTL(t *testing.T) {
+	stub := &stubProcessor{}
+	cache := NewCachingProcessor(stub, 10*time.Millisecond, 10)
+	ctx := context.Background()
+
+	cache.ParseCommand(ctx, "check balance")
+	time.Sleep(20 * time.Millisecond)
+	cache.ParseCommand(ctx, "check balance")
+
+	if got := stub.calls.Load(); got != 2 {
+		t.Errorf("backend calls = %d, want 2", got)
+	}
+}
+
+func TestCachingProcessor_MaxEntries(t *testing.T) {
+	stub := &stubProcessor{}
+	cache := NewCachingProcessor(stub, time.Minute, 2)
+	ctx := context.Background()
+
+	cache.ParseCommand(ctx, "a")
+	cache.ParseCommand(ctx, "b")
+	cache.ParseCommand(ctx, "a") // a becomes most recently used
+	cache.ParseCommand(ctx, "c") // evicts b
+
+	if got := cache.Len(); got != 2 {
+		t.Errorf("Len = %d, want 2", got)
+	}
+
+	cache.ParseCommand(ctx, "a")
+	if got := stub.calls.Load(); got != 3 {
+		t.Errorf("backend calls = %d, want 3 (a should still be cached)", got)
+	}
+
+	cache.ParseCommand(ctx, "b")
+	if got := stub.calls.Load(); got != 4 {
+		t.Errorf("backend calls = %d, want 4 (b should have been evicted)", got)
+	}
+}
+
+func TestCachingProcessor_ErrorsNotCached(t *testing.T) {
+	stub := &stubProcessor{err: errors.New("backend down")}
+	cache := NewCachingProcessor(stub, time.Minute, 10)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.ParseCommand(ctx, "show my positions"); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+
+	if got := stub.calls.Load(); got != 2 {
+		t.Errorf("backend calls = %d, want 2", got)
+	}
+	if got := cache.Len(); got != 0 {
+		t.Errorf("Len = %d, want 0", got)
+	}
+}
+
+// manualClock is a Clock tests move forward explicitly
+type manualClock struct {
+	now time.Time
+}
+
+func (m *manualClock) Now() time.Time { return m.now }
+
+func TestCachingProcessor_InjectedClockAndIDs(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := &manualClock{now: start}
+	stub := &idProcessor{}
+	cache := NewCachingProcessor(stub, time.Minute, 10,
+		WithCacheClock(clock),
+		WithCacheIDs(&SequentialIDs{Prefix: "hit-"}),
+	)
+	ctx := context.Background()
+
+	first, _ := cache.ParseCommand(ctx, "check balance")
+
+	clock.now = start.Add(30 * time.Second)
+	hit, _ := cache.ParseCommand(ctx, "check balance")
+	if hit.ID != "hit-1" {
+		t.Errorf("hit ID = %q, want %q", hit.ID, "hit-1")
+	}
+	if hit.ID == first.ID {
+		t.Error("cache hit shares the ID of the cached command")
+	}
+	if !hit.Timestamp.Equal(clock.now) {
+		t.Errorf("hit Timestamp = %v, want %v", hit.Timestamp, clock.now)
+	}
+
+	clock.now = start.Add(2 * time.Minute)
+	cache.ParseCommand(ctx, "check balance")
+	if got := stub.calls; got != 2 {
+		t.Errorf("backend calls = %d, want 2 after expiry", got)
+	}
+}
+
+// idProcessor returns commands carrying backend-assigned IDs
+type idProcessor struct {
+	calls int
+}
+
+func (p *idProcessor) Name() string                 { return "ids" }
+func (p *idProcessor) SupportedLanguages() []string { return []string{"en"} }
+
+func (p *idProcessor) ParseCommand(ctx context.Context, input string) (*NormalizedCommand, error) {
+	p.calls++
+	return &NormalizedCommand{ID: fmt.Sprintf("backend-%d", p.calls), Intent: IntentCheckBalance, RawInput: input}, nil
+}
+
+func TestCachingProcessor_ScopedPerUser(t *testing.T) {
+	stub := &stubProcessor{}
+	cache := NewCachingProcessor(stub, time.Minute, 10)
+
+	alice := WithUser(context.Background(), User{ID: "alice"})
+	bob := WithUser(context.Background(), User{ID: "bob"})
+
+	cache.ParseCommand(alice, "show my positions")
+	cache.ParseCommand(alice, "show my positions")
+	cache.ParseCommand(bob, "show my positions")
+
+	if got := stub.calls.Load(); got != 2 {
+		t.Errorf("backend calls = %d, want 2 (one per user)", got)
+	}
+}
+
+func TestCachingProcessor_ScopedPerParseOptions(t *testing.T) {
+	stub := &stubProcessor{}
+	cache := NewCachingProcessor(stub, time.Minute, 10)
+	ref := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	base := context.Background()
+
+	contexts := []context.Context{
+		base,
+		WithParseOptions(base, WithReferenceTime(ref)),
+		WithParseOptions(base, WithReferenceTime(ref.Add(24*time.Hour))),
+		WithParseOptions(base, WithTimezone("America/Argentina/Buenos_Aires")),
+		WithParseOptions(base, WithLocale("es_AR")),
+		WithParseOptions(base, WithCoordinates(-34.6, -58.4)),
+		WithParseOptions(base, WithDynamicEntities(map[string][]string{"symbol": {"PEPE"}})),
+		WithParseOptions(base, WithDynamicEntities(map[string][]string{"symbol": {"WIF"}})),
+	}
+	for _, ctx := range contexts {
+		cache.ParseCommand(ctx, "close at 5pm tomorrow")
+	}
+	if got := stub.calls.Load(); got != int32(len(contexts)) {
+		t.Errorf("backend calls = %d, want %d (one per option set)", got, len(contexts))
+	}
+
+	// Repeating an option set hits its entry
+	cache.ParseCommand(WithParseOptions(base, WithReferenceTime(ref)), "close at 5pm tomorrow")
+	cache.ParseCommand(WithParseOptions(base, WithDynamicEntities(map[string][]string{"symbol": {"PEPE"}})), "Close at 5pm  tomorrow")
+	if got := stub.calls.Load(); got != int32(len(contexts)) {
+		t.Errorf("backend calls after repeats = %d, want %d", got, len(contexts))
+	}
+}
+
+func TestCachingProcessor_StaleOnError(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := &manualClock{now: start}
+	stub := &stubProcessor{}
+	cache := NewCachingProcessor(stub, time.Minute, 10, WithCacheClock(clock), WithStaleOnError(time.Hour))
+	ctx := context.Background()
+
+	if _, err := cache.ParseCommand(ctx, "show my positions"); err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	// Expired but within the stale window: served, flagged, when the backend fails
+	stub.err = errors.New("backend down")
+	clock.now = start.Add(30 * time.Minute)
+	cmd, err := cache.ParseCommand(ctx, "Show my positions")
+	if err != nil {
+		t.Fatalf("stale ParseCommand error: %v", err)
+	}
+	if !cmd.Stale || cmd.Intent != IntentViewPositions {
+		t.Errorf("got Stale = %v, Intent = %v, want a stale view_positions", cmd.Stale, cmd.Intent)
+	}
+	if cmd.RawInput != "Show my positions" || !cmd.Timestamp.Equal(clock.now) {
+		t.Errorf("stale command not stamped for this input: %q at %v", cmd.RawInput, cmd.Timestamp)
+	}
+
+	// Backend recovered: a fresh result replaces the stale entry
+	stub.err = nil
+	cmd, _ = cache.ParseCommand(ctx, "show my positions")
+	if cmd.Stale {
+		t.Error("fresh result flagged Stale")
+	}
+
+	// Past the stale window the error surfaces
+	stub.err = errors.New("backend down")
+	clock.now = clock.now.Add(2 * time.Hour)
+	if _, err := cache.ParseCommand(ctx, "show my positions"); err == nil {
+		t.Error("expected error past the stale window")
+	}
+}
+
+func TestCachingProcessor_StaleOnlyForReadOnly(t *testing.T) {
+	var backendErr error
+	p := WrapParse(&stubProcessor{}, func(ctx context.Context, input string) (*NormalizedCommand, error) {
+		if backendErr != nil {
+			return nil, backendErr
+		}
+		return &NormalizedCommand{Intent: IntentClosePosition, Symbol: "BTC-USDT", RawInput: input}, nil
+	})
+	clock := &manualClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	cache := NewCachingProcessor(p, time.Minute, 10, WithCacheClock(clock), WithStaleOnError(time.Hour))
+	ctx := context.Background()
+
+	cache.ParseCommand(ctx, "close BTC")
+
+	backendErr = errors.New("backend down")
+	clock.now = clock.now.Add(2 * time.Minute)
+	if _, err := cache.ParseCommand(ctx, "close BTC"); !errors.Is(err, backendErr) {
+		t.Errorf("error = %v, want the backend error for a trading intent", err)
+	}
+}
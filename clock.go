@@ -0,0 +1,75 @@
+package intent
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock provides the current time, so timestamps can be made deterministic
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is a Clock backed by time.Now
+type SystemClock struct{}
+
+// Now returns the current wall-clock time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// StepClock is a deterministic Clock that starts at a fixed instant and
+// advances by a fixed step on every call. A zero step always returns start.
+type StepClock struct {
+	mu   sync.Mutex
+	next time.Time
+	step time.Duration
+}
+
+// NewStepClock returns a StepClock starting at start
+func NewStepClock(start time.Time, step time.Duration) *StepClock {
+	return &StepClock{next: start, step: step}
+}
+
+// Now returns the current instant and advances the clock
+func (c *StepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.next
+	c.next = c.next.Add(c.step)
+	return now
+}
+
+// IDGenerator assigns identifiers to parsed commands
+type IDGenerator interface {
+	NewID() string
+}
+
+// RandomIDs generates random (version 4) UUIDs
+type RandomIDs struct{}
+
+// NewID returns a new random UUID
+func (RandomIDs) NewID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("intent: reading random bytes: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// SequentialIDs is a deterministic IDGenerator returning Prefix followed by
+// an increasing counter ("cmd-1", "cmd-2", ...)
+type SequentialIDs struct {
+	Prefix string
+	n      atomic.Uint64
+}
+
+// NewID returns the next identifier in the sequence
+func (s *SequentialIDs) NewID() string {
+	return fmt.Sprintf("%s%d", s.Prefix, s.n.Add(1))
+}
@@ -0,0 +1,46 @@
+package intent
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestStepClock(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewStepClock(start, time.Second)
+
+	for i := 0; i < 3; i++ {
+		want := start.Add(time.Duration(i) * time.Second)
+		if got := clock.Now(); !got.Equal(want) {
+			t.Errorf("Now() #%d = %v, want %v", i, got, want)
+		}
+	}
+
+	fixed := NewStepClock(start, 0)
+	fixed.Now()
+	if got := fixed.Now(); !got.Equal(start) {
+		t.Errorf("zero-step Now() = %v, want %v", got, start)
+	}
+}
+
+func TestSequentialIDs(t *testing.T) {
+	ids := &SequentialIDs{Prefix: "cmd-"}
+	for _, want := range []string{"cmd-1", "cmd-2", "cmd-3"} {
+		if got := ids.NewID(); got != want {
+			t.Errorf("NewID() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestRandomIDs(t *testing.T) {
+	uuidV4 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	a, b := RandomIDs{}.NewID(), RandomIDs{}.NewID()
+	if !uuidV4.MatchString(a) {
+		t.Errorf("NewID() = %q, want a version 4 UUID", a)
+	}
+	if a == b {
+		t.Errorf("NewID() returned %q twice", a)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// Corpus generates mixed-language trading commands with varied symbols and prices
+type Corpus struct {
+	rng     *rand.Rand
+	spanish float64
+}
+
+// NewCorpus returns a corpus producing Spanish commands with probability spanish
+func NewCorpus(rng *rand.Rand, spanish float64) *Corpus {
+	return &Corpus{rng: rng, spanish: spanish}
+}
+
+type symbolPrice struct {
+	name  string
+	price float64
+}
+
+var corpusSymbols = []symbolPrice{
+	{"BTC", 45000}, {"bitcoin", 45000}, {"ETH", 3000}, {"ethereum", 3000},
+	{"SOL", 150}, {"solana", 150}, {"DOGE", 0.15}, {"ADA", 0.5},
+}
+
+// Templates take a symbol, an entry price and a stop price, in that order.
+// Read-only commands are weighted higher, as in real chat traffic.
+var (
+	englishTemplates = []string{
+		"open long %[1]s at %.2[2]f with stop loss %.2[3]f and risk 1%%",
+		"short %[1]s at %.2[2]f stop %.2[3]f risk 2%%",
+		"close 50%% of %[1]s",
+		"move my %[1]s stop to %.2[3]f",
+		"set %[1]s leverage to 10x",
+		"DCA into %[1]s at %.2[2]f, %.2[3]f",
+		"show my positions",
+		"show my positions",
+		"what's my balance",
+		"show open orders for %[1]s",
+	}
+	spanishTemplates = []string{
+		"abrir largo %[1]s en %.2[2]f con stop loss %.2[3]f y riesgo 1%%",
+		"vender %[1]s en %.2[2]f stop %.2[3]f riesgo 2%%",
+		"cerrar 50%% de %[1]s",
+		"mover el stop de %[1]s a %.2[3]f",
+		"poner apalancamiento 10x en %[1]s",
+		"mostrar mis posiciones",
+		"mostrar mis posiciones",
+		"cuál es mi saldo",
+		"cancelar todas las órdenes",
+	}
+)
+
+// Next returns the next command
+func (c *Corpus) Next() string {
+	templates := englishTemplates
+	if c.rng.Float64() < c.spanish {
+		templates = spanishTemplates
+	}
+
+	sym := corpusSymbols[c.rng.IntN(len(corpusSymbols))]
+	entry := sym.price * (0.95 + 0.1*c.rng.Float64())
+	stop := entry * (0.97 + 0.02*c.rng.Float64())
+
+	tmpl := templates[c.rng.IntN(len(templates))]
+	if !strings.Contains(tmpl, "%[") {
+		return tmpl
+	}
+	return fmt.Sprintf(tmpl, sym.name, entry, stop)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{50, 50 * time.Millisecond},
+		{90, 90 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile(nil) = %v, want 0", got)
+	}
+}
+
+func TestCorpus_MixesLanguages(t *testing.T) {
+	c := NewCorpus(rand.New(rand.NewPCG(1, 1)), 0.5)
+
+	var spanish int
+	for range 200 {
+		cmd := c.Next()
+		if strings.Contains(cmd, "%!") {
+			t.Fatalf("malformed command %q", cmd)
+		}
+		for _, tmpl := range spanishTemplates {
+			if strings.HasPrefix(tmpl, strings.Fields(cmd)[0]) {
+				spanish++
+				break
+			}
+		}
+	}
+	if spanish == 0 || spanish == 200 {
+		t.Errorf("spanish commands = %d of 200, want a mix", spanish)
+	}
+}
+
+type countingProcessor struct {
+	calls atomic.Int64
+}
+
+func (p *countingProcessor) ParseCommand(ctx context.Context, input string) (*intent.NormalizedCommand, error) {
+	if p.calls.Add(1)%10 == 0 {
+		return nil, errors.New("backend error")
+	}
+	return &intent.NormalizedCommand{Intent: intent.IntentViewPositions, Valid: true}, nil
+}
+
+func (p *countingProcessor) Name() string                 { return "counting" }
+func (p *countingProcessor) SupportedLanguages() []string { return []string{"en", "es"} }
+
+func TestRun(t *testing.T) {
+	p := &countingProcessor{}
+	corpus := NewCorpus(rand.New(rand.NewPCG(1, 1)), 0.4)
+
+	report := run(p, corpus, 100, 4, time.Second)
+
+	if report.Total != 100 || p.calls.Load() != 100 {
+		t.Errorf("Total = %d, calls = %d, want 100", report.Total, p.calls.Load())
+	}
+	if report.Errors != 10 {
+		t.Errorf("Errors = %d, want 10", report.Errors)
+	}
+	if report.Intents[intent.IntentViewPositions] != 90 {
+		t.Errorf("Intents = %v, want 90 view_positions", report.Intents)
+	}
+}
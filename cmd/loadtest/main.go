@@ -0,0 +1,93 @@
+// Command loadtest drives a Wit.ai processor with realistic mixed-language
+// command traffic and reports Wit.ai processor latency percentiles. It
+// calls the processor in-process: there are no HTTP or gRPC servers in
+// this module, so the numbers measure the Wit.ai client and backend, not a
+// deployment, and are no basis for sizing one.
+//
+// Usage:
+//
+//	WIT_AI_TOKEN=... go run ./cmd/loadtest -n 2000 -c 16
+//	go run ./cmd/loadtest -base-url http://localhost:8080 -n 10000 -c 64
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/witai"
+)
+
+func main() {
+	var (
+		token    = flag.String("token", os.Getenv("WIT_AI_TOKEN"), "Wit.ai token (default $WIT_AI_TOKEN)")
+		baseURL  = flag.String("base-url", "", "Wit.ai compatible endpoint, e.g. a fake server")
+		requests = flag.Int("n", 1000, "total number of commands")
+		workers  = flag.Int("c", 8, "concurrent workers")
+		spanish  = flag.Float64("es", 0.4, "fraction of Spanish commands")
+		seed     = flag.Uint64("seed", 1, "corpus random seed")
+		timeout  = flag.Duration("timeout", 10*time.Second, "per-command timeout")
+	)
+	flag.Parse()
+
+	if *token == "" {
+		if *baseURL == "" {
+			fmt.Fprintln(os.Stderr, "loadtest: set -token or $WIT_AI_TOKEN, or point -base-url at a fake server")
+			os.Exit(2)
+		}
+		*token = "loadtest"
+	}
+
+	opts := []witai.Option{witai.WithConcurrency(*workers)}
+	if *baseURL != "" {
+		opts = append(opts, witai.WithBaseURL(*baseURL))
+	}
+	processor, err := witai.New(*token, opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loadtest: %v\n", err)
+		os.Exit(1)
+	}
+
+	corpus := NewCorpus(rand.New(rand.NewPCG(*seed, *seed)), *spanish)
+	report := run(processor, corpus, *requests, *workers, *timeout)
+	report.Print(os.Stdout)
+
+	if report.Errors > 0 {
+		os.Exit(1)
+	}
+}
+
+// run sends n commands from corpus through processor using workers goroutines
+func run(processor intent.Processor, corpus *Corpus, n, workers int, timeout time.Duration) *Report {
+	inputs := make(chan string)
+	go func() {
+		defer close(inputs)
+		for range n {
+			inputs <- corpus.Next()
+		}
+	}()
+
+	rec := NewRecorder(n)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Go(func() {
+			for input := range inputs {
+				ctx := intent.WithParseOptions(context.Background(), intent.WithTimeout(timeout))
+
+				began := time.Now()
+				cmd, err := processor.ParseCommand(ctx, input)
+				rec.Record(time.Since(began), cmd, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	return rec.Report(time.Since(start))
+}
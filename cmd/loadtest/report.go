@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// Recorder collects per-command latencies and outcomes; safe for concurrent use
+type Recorder struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	intents   map[intent.Intent]int
+	invalid   int
+	errors    int
+}
+
+// NewRecorder returns a Recorder sized for n commands
+func NewRecorder(n int) *Recorder {
+	return &Recorder{
+		latencies: make([]time.Duration, 0, n),
+		intents:   make(map[intent.Intent]int),
+	}
+}
+
+// Record adds one command result
+func (r *Recorder) Record(latency time.Duration, cmd *intent.NormalizedCommand, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.latencies = append(r.latencies, latency)
+	switch {
+	case err != nil:
+		r.errors++
+	case !cmd.Valid:
+		r.invalid++
+		r.intents[cmd.Intent]++
+	default:
+		r.intents[cmd.Intent]++
+	}
+}
+
+// Report summarizes the recorded commands over the elapsed wall time
+func (r *Recorder) Report(elapsed time.Duration) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sorted := slices.Clone(r.latencies)
+	slices.Sort(sorted)
+
+	return &Report{
+		Total:   len(sorted),
+		Errors:  r.errors,
+		Invalid: r.invalid,
+		Elapsed: elapsed,
+		P50:     percentile(sorted, 50),
+		P90:     percentile(sorted, 90),
+		P99:     percentile(sorted, 99),
+		Max:     percentile(sorted, 100),
+		Intents: maps.Clone(r.intents),
+	}
+}
+
+// Report is the outcome of a load test run
+type Report struct {
+	Total, Errors, Invalid int
+	Elapsed                time.Duration
+	P50, P90, P99, Max     time.Duration
+	Intents                map[intent.Intent]int
+}
+
+// Throughput returns commands per second
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Total) / r.Elapsed.Seconds()
+}
+
+// Print writes a human-readable summary to w
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "commands:   %d in %s (%.1f/s)\n", r.Total, r.Elapsed.Round(time.Millisecond), r.Throughput())
+	fmt.Fprintf(w, "errors:     %d\n", r.Errors)
+	fmt.Fprintf(w, "invalid:    %d\n", r.Invalid)
+	fmt.Fprintf(w, "latency:    p50 %s  p90 %s  p99 %s  max %s\n", r.P50, r.P90, r.P99, r.Max)
+
+	names := make([]string, 0, len(r.Intents))
+	for i := range r.Intents {
+		names = append(names, string(i))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-20s %d\n", name, r.Intents[intent.Intent(name)])
+	}
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return sorted[rank-1]
+}
@@ -0,0 +1,76 @@
+package intent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// clauseSeparator matches the English and Spanish conjunctions that chain
+// commands, e.g. "and" in "open long BTC and set an alert at 47000", and
+// the question mark ending a question followed by a command
+var clauseSeparator = regexp.MustCompile(`(?i)\s*[,;]?\s+(?:and then|and|then|y luego|y después|luego|y)\s+|\s*;\s*|\?\s+`)
+
+// SplitClauses splits a compound utterance into its candidate commands at
+// conjunctions ("and", "then", "y", "luego"), semicolons and question marks. The pieces
+// aren't necessarily commands: "TP at 46000 and 47000" splits too, so
+// callers only use them when the backend reports several intents and each
+// piece parses on its own. Input without a separator is returned as the
+// only clause.
+func SplitClauses(input string) []string {
+	var clauses []string
+	for _, clause := range clauseSeparator.Split(input, -1) {
+		if clause = strings.TrimSpace(clause); clause != "" {
+			clauses = append(clauses, clause)
+		}
+	}
+	return clauses
+}
+
+// LinkDependencies links the commands of a compound utterance that act on
+// an earlier question, e.g. in "what's BTC at? open a long if it's under
+// 45k" the open_position depends on the view_price. A command depends on
+// the latest earlier read-only command with a symbol when it names the same
+// symbol or none; in the latter case it refers to the question's subject
+// ("it") and takes its symbol. Only commands that act on one symbol are
+// linked: account-wide ones such as close_all, cancel_orders or withdraw
+// keep their scope. Commands must have IDs.
+func LinkDependencies(cmds []*NormalizedCommand) {
+	for i, cmd := range cmds {
+		if !actsOnSymbol(cmd.Intent) {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			question := cmds[j]
+			if !IsReadOnly(question.Intent) || question.Symbol == "" {
+				continue
+			}
+			if cmd.Symbol == "" {
+				cmd.Symbol = question.Symbol
+			}
+			if cmd.Symbol == question.Symbol {
+				cmd.DependsOn = append(cmd.DependsOn, question.ID)
+			}
+			break
+		}
+	}
+}
+
+// actsOnSymbol reports whether i needs a symbol to be executed
+func actsOnSymbol(i Intent) bool {
+	switch i {
+	case IntentOpenPosition, IntentClosePosition, IntentSetTakeProfit, IntentSetStopLoss,
+		IntentModifyPosition, IntentMoveStopLoss, IntentBreakEven, IntentTrailingStop,
+		IntentSetLeverage, IntentDCAOrder, IntentSetAlert, IntentHedgePosition, IntentSetupGrid:
+		return true
+	}
+	return false
+}
+
+// Commands returns the command followed by its SubCommands, i.e. every
+// command of a compound utterance in the order they were given
+func (c *NormalizedCommand) Commands() []*NormalizedCommand {
+	if c == nil {
+		return nil
+	}
+	return append([]*NormalizedCommand{c}, c.SubCommands...)
+}
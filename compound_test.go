@@ -0,0 +1,144 @@
+package intent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitClauses(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"show my positions", []string{"show my positions"}},
+		{
+			"open long BTC at 45000 and set an alert at 47000",
+			[]string{"open long BTC at 45000", "set an alert at 47000"},
+		},
+		{
+			"close my ETH short, then show my balance",
+			[]string{"close my ETH short", "show my balance"},
+		},
+		{
+			"cerrá el long de BTC y luego mostrame el balance; cancelá las órdenes",
+			[]string{"cerrá el long de BTC", "mostrame el balance", "cancelá las órdenes"},
+		},
+		{
+			"what's BTC at? open a long if it's under 45k",
+			[]string{"what's BTC at", "open a long if it's under 45k"},
+		},
+		{"what's BTC at?", []string{"what's BTC at?"}},
+		{"Brandon bought ETH", []string{"Brandon bought ETH"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := SplitClauses(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitClauses(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizedCommand_Commands(t *testing.T) {
+	alert := &NormalizedCommand{Intent: IntentSetAlert}
+	cmd := &NormalizedCommand{Intent: IntentOpenPosition, SubCommands: []*NormalizedCommand{alert}}
+
+	got := cmd.Commands()
+	if len(got) != 2 || got[0] != cmd || got[1] != alert {
+		t.Errorf("Commands() = %v, want the command followed by its sub-command", got)
+	}
+
+	var missing *NormalizedCommand
+	if missing.Commands() != nil {
+		t.Error("Commands() of nil command should be nil")
+	}
+}
+
+func TestLinkDependencies(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmds       []*NormalizedCommand
+		wantSymbol string
+		wantDeps   []string
+	}{
+		{
+			name: "Command refers to the question",
+			cmds: []*NormalizedCommand{
+				{ID: "q", Intent: IntentViewPrice, Symbol: "BTC-USDT"},
+				{ID: "c", Intent: IntentOpenPosition, Condition: &PriceCondition{Operator: ConditionBelow}},
+			},
+			wantSymbol: "BTC-USDT",
+			wantDeps:   []string{"q"},
+		},
+		{
+			name: "Same symbol",
+			cmds: []*NormalizedCommand{
+				{ID: "q", Intent: IntentViewPosition, Symbol: "ETH-USDT"},
+				{ID: "c", Intent: IntentClosePosition, Symbol: "ETH-USDT"},
+			},
+			wantSymbol: "ETH-USDT",
+			wantDeps:   []string{"q"},
+		},
+		{
+			name: "Other symbol",
+			cmds: []*NormalizedCommand{
+				{ID: "q", Intent: IntentViewPrice, Symbol: "BTC-USDT"},
+				{ID: "c", Intent: IntentClosePosition, Symbol: "ETH-USDT"},
+			},
+			wantSymbol: "ETH-USDT",
+		},
+		{
+			name: "Close all keeps its scope",
+			cmds: []*NormalizedCommand{
+				{ID: "q", Intent: IntentViewPrice, Symbol: "BTC-USDT"},
+				{ID: "c", Intent: IntentCloseAll},
+			},
+		},
+		{
+			name: "Panic close keeps its scope",
+			cmds: []*NormalizedCommand{
+				{ID: "q", Intent: IntentViewPrice, Symbol: "BTC-USDT"},
+				{ID: "c", Intent: IntentPanicClose},
+			},
+		},
+		{
+			name: "Cancel all orders keeps its scope",
+			cmds: []*NormalizedCommand{
+				{ID: "q", Intent: IntentViewPrice, Symbol: "BTC-USDT"},
+				{ID: "c", Intent: IntentCancelOrders},
+			},
+		},
+		{
+			name: "Withdraw keeps its scope",
+			cmds: []*NormalizedCommand{
+				{ID: "q", Intent: IntentViewPrice, Symbol: "BTC-USDT"},
+				{ID: "c", Intent: IntentWithdraw, Asset: "USDT"},
+			},
+		},
+		{
+			name: "Two commands",
+			cmds: []*NormalizedCommand{
+				{ID: "a", Intent: IntentOpenPosition, Symbol: "BTC-USDT"},
+				{ID: "c", Intent: IntentSetAlert},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			LinkDependencies(tt.cmds)
+
+			last := tt.cmds[len(tt.cmds)-1]
+			if last.Symbol != tt.wantSymbol {
+				t.Errorf("Symbol = %q, want %q", last.Symbol, tt.wantSymbol)
+			}
+			if !reflect.DeepEqual(last.DependsOn, tt.wantDeps) {
+				t.Errorf("DependsOn = %v, want %v", last.DependsOn, tt.wantDeps)
+			}
+			if tt.cmds[0].DependsOn != nil {
+				t.Errorf("first command DependsOn = %v, want none", tt.cmds[0].DependsOn)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package intent
+
+// ConditionOperator compares the observed price against PriceCondition.Price
+type ConditionOperator string
+
+const (
+	ConditionAbove ConditionOperator = "above"
+	ConditionBelow ConditionOperator = "below"
+)
+
+// PriceCondition gates a command on the price of a symbol, e.g.
+// "if ETH/BTC breaks 0.06, close my ETH long"
+type PriceCondition struct {
+	// Symbol whose price is observed. It may differ from the command symbol;
+	// when empty the command symbol is used.
+	Symbol   string            `json:"symbol,omitempty"`
+	Operator ConditionOperator `json:"operator,omitempty"`
+	Price    *float64          `json:"price,omitempty"`
+}
+
+// ObservedSymbol returns the symbol the condition watches for a command on cmdSymbol
+func (c *PriceCondition) ObservedSymbol(cmdSymbol string) string {
+	if c.Symbol != "" {
+		return c.Symbol
+	}
+	return cmdSymbol
+}
+
+// Clone returns a deep copy of the condition
+func (c *PriceCondition) Clone() *PriceCondition {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.Price = clonePtr(c.Price)
+	return &clone
+}
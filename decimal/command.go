@@ -0,0 +1,196 @@
+package decimal
+
+import (
+	"fmt"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// TPLevel is a take-profit level with fixed-point price and percentage
+type TPLevel struct {
+	Price      Decimal `json:"price"`
+	Percentage Decimal `json:"percentage"`
+}
+
+// SLLevel is a stop-loss level with fixed-point price and percentage
+type SLLevel struct {
+	Price      Decimal `json:"price"`
+	Percentage Decimal `json:"percentage"`
+}
+
+// EntryLevel is a DCA ladder rung with fixed-point price and percentage
+type EntryLevel struct {
+	Price      Decimal `json:"price"`
+	Percentage Decimal `json:"percentage"`
+}
+
+// Command is a NormalizedCommand whose prices and percentages are Decimals.
+// Fields without a float representation are carried by the embedded command;
+// the Decimal fields shadow the float ones in JSON.
+type Command struct {
+	*intent.NormalizedCommand
+
+	EntryPrice   *Decimal  `json:"entry_price,omitempty"`
+	StopLoss     *Decimal  `json:"stop_loss,omitempty"`
+	TakeProfit   *Decimal  `json:"take_profit,omitempty"`
+	TriggerPrice *Decimal  `json:"trigger_price,omitempty"`
+	TPLevels     []TPLevel `json:"tp_levels,omitempty"`
+	SLLevels     []SLLevel `json:"sl_levels,omitempty"`
+
+	StopLossOffset *Decimal `json:"stop_loss_offset,omitempty"`
+
+	EntryLevels []EntryLevel `json:"entry_levels,omitempty"`
+
+	RiskPercent  *Decimal `json:"risk_percent,omitempty"`
+	ClosePercent *Decimal `json:"close_percent,omitempty"`
+	HedgeRatio   *Decimal `json:"hedge_ratio,omitempty"`
+	RRRatio      *Decimal `json:"rr_ratio,omitempty"`
+	Leverage     *Decimal `json:"leverage,omitempty"`
+	Quantity     *Decimal `json:"quantity,omitempty"`
+	NotionalUSD  *Decimal `json:"notional_usd,omitempty"`
+	CallbackRate *Decimal `json:"callback_rate,omitempty"`
+	Distance     *Decimal `json:"distance,omitempty"`
+	SizeFactor   *Decimal `json:"size_factor,omitempty"`
+
+	GridLower     *Decimal `json:"grid_lower,omitempty"`
+	GridUpper     *Decimal `json:"grid_upper,omitempty"`
+	GridLevelSize *Decimal `json:"grid_level_size,omitempty"`
+
+	AlertPrice *Decimal `json:"alert_price,omitempty"`
+	Amount     *Decimal `json:"amount,omitempty"`
+
+	// ConditionPrice is the fixed-point Condition.Price
+	ConditionPrice *Decimal `json:"condition_price,omitempty"`
+}
+
+// decimalField pairs a float field of NormalizedCommand with its Decimal counterpart
+type decimalField struct {
+	name string
+	f    **float64
+	d    **Decimal
+}
+
+func (c *Command) fields() []decimalField {
+	cmd := c.NormalizedCommand
+	fields := []decimalField{
+		{"entry_price", &cmd.EntryPrice, &c.EntryPrice},
+		{"stop_loss", &cmd.StopLoss, &c.StopLoss},
+		{"take_profit", &cmd.TakeProfit, &c.TakeProfit},
+		{"trigger_price", &cmd.TriggerPrice, &c.TriggerPrice},
+		{"stop_loss_offset", &cmd.StopLossOffset, &c.StopLossOffset},
+		{"risk_percent", &cmd.RiskPercent, &c.RiskPercent},
+		{"rr_ratio", &cmd.RRRatio, &c.RRRatio},
+		{"close_percent", &cmd.ClosePercent, &c.ClosePercent},
+		{"hedge_ratio", &cmd.HedgeRatio, &c.HedgeRatio},
+		{"leverage", &cmd.Leverage, &c.Leverage},
+		{"quantity", &cmd.Quantity, &c.Quantity},
+		{"notional_usd", &cmd.NotionalUSD, &c.NotionalUSD},
+		{"callback_rate", &cmd.CallbackRate, &c.CallbackRate},
+		{"distance", &cmd.Distance, &c.Distance},
+		{"size_factor", &cmd.SizeFactor, &c.SizeFactor},
+		{"grid_lower", &cmd.GridLower, &c.GridLower},
+		{"grid_upper", &cmd.GridUpper, &c.GridUpper},
+		{"grid_level_size", &cmd.GridLevelSize, &c.GridLevelSize},
+		{"alert_price", &cmd.AlertPrice, &c.AlertPrice},
+		{"amount", &cmd.Amount, &c.Amount},
+	}
+	if cmd.Condition != nil {
+		fields = append(fields, decimalField{"condition_price", &cmd.Condition.Price, &c.ConditionPrice})
+	}
+	return fields
+}
+
+// FromCommand converts the float fields of cmd to Decimals. cmd is cloned,
+// so the result doesn't alias it.
+func FromCommand(cmd *intent.NormalizedCommand) (*Command, error) {
+	c := &Command{NormalizedCommand: cmd.Clone()}
+
+	for _, field := range c.fields() {
+		if *field.f == nil {
+			continue
+		}
+		d, err := FromFloat(**field.f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.name, err)
+		}
+		*field.d = &d
+	}
+
+	for i, tp := range cmd.TPLevels {
+		price, err := FromFloat(tp.Price)
+		if err != nil {
+			return nil, fmt.Errorf("tp_levels[%d].price: %w", i, err)
+		}
+		pct, err := FromFloat(tp.Percentage)
+		if err != nil {
+			return nil, fmt.Errorf("tp_levels[%d].percentage: %w", i, err)
+		}
+		c.TPLevels = append(c.TPLevels, TPLevel{Price: price, Percentage: pct})
+	}
+
+	for i, sl := range cmd.SLLevels {
+		price, err := FromFloat(sl.Price)
+		if err != nil {
+			return nil, fmt.Errorf("sl_levels[%d].price: %w", i, err)
+		}
+		pct, err := FromFloat(sl.Percentage)
+		if err != nil {
+			return nil, fmt.Errorf("sl_levels[%d].percentage: %w", i, err)
+		}
+		c.SLLevels = append(c.SLLevels, SLLevel{Price: price, Percentage: pct})
+	}
+
+	for i, level := range cmd.EntryLevels {
+		price, err := FromFloat(level.Price)
+		if err != nil {
+			return nil, fmt.Errorf("entry_levels[%d].price: %w", i, err)
+		}
+		pct, err := FromFloat(level.Percentage)
+		if err != nil {
+			return nil, fmt.Errorf("entry_levels[%d].percentage: %w", i, err)
+		}
+		c.EntryLevels = append(c.EntryLevels, EntryLevel{Price: price, Percentage: pct})
+	}
+
+	return c, nil
+}
+
+// Float returns a NormalizedCommand with the float fields set from the Decimals
+func (c *Command) Float() *intent.NormalizedCommand {
+	out := &Command{NormalizedCommand: c.NormalizedCommand.Clone()}
+	src := c.fields()
+	for i, field := range out.fields() {
+		d := *src[i].d
+		if d == nil {
+			*field.f = nil
+			continue
+		}
+		f := d.Float64()
+		*field.f = &f
+	}
+
+	out.NormalizedCommand.TPLevels = nil
+	for _, tp := range c.TPLevels {
+		out.NormalizedCommand.TPLevels = append(out.NormalizedCommand.TPLevels, intent.TPLevel{
+			Price:      tp.Price.Float64(),
+			Percentage: tp.Percentage.Float64(),
+		})
+	}
+
+	out.NormalizedCommand.SLLevels = nil
+	for _, sl := range c.SLLevels {
+		out.NormalizedCommand.SLLevels = append(out.NormalizedCommand.SLLevels, intent.SLLevel{
+			Price:      sl.Price.Float64(),
+			Percentage: sl.Percentage.Float64(),
+		})
+	}
+
+	out.NormalizedCommand.EntryLevels = nil
+	for _, level := range c.EntryLevels {
+		out.NormalizedCommand.EntryLevels = append(out.NormalizedCommand.EntryLevels, intent.EntryLevel{
+			Price:      level.Price.Float64(),
+			Percentage: level.Percentage.Float64(),
+		})
+	}
+	return out.NormalizedCommand
+}
@@ -0,0 +1,193 @@
+// Package decimal offers a fixed-point representation of commands, so prices
+// and percentages survive serialization and conversion to exchange APIs
+// without float drift
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MaxScale is the largest number of fractional digits a Decimal holds
+const MaxScale = 18
+
+// Decimal is the fixed-point number units / 10^scale. The zero value is 0.
+type Decimal struct {
+	units int64
+	scale int32
+}
+
+// ErrRange is returned when a value doesn't fit a Decimal
+var ErrRange = errors.New("decimal: value out of range")
+
+// New returns units / 10^scale. A negative scale multiplies units by 10^-scale.
+func New(units int64, scale int32) Decimal {
+	for ; scale < 0; scale++ {
+		units *= 10
+	}
+	return Decimal{units: units, scale: scale}.normalize()
+}
+
+// Parse parses a plain decimal string such as "-45000.25"
+func Parse(s string) (Decimal, error) {
+	str := s
+	neg := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(strings.TrimPrefix(str, "-"), "+")
+
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	if intPart == "" && fracPart == "" {
+		return Decimal{}, fmt.Errorf("decimal: invalid syntax %q", s)
+	}
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return Decimal{}, fmt.Errorf("decimal: invalid syntax %q", s)
+		}
+	}
+
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > MaxScale {
+		return Decimal{}, fmt.Errorf("%w: %q has more than %d fractional digits", ErrRange, s, MaxScale)
+	}
+
+	digits := strings.TrimLeft(intPart+fracPart, "0")
+	if digits == "" {
+		return Decimal{}, nil
+	}
+	units, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrRange, s)
+	}
+	if neg {
+		units = -units
+	}
+	return Decimal{units: units, scale: int32(len(fracPart))}, nil
+}
+
+// FromFloat converts f using its shortest decimal representation, so 0.1
+// becomes exactly 0.1 rather than the nearest binary fraction
+func FromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("%w: %v", ErrRange, f)
+	}
+	return Parse(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// Units returns the unscaled integer value
+func (d Decimal) Units() int64 { return d.units }
+
+// Scale returns the number of fractional digits
+func (d Decimal) Scale() int32 { return d.scale }
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool { return d.units == 0 }
+
+// Float64 returns the nearest float64
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d without exponent, e.g. "45000.25"
+func (d Decimal) String() string {
+	s := strconv.FormatInt(d.units, 10)
+	if d.scale <= 0 {
+		return s
+	}
+
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if pad := int(d.scale) + 1 - len(s); pad > 0 {
+		s = strings.Repeat("0", pad) + s
+	}
+	s = s[:len(s)-int(d.scale)] + "." + s[len(s)-int(d.scale):]
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// Cmp compares d and other and returns -1, 0 or +1
+func (d Decimal) Cmp(other Decimal) int {
+	a, b := d.aligned(other)
+	return a.Cmp(b)
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) (Decimal, error) {
+	a, b := d.aligned(other)
+	return fromBig(a.Add(a, b), max(d.scale, other.scale))
+}
+
+// Ticks returns d as an integer number of tick increments, e.g. 45000.5
+// with tick 0.1 is 450005. It fails when d is not a multiple of tick.
+func (d Decimal) Ticks(tick Decimal) (int64, error) {
+	if tick.units <= 0 {
+		return 0, fmt.Errorf("decimal: tick must be positive, got %s", tick)
+	}
+
+	a, b := d.aligned(tick)
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if r.Sign() != 0 {
+		return 0, fmt.Errorf("decimal: %s is not a multiple of tick %s", d, tick)
+	}
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("%w: %s ticks of %s", ErrRange, d, tick)
+	}
+	return q.Int64(), nil
+}
+
+// MarshalJSON encodes d as a JSON string to keep every digit
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON accepts a JSON string or number
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// aligned returns the unscaled values of d and other at their common scale
+func (d Decimal) aligned(other Decimal) (*big.Int, *big.Int) {
+	scale := max(d.scale, other.scale)
+	return d.bigAt(scale), other.bigAt(scale)
+}
+
+func (d Decimal) bigAt(scale int32) *big.Int {
+	v := big.NewInt(d.units)
+	if scale > d.scale {
+		v.Mul(v, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale-d.scale)), nil))
+	}
+	return v
+}
+
+func fromBig(v *big.Int, scale int32) (Decimal, error) {
+	if !v.IsInt64() {
+		return Decimal{}, ErrRange
+	}
+	return Decimal{units: v.Int64(), scale: scale}.normalize(), nil
+}
+
+// normalize strips trailing fractional zeros so equal values share one representation
+func (d Decimal) normalize() Decimal {
+	if d.units == 0 {
+		return Decimal{}
+	}
+	for d.scale > 0 && d.units%10 == 0 {
+		d.units /= 10
+		d.scale--
+	}
+	return d
+}
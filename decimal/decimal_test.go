@@ -0,0 +1,175 @@
+package decimal
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/intenttest"
+)
+
+func TestParseAndString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"45000.25", "45000.25"},
+		{"-0.5", "-0.5"},
+		{"0.000001", "0.000001"},
+		{"100.00", "100"},
+		{"007", "7"},
+		{".5", "0.5"},
+		{"0", "0"},
+	}
+
+	for _, tt := range tests {
+		d, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got := d.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "-", "1e5", "1.2.3", "abc", "99999999999999999999"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestFromFloat_NoDrift(t *testing.T) {
+	d, err := FromFloat(0.1)
+	if err != nil {
+		t.Fatalf("FromFloat error: %v", err)
+	}
+	if d.Units() != 1 || d.Scale() != 1 {
+		t.Errorf("FromFloat(0.1) = %d/10^%d, want 1/10^1", d.Units(), d.Scale())
+	}
+
+	// 16.1 + 48.7 + 35.2 is 100.00000000000001 in float64 but exactly 100 here
+	sum := Decimal{}
+	for _, f := range []float64{16.1, 48.7, 35.2} {
+		v, _ := FromFloat(f)
+		sum, _ = sum.Add(v)
+	}
+	if sum.Cmp(New(100, 0)) != 0 {
+		t.Errorf("sum = %s, want 100", sum)
+	}
+}
+
+func TestTicks(t *testing.T) {
+	price, _ := Parse("45000.5")
+	tick, _ := Parse("0.1")
+
+	ticks, err := price.Ticks(tick)
+	if err != nil || ticks != 450005 {
+		t.Errorf("Ticks = (%d, %v), want 450005", ticks, err)
+	}
+
+	if _, err := New(4500025, 2).Ticks(tick); err == nil {
+		t.Error("Ticks of 45000.25 with tick 0.1 succeeded, want error")
+	}
+	if _, err := price.Ticks(Decimal{}); err == nil {
+		t.Error("Ticks with zero tick succeeded, want error")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	d := New(-4500025, 2)
+	data, err := json.Marshal(d)
+	if err != nil || string(data) != `"-45000.25"` {
+		t.Fatalf("Marshal = (%s, %v), want \"-45000.25\"", data, err)
+	}
+
+	var fromString, fromNumber Decimal
+	if err := json.Unmarshal(data, &fromString); err != nil || fromString != d {
+		t.Errorf("Unmarshal string = (%s, %v), want %s", fromString, err, d)
+	}
+	if err := json.Unmarshal([]byte("-45000.25"), &fromNumber); err != nil || fromNumber != d {
+		t.Errorf("Unmarshal number = (%s, %v), want %s", fromNumber, err, d)
+	}
+}
+
+func TestFromCommand(t *testing.T) {
+	entry, sl, condPrice := 45000.1, 44500.3, 0.06
+	cmd := &intent.NormalizedCommand{
+		Intent:     intent.IntentOpenPosition,
+		Symbol:     "BTC-USDT",
+		EntryPrice: &entry,
+		StopLoss:   &sl,
+		TPLevels:   []intent.TPLevel{{Price: 46000.7, Percentage: 33.3}},
+		Condition:  &intent.PriceCondition{Operator: intent.ConditionAbove, Price: &condPrice},
+	}
+
+	dc, err := FromCommand(cmd)
+	if err != nil {
+		t.Fatalf("FromCommand error: %v", err)
+	}
+	if dc.EntryPrice.String() != "45000.1" || dc.StopLoss.String() != "44500.3" {
+		t.Errorf("prices = %s / %s", dc.EntryPrice, dc.StopLoss)
+	}
+	if dc.ConditionPrice.String() != "0.06" {
+		t.Errorf("ConditionPrice = %s, want 0.06", dc.ConditionPrice)
+	}
+	if len(dc.TPLevels) != 1 || dc.TPLevels[0].Percentage.String() != "33.3" {
+		t.Errorf("TPLevels = %+v", dc.TPLevels)
+	}
+	if dc.TakeProfit != nil {
+		t.Errorf("TakeProfit = %s, want nil", dc.TakeProfit)
+	}
+
+	// Decimal fields shadow float fields in JSON
+	data, _ := json.Marshal(dc)
+	var fields map[string]any
+	json.Unmarshal(data, &fields)
+	if fields["entry_price"] != "45000.1" || fields["symbol"] != "BTC-USDT" {
+		t.Errorf("JSON = %s", data)
+	}
+
+	back := dc.Float()
+	if *back.EntryPrice != entry || *back.Condition.Price != condPrice || back.TPLevels[0].Price != 46000.7 {
+		t.Errorf("Float() = %+v", back)
+	}
+	if back.EntryPrice == cmd.EntryPrice {
+		t.Error("Float() aliases the original command")
+	}
+}
+
+func TestFromCommand_Invalid(t *testing.T) {
+	huge := 1e30
+	_, err := FromCommand(&intent.NormalizedCommand{Amount: &huge})
+	if !errors.Is(err, ErrRange) {
+		t.Errorf("FromCommand error = %v, want ErrRange", err)
+	}
+}
+
+// decimalJSON encodes commands in their decimal representation
+var decimalJSON = intenttest.Codec{
+	Name: "decimal json",
+	Encode: func(cmd *intent.NormalizedCommand) ([]byte, error) {
+		dc, err := FromCommand(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(dc)
+	},
+	Decode: func(data []byte) (*intent.NormalizedCommand, error) {
+		var dc Command
+		if err := json.Unmarshal(data, &dc); err != nil {
+			return nil, err
+		}
+		return dc.Float(), nil
+	},
+}
+
+func TestCommand_RoundTrip(t *testing.T) {
+	intenttest.RoundTrip(t, decimalJSON)
+}
+
+func TestCommand_RoundTripThroughJSON(t *testing.T) {
+	intenttest.RoundTripChain(t, intenttest.JSON, decimalJSON, intenttest.JSON)
+}
@@ -0,0 +1,48 @@
+package intent
+
+import (
+	"maps"
+	"slices"
+)
+
+// EntitySpan records where an extracted value came from in the input, so
+// UIs can highlight the words behind it. Start and End are character
+// offsets into the text the backend parsed, which differs from RawInput
+// when plugins rewrite the input or WithMaxInputLength shortens it.
+type EntitySpan struct {
+	Start      int     `json:"start"`
+	End        int     `json:"end"`
+	Body       string  `json:"body"`
+	Confidence float64 `json:"confidence"`
+}
+
+// LowConfidenceFields returns the sorted fields, named by their JSON keys,
+// extracted with less than min confidence. With fields given only those are
+// checked, e.g. LowConfidenceFields(0.9, "stop_loss", "entry_price") before
+// executing an order. Fields missing from FieldConfidence weren't extracted
+// by the backend and aren't reported.
+func (c *NormalizedCommand) LowConfidenceFields(min float64, fields ...string) []string {
+	if len(fields) == 0 {
+		fields = slices.Collect(maps.Keys(c.FieldConfidence))
+	}
+	var low []string
+	for _, field := range slices.Sorted(slices.Values(fields)) {
+		if confidence, ok := c.FieldConfidence[field]; ok && confidence < min {
+			low = append(low, field)
+		}
+	}
+	return low
+}
+
+// LowConfidenceEntities returns the sorted names of the entities in
+// EntityMeta extracted with less than min confidence, e.g. to ask the user
+// to double-check a price
+func (c *NormalizedCommand) LowConfidenceEntities(min float64) []string {
+	var names []string
+	for _, name := range slices.Sorted(maps.Keys(c.EntityMeta)) {
+		if c.EntityMeta[name].Confidence < min {
+			names = append(names, name)
+		}
+	}
+	return names
+}
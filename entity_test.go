@@ -0,0 +1,44 @@
+package intent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizedCommand_LowConfidenceEntities(t *testing.T) {
+	cmd := &NormalizedCommand{EntityMeta: map[string]EntitySpan{
+		"symbol":          {Start: 9, End: 12, Body: "BTC", Confidence: 0.98},
+		"price:stop_loss": {Start: 24, End: 29, Body: "44500", Confidence: 0.41},
+		"risk":            {Start: 39, End: 41, Body: "2%", Confidence: 0.6},
+	}}
+
+	got := cmd.LowConfidenceEntities(0.7)
+	if want := []string{"price:stop_loss", "risk"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LowConfidenceEntities(0.7) = %v, want %v", got, want)
+	}
+	if got := (&NormalizedCommand{}).LowConfidenceEntities(0.7); got != nil {
+		t.Errorf("LowConfidenceEntities() without metadata = %v, want nil", got)
+	}
+}
+
+func TestNormalizedCommand_LowConfidenceFields(t *testing.T) {
+	cmd := &NormalizedCommand{FieldConfidence: map[string]float64{
+		"symbol":       0.98,
+		"stop_loss":    0.82,
+		"risk_percent": 0.6,
+	}}
+
+	tests := []struct {
+		fields []string
+		want   []string
+	}{
+		{nil, []string{"risk_percent", "stop_loss"}},
+		{[]string{"stop_loss", "symbol"}, []string{"stop_loss"}},
+		{[]string{"entry_price"}, nil},
+	}
+	for _, tt := range tests {
+		if got := cmd.LowConfidenceFields(0.9, tt.fields...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("LowConfidenceFields(0.9, %v) = %v, want %v", tt.fields, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package intent
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Event is a pipeline lifecycle event published on an EventBus
+type Event interface {
+	// EventName identifies the event type, e.g. "parse_started"
+	EventName() string
+}
+
+// ParseStarted is published before a processor handles input
+type ParseStarted struct {
+	Processor string
+	UserID    string
+	Input     string // Empty when the processor redacts input
+	Time      time.Time
+}
+
+// BackendCalled is published after a processor called its NLP backend
+type BackendCalled struct {
+	Processor string
+	UserID    string
+	Latency   time.Duration
+	Err       error // Nil when the call succeeded
+	Time      time.Time
+}
+
+// ValidationFailed is published for a parsed command that failed validation
+type ValidationFailed struct {
+	Processor string
+	Command   *NormalizedCommand
+	Time      time.Time
+}
+
+// CommandReady is published for a parsed command that passed validation
+type CommandReady struct {
+	Processor string
+	Command   *NormalizedCommand
+	Time      time.Time
+}
+
+// ParseFailed is published when a processor returns an error instead of a
+// command
+type ParseFailed struct {
+	Processor string
+	UserID    string
+	Err       error
+	Time      time.Time
+}
+
+// Dispatched is published by the caller once it handed a command to its
+// executor, e.g. an exchange router. Err is the dispatch error, if any.
+type Dispatched struct {
+	Command *NormalizedCommand
+	Target  string
+	Err     error
+	Time    time.Time
+}
+
+func (ParseStarted) EventName() string     { return "parse_started" }
+func (BackendCalled) EventName() string    { return "backend_called" }
+func (ValidationFailed) EventName() string { return "validation_failed" }
+func (CommandReady) EventName() string     { return "command_ready" }
+func (ParseFailed) EventName() string      { return "parse_failed" }
+func (Dispatched) EventName() string       { return "dispatched" }
+
+// EventBus delivers events to subscribers synchronously, in subscription
+// order, on the publishing goroutine. Subscribers must be fast and must not
+// modify the commands they receive; hand work off to a goroutine or queue
+// otherwise. The zero value is ready to use.
+type EventBus struct {
+	mu   sync.RWMutex
+	next int
+	subs map[int]func(Event)
+	ids  []int // Subscription order
+}
+
+// NewEventBus returns an empty EventBus
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe calls fn for every published event until the returned
+// function is called
+func (b *EventBus) Subscribe(fn func(Event)) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs == nil {
+		b.subs = make(map[int]func(Event))
+	}
+	id := b.next
+	b.next++
+	b.subs[id] = fn
+	b.ids = append(b.ids, id)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+			for i, sub := range b.ids {
+				if sub == id {
+					b.ids = append(b.ids[:i:i], b.ids[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// Publish delivers e to every subscriber. Publishing on a nil bus is a no-op,
+// so components can publish unconditionally.
+func (b *EventBus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	subs := make([]func(Event), 0, len(b.ids))
+	for _, id := range b.ids {
+		subs = append(subs, b.subs[id])
+	}
+	b.mu.RUnlock()
+
+	for _, fn := range subs {
+		fn(e)
+	}
+}
+
+// On subscribes fn to events of type T only
+func On[T Event](b *EventBus, fn func(T)) (unsubscribe func()) {
+	return b.Subscribe(func(e Event) {
+		if typed, ok := e.(T); ok {
+			fn(typed)
+		}
+	})
+}
+
+// EventsOption configures EventsMiddleware
+type EventsOption func(*eventsConfig)
+
+type eventsConfig struct {
+	clock       Clock
+	redactInput bool
+}
+
+// WithEventsClock sets the clock that stamps events (default SystemClock)
+func WithEventsClock(clock Clock) EventsOption {
+	return func(c *eventsConfig) {
+		c.clock = clock
+	}
+}
+
+// WithEventsRedactedInput leaves ParseStarted.Input empty, so user input
+// never reaches subscribers
+func WithEventsRedactedInput() EventsOption {
+	return func(c *eventsConfig) {
+		c.redactInput = true
+	}
+}
+
+// EventsMiddleware publishes ParseStarted, then ValidationFailed or
+// CommandReady, or ParseFailed when the parse returns an error, for every
+// ParseCommand call. Use it for processors that don't accept an EventBus
+// themselves; those also publish BackendCalled.
+func EventsMiddleware(bus *EventBus, opts ...EventsOption) ProcessorMiddleware {
+	config := eventsConfig{clock: SystemClock{}}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return func(next Processor) Processor {
+		return WrapParse(next, func(ctx context.Context, input string) (*NormalizedCommand, error) {
+			name := next.Name()
+			userID := UserIDFromContext(ctx)
+			started := ParseStarted{Processor: name, UserID: userID, Input: input, Time: config.clock.Now()}
+			if config.redactInput {
+				started.Input = ""
+			}
+			bus.Publish(started)
+
+			cmd, err := next.ParseCommand(ctx, input)
+			if err != nil {
+				bus.Publish(ParseFailed{Processor: name, UserID: userID, Err: err, Time: config.clock.Now()})
+				return nil, err
+			}
+			PublishParsed(bus, name, cmd, config.clock.Now())
+			return cmd, nil
+		})
+	}
+}
+
+// PublishParsed publishes CommandReady or ValidationFailed for cmd. The
+// event carries a copy, so subscribers can't affect the caller's command.
+func PublishParsed(bus *EventBus, processor string, cmd *NormalizedCommand, at time.Time) {
+	if bus == nil {
+		return
+	}
+	if cmd.Valid {
+		bus.Publish(CommandReady{Processor: processor, Command: cmd.Clone(), Time: at})
+		return
+	}
+	bus.Publish(ValidationFailed{Processor: processor, Command: cmd.Clone(), Time: at})
+}
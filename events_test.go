@@ -0,0 +1,114 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEventBus_SubscribeAndUnsubscribe(t *testing.T) {
+	bus := NewEventBus()
+	var first, second []string
+
+	unsubscribe := bus.Subscribe(func(e Event) { first = append(first, e.EventName()) })
+	bus.Subscribe(func(e Event) { second = append(second, e.EventName()) })
+
+	bus.Publish(ParseStarted{Processor: "stub"})
+	unsubscribe()
+	unsubscribe() // Idempotent
+	bus.Publish(Dispatched{Target: "exchange"})
+
+	if len(first) != 1 || first[0] != "parse_started" {
+		t.Errorf("first subscriber got %v, want [parse_started]", first)
+	}
+	if len(second) != 2 || second[1] != "dispatched" {
+		t.Errorf("second subscriber got %v, want [parse_started dispatched]", second)
+	}
+}
+
+func TestEventBus_NilIsNoop(t *testing.T) {
+	var bus *EventBus
+	bus.Publish(ParseStarted{})
+	PublishParsed(bus, "stub", &NormalizedCommand{}, time.Time{})
+}
+
+func TestOn_FiltersByType(t *testing.T) {
+	bus := NewEventBus()
+	var ready []*NormalizedCommand
+	On(bus, func(e CommandReady) { ready = append(ready, e.Command) })
+
+	bus.Publish(ParseStarted{})
+	PublishParsed(bus, "stub", &NormalizedCommand{Intent: IntentCheckBalance, Valid: false}, time.Time{})
+	PublishParsed(bus, "stub", &NormalizedCommand{Intent: IntentCheckBalance, Valid: true}, time.Time{})
+
+	if len(ready) != 1 || !ready[0].Valid {
+		t.Errorf("CommandReady events = %v, want one valid command", ready)
+	}
+}
+
+func TestEventsMiddleware(t *testing.T) {
+	bus := NewEventBus()
+	var names []string
+	bus.Subscribe(func(e Event) { names = append(names, e.EventName()) })
+
+	p := Chain(&stubProcessor{}, EventsMiddleware(bus))
+	cmd, err := p.ParseCommand(WithUser(context.Background(), User{ID: "u-1"}), "show my positions")
+	if err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	if len(names) != 2 || names[0] != "parse_started" || names[1] != "command_ready" {
+		t.Errorf("events = %v, want [parse_started command_ready]", names)
+	}
+
+	// Subscribers get a copy
+	var published *NormalizedCommand
+	On(bus, func(e CommandReady) { published = e.Command })
+	cmd, _ = p.ParseCommand(context.Background(), "show my positions")
+	published.Symbol = "BTC-USDT"
+	if cmd.Symbol != "" {
+		t.Error("subscriber mutation leaked into the returned command")
+	}
+}
+
+func TestEventsMiddleware_ClockAndRedaction(t *testing.T) {
+	bus := NewEventBus()
+	var events []Event
+	bus.Subscribe(func(e Event) { events = append(events, e) })
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	p := Chain(&stubProcessor{}, EventsMiddleware(bus, WithEventsClock(NewStepClock(start, time.Second)), WithEventsRedactedInput()))
+	if _, err := p.ParseCommand(context.Background(), "show my positions"); err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	started, ok := events[0].(ParseStarted)
+	if !ok || started.Input != "" || !started.Time.Equal(start) {
+		t.Errorf("first event = %+v, want ParseStarted at %v without input", events[0], start)
+	}
+	if ready, ok := events[1].(CommandReady); !ok || !ready.Time.Equal(start.Add(time.Second)) {
+		t.Errorf("second event = %+v, want CommandReady at %v", events[1], start.Add(time.Second))
+	}
+}
+
+func TestEventsMiddleware_ParseFailed(t *testing.T) {
+	bus := NewEventBus()
+	var names []string
+	var failed ParseFailed
+	bus.Subscribe(func(e Event) { names = append(names, e.EventName()) })
+	On(bus, func(e ParseFailed) { failed = e })
+
+	backendErr := errors.New("backend down")
+	p := Chain(&stubProcessor{err: backendErr}, EventsMiddleware(bus))
+	if _, err := p.ParseCommand(WithUser(context.Background(), User{ID: "u-1"}), "show my positions"); !errors.Is(err, backendErr) {
+		t.Fatalf("ParseCommand error = %v, want %v", err, backendErr)
+	}
+
+	if len(names) != 2 || names[0] != "parse_started" || names[1] != "parse_failed" {
+		t.Errorf("events = %v, want [parse_started parse_failed]", names)
+	}
+	if failed.UserID != "u-1" || !errors.Is(failed.Err, backendErr) {
+		t.Errorf("ParseFailed = %+v, want the user and the backend error", failed)
+	}
+}
@@ -0,0 +1,94 @@
+package intent_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/validators"
+)
+
+// mockProcessor resolves inputs from a fixed table, standing in for an NLP
+// backend in examples and tests
+type mockProcessor map[string]*intent.NormalizedCommand
+
+func (m mockProcessor) ParseCommand(_ context.Context, input string) (*intent.NormalizedCommand, error) {
+	cmd, ok := m[input]
+	if !ok {
+		cmd = &intent.NormalizedCommand{Intent: intent.IntentUnknown}
+	}
+	cmd = cmd.Clone()
+	cmd.RawInput = input
+	validators.ValidateCommand(cmd)
+	return cmd, nil
+}
+
+func (mockProcessor) Name() string                 { return "mock" }
+func (mockProcessor) SupportedLanguages() []string { return []string{"en"} }
+
+// Dispatching parsed commands to their handlers. Valid commands arrive as
+// CommandReady events, the rest as ValidationFailed; the dispatcher reports
+// the outcome of every handoff with a Dispatched event.
+func Example_dispatch() {
+	stopLoss, risk := 44000.0, 1.0
+	side := intent.SideLong
+	processor := mockProcessor{
+		"long BTC at market stop 44000 risk 1": {
+			Intent:      intent.IntentOpenPosition,
+			Symbol:      "BTC-USDT",
+			Side:        &side,
+			OrderType:   intent.OrderTypeMarket,
+			StopLoss:    &stopLoss,
+			RiskPercent: &risk,
+		},
+		"close ETH":         {Intent: intent.IntentClosePosition, Symbol: "ETH-USDT"},
+		"close":             {Intent: intent.IntentClosePosition},
+		"show my positions": {Intent: intent.IntentViewPositions},
+	}
+
+	handlers := map[intent.Intent]func(*intent.NormalizedCommand) error{
+		intent.IntentOpenPosition: func(cmd *intent.NormalizedCommand) error {
+			fmt.Printf("  order: %s %s risking %g%%\n", *cmd.Side, cmd.Symbol, *cmd.RiskPercent)
+			return nil
+		},
+		intent.IntentClosePosition: func(cmd *intent.NormalizedCommand) error {
+			return fmt.Errorf("no open %s position", cmd.Symbol)
+		},
+	}
+
+	bus := intent.NewEventBus()
+	intent.On(bus, func(e intent.CommandReady) {
+		handle, ok := handlers[e.Command.Intent]
+		if !ok {
+			handle = func(*intent.NormalizedCommand) error { return errors.New("no handler") }
+		}
+		bus.Publish(intent.Dispatched{Command: e.Command, Target: string(e.Command.Intent), Err: handle(e.Command)})
+	})
+	intent.On(bus, func(e intent.ValidationFailed) {
+		fmt.Printf("  ask for %v\n", e.Command.Missing)
+	})
+	intent.On(bus, func(e intent.Dispatched) {
+		if e.Err != nil {
+			fmt.Printf("  %s failed: %v\n", e.Target, e.Err)
+		}
+	})
+
+	p := intent.Chain(processor, intent.EventsMiddleware(bus))
+	for _, input := range []string{"long BTC at market stop 44000 risk 1", "close ETH", "close", "show my positions"} {
+		fmt.Println(input)
+		if _, err := p.ParseCommand(context.Background(), input); err != nil {
+			log.Fatal(err)
+		}
+	}
+	// Output:
+	// long BTC at market stop 44000 risk 1
+	//   order: LONG BTC-USDT risking 1%
+	// close ETH
+	//   close_position failed: no open ETH-USDT position
+	// close
+	//   ask for [symbol]
+	// show my positions
+	//   view_positions failed: no handler
+}
@@ -1,7 +0,0 @@
-module intent-examples
-
-go 1.25.1
-
-require github.com/agatticelli/intent-go v0.1.0
-
-replace github.com/agatticelli/intent-go => ../
@@ -0,0 +1,116 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// errNoCommand is returned by FallbackProcessor when no processor returned
+// a command or an error, e.g. when it has none
+var errNoCommand = errors.New("intent: no processor returned a command")
+
+// FallbackProcessor tries its processors in order and returns the first
+// command with a known intent and at least the minimum confidence. Every
+// processor consulted is recorded in the command's Meta.Sources, and
+// Meta.Rationale says why the returned result won, so operators can tune
+// the threshold against what each backend actually returned.
+type FallbackProcessor struct {
+	processors    []Processor
+	minConfidence float64
+}
+
+// NewFallbackProcessor returns a processor trying processors in order.
+// A result whose confidence is below minConfidence falls through to the next
+// processor; when none is accepted, the most confident result is returned.
+func NewFallbackProcessor(minConfidence float64, processors ...Processor) *FallbackProcessor {
+	return &FallbackProcessor{processors: processors, minConfidence: minConfidence}
+}
+
+// Name returns "fallback"; Meta.Processor names the backend that answered
+func (f *FallbackProcessor) Name() string {
+	return "fallback"
+}
+
+// SupportedLanguages returns the languages of every processor, without
+// duplicates
+func (f *FallbackProcessor) SupportedLanguages() []string {
+	var langs []string
+	for _, p := range f.processors {
+		for _, lang := range p.SupportedLanguages() {
+			if !slices.Contains(langs, lang) {
+				langs = append(langs, lang)
+			}
+		}
+	}
+	return langs
+}
+
+// ParseCommand returns the first accepted result. Failures are skipped, and
+// the joined error is returned only when every processor failed.
+func (f *FallbackProcessor) ParseCommand(ctx context.Context, input string) (*NormalizedCommand, error) {
+	var (
+		sources  []SourceResult
+		skipped  []string
+		errs     []error
+		best     *NormalizedCommand
+		bestName string
+	)
+
+	for _, p := range f.processors {
+		name := p.Name()
+		cmd, err := p.ParseCommand(ctx, input)
+		sources = append(sources, SourceOf(name, cmd, err))
+
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			if ctx.Err() != nil {
+				return nil, errors.Join(errs...)
+			}
+			skipped = append(skipped, name+" failed")
+			continue
+		case cmd == nil:
+			skipped = append(skipped, name+" returned no command")
+			continue
+		case cmd.Intent == IntentUnknown:
+			skipped = append(skipped, name+" returned unknown")
+		case cmd.Confidence < f.minConfidence:
+			skipped = append(skipped, fmt.Sprintf("%s confidence %.2f below %.2f", name, cmd.Confidence, f.minConfidence))
+		default:
+			rationale := "primary accepted"
+			if len(skipped) > 0 {
+				rationale = strings.Join(skipped, ", ") + ", fell back to " + name
+			}
+			return withSources(cmd, name, sources, rationale), nil
+		}
+
+		if best == nil || cmd.Confidence > best.Confidence {
+			best, bestName = cmd, name
+		}
+	}
+
+	if best == nil {
+		if len(errs) == 0 {
+			return nil, errNoCommand
+		}
+		return nil, errors.Join(errs...)
+	}
+	return withSources(best, bestName, sources, "none accepted, highest confidence"), nil
+}
+
+// withSources records the backends consulted for cmd and why processor's
+// result won
+func withSources(cmd *NormalizedCommand, processor string, sources []SourceResult, rationale string) *NormalizedCommand {
+	meta := cmd.Meta.Clone()
+	if meta == nil {
+		meta = NewParseMeta(processor, nil, nil)
+	}
+	meta.Processor = processor
+	meta.Sources = sources
+	meta.Rationale = rationale
+	cmd.Meta = meta
+	return cmd
+}
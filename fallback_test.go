@@ -0,0 +1,122 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// fixedProcessor returns the same command or error for every input
+type fixedProcessor struct {
+	name  string
+	cmd   *NormalizedCommand
+	err   error
+	calls int
+}
+
+func (f *fixedProcessor) Name() string                 { return f.name }
+func (f *fixedProcessor) SupportedLanguages() []string { return []string{"en"} }
+
+func (f *fixedProcessor) ParseCommand(ctx context.Context, input string) (*NormalizedCommand, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.cmd.Clone(), nil
+}
+
+func TestFallbackProcessor(t *testing.T) {
+	failing := &fixedProcessor{name: "witai", err: errors.New("timeout")}
+	unsure := &fixedProcessor{name: "llm", cmd: &NormalizedCommand{Intent: IntentClosePosition, Confidence: 0.4}}
+	rules := &fixedProcessor{name: "rules", cmd: &NormalizedCommand{Intent: IntentViewPrice, Confidence: 0.8}}
+	unknown := &fixedProcessor{name: "regex", cmd: &NormalizedCommand{Intent: IntentUnknown}}
+
+	tests := []struct {
+		name          string
+		processors    []Processor
+		wantIntent    Intent
+		wantProcessor string
+		wantRationale string
+		wantSources   []SourceResult
+	}{
+		{
+			name:          "Primary accepted",
+			processors:    []Processor{rules, failing},
+			wantIntent:    IntentViewPrice,
+			wantProcessor: "rules",
+			wantRationale: "primary accepted",
+			wantSources:   []SourceResult{{Processor: "rules", Intent: IntentViewPrice, Confidence: 0.8}},
+		},
+		{
+			name:          "Falls back past failures and low confidence",
+			processors:    []Processor{failing, unsure, unknown, rules},
+			wantIntent:    IntentViewPrice,
+			wantProcessor: "rules",
+			wantRationale: "witai failed, llm confidence 0.40 below 0.70, regex returned unknown, fell back to rules",
+			wantSources: []SourceResult{
+				{Processor: "witai", Err: "timeout"},
+				{Processor: "llm", Intent: IntentClosePosition, Confidence: 0.4},
+				{Processor: "regex", Intent: IntentUnknown},
+				{Processor: "rules", Intent: IntentViewPrice, Confidence: 0.8},
+			},
+		},
+		{
+			name:          "None accepted",
+			processors:    []Processor{unknown, unsure, failing},
+			wantIntent:    IntentClosePosition,
+			wantProcessor: "llm",
+			wantRationale: "none accepted, highest confidence",
+			wantSources: []SourceResult{
+				{Processor: "regex", Intent: IntentUnknown},
+				{Processor: "llm", Intent: IntentClosePosition, Confidence: 0.4},
+				{Processor: "witai", Err: "timeout"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := NewFallbackProcessor(0.7, tt.processors...).ParseCommand(context.Background(), "btc price")
+			if err != nil {
+				t.Fatalf("ParseCommand() error = %v", err)
+			}
+			if cmd.Intent != tt.wantIntent {
+				t.Errorf("Intent = %q, want %q", cmd.Intent, tt.wantIntent)
+			}
+			if cmd.Meta.Processor != tt.wantProcessor {
+				t.Errorf("Meta.Processor = %q, want %q", cmd.Meta.Processor, tt.wantProcessor)
+			}
+			if cmd.Meta.Rationale != tt.wantRationale {
+				t.Errorf("Meta.Rationale = %q, want %q", cmd.Meta.Rationale, tt.wantRationale)
+			}
+			if !reflect.DeepEqual(cmd.Meta.Sources, tt.wantSources) {
+				t.Errorf("Meta.Sources = %+v, want %+v", cmd.Meta.Sources, tt.wantSources)
+			}
+		})
+	}
+
+	if rules.calls != 2 || failing.calls != 2 {
+		t.Errorf("calls = rules %d, witai %d, want 2 each (later processors are skipped once one is accepted)", rules.calls, failing.calls)
+	}
+}
+
+func TestFallbackProcessor_AllFail(t *testing.T) {
+	errA, errB := errors.New("timeout"), errors.New("unauthorized")
+	f := NewFallbackProcessor(0.5,
+		&fixedProcessor{name: "a", err: errA},
+		&fixedProcessor{name: "b", err: errB},
+	)
+
+	cmd, err := f.ParseCommand(context.Background(), "close btc")
+	if cmd != nil {
+		t.Errorf("cmd = %+v, want nil", cmd)
+	}
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("err = %v, want both backend errors", err)
+	}
+
+	if _, err := NewFallbackProcessor(0.5).ParseCommand(context.Background(), "close btc"); err == nil {
+		t.Error("ParseCommand() without processors succeeded, want error")
+	}
+}
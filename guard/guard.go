@@ -0,0 +1,167 @@
+// Package guard protects a processor pipeline with rate limiting, duplicate
+// detection and idempotency. The implementations keep their state in Redis,
+// so the guarantees hold when the bot runs as several replicas.
+package guard
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/agatticelli/intent-go"
+)
+
+var (
+	// ErrRateLimited is returned when the user exceeded the rate limit
+	ErrRateLimited = errors.New("guard: rate limit exceeded")
+
+	// ErrDuplicate is returned when the same input was already seen within
+	// the dedupe window
+	ErrDuplicate = errors.New("guard: duplicate command")
+)
+
+// RateLimiter decides whether another command is allowed for key
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
+
+// DuplicateDetector reports whether key was already seen, recording it otherwise
+type DuplicateDetector interface {
+	Seen(ctx context.Context, key string) (bool, error)
+
+	// Forget removes key, so the next Seen reports it as new
+	Forget(ctx context.Context, key string) error
+}
+
+// IdempotencyStore keeps parse results by idempotency key
+type IdempotencyStore interface {
+	// Load returns the command stored under key, or false when there is none
+	Load(ctx context.Context, key string) (*intent.NormalizedCommand, bool, error)
+
+	// Store saves cmd under key unless a command is already stored, and
+	// returns the command that ends up stored
+	Store(ctx context.Context, key string, cmd *intent.NormalizedCommand) (*intent.NormalizedCommand, error)
+}
+
+// RateLimitMiddleware rejects parses with ErrRateLimited once the user in
+// the context (see intent.WithUser) exceeds limiter. Parses without a user
+// share one bucket.
+func RateLimitMiddleware(limiter RateLimiter) intent.ProcessorMiddleware {
+	return func(next intent.Processor) intent.Processor {
+		return intent.WrapParse(next, func(ctx context.Context, input string) (*intent.NormalizedCommand, error) {
+			ok, err := limiter.Allow(ctx, intent.UserIDFromContext(ctx))
+			if err != nil {
+				return nil, fmt.Errorf("rate limiter: %w", err)
+			}
+			if !ok {
+				return nil, ErrRateLimited
+			}
+			return next.ParseCommand(ctx, input)
+		})
+	}
+}
+
+// DedupeMiddleware rejects with ErrDuplicate an input the same user already
+// sent within the detector's window, e.g. a chat message delivered twice.
+// Inputs are compared case- and whitespace-insensitively. An input whose
+// parse fails is forgotten, so retrying it after a backend error goes through.
+func DedupeMiddleware(detector DuplicateDetector) intent.ProcessorMiddleware {
+	return func(next intent.Processor) intent.Processor {
+		return intent.WrapParse(next, func(ctx context.Context, input string) (*intent.NormalizedCommand, error) {
+			key := inputKey(intent.UserIDFromContext(ctx), input)
+			seen, err := detector.Seen(ctx, key)
+			if err != nil {
+				return nil, fmt.Errorf("duplicate detector: %w", err)
+			}
+			if seen {
+				return nil, ErrDuplicate
+			}
+
+			cmd, err := next.ParseCommand(ctx, input)
+			if err != nil {
+				if forgetErr := detector.Forget(ctx, key); forgetErr != nil {
+					return nil, errors.Join(err, fmt.Errorf("duplicate detector: %w", forgetErr))
+				}
+				return nil, err
+			}
+			return cmd, nil
+		})
+	}
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx carrying key, e.g. a chat message
+// ID, so retries of the same request return the original result
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the key stored by WithIdempotencyKey
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key, ok && key != ""
+}
+
+// IdempotencyMiddleware returns the stored result for parses carrying an
+// idempotency key that was already processed, so a retried request yields
+// the same command (and command ID) on every replica. Keys are scoped to the
+// user in the context, so two users sending the same key never share a
+// result. Parses without a key pass through.
+func IdempotencyMiddleware(store IdempotencyStore) intent.ProcessorMiddleware {
+	return func(next intent.Processor) intent.Processor {
+		return intent.WrapParse(next, func(ctx context.Context, input string) (*intent.NormalizedCommand, error) {
+			requestKey, ok := IdempotencyKeyFromContext(ctx)
+			if !ok {
+				return next.ParseCommand(ctx, input)
+			}
+			key := userKey(intent.UserIDFromContext(ctx), requestKey)
+
+			if cmd, found, err := store.Load(ctx, key); err != nil {
+				return nil, fmt.Errorf("idempotency store: %w", err)
+			} else if found {
+				return cmd, nil
+			}
+
+			cmd, err := next.ParseCommand(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+
+			// A concurrent replica may have stored its result first; return
+			// that one so every caller sees the same command
+			stored, err := store.Store(ctx, key, cmd)
+			if err != nil {
+				return nil, fmt.Errorf("idempotency store: %w", err)
+			}
+			return stored, nil
+		})
+	}
+}
+
+// inputKey identifies input from userID independently of case and whitespace
+func inputKey(userID, input string) string {
+	return userKey(userID, strings.ToLower(strings.Join(strings.Fields(input), " ")))
+}
+
+// userKey scopes key to userID
+func userKey(userID, key string) string {
+	sum := sha256.Sum256([]byte(userID + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+func encodeCommand(cmd *intent.NormalizedCommand) ([]byte, error) {
+	return json.Marshal(cmd)
+}
+
+func decodeCommand(data []byte) (*intent.NormalizedCommand, error) {
+	var cmd intent.NormalizedCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return nil, err
+	}
+	return &cmd, nil
+}
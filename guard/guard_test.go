@@ -0,0 +1,288 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// fakeRedis is an in-memory RedisClient; one instance stands in for the
+// Redis shared by several replicas
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	ttls map[string]time.Duration
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{data: map[string][]byte{}, ttls: map[string]time.Duration{}}
+}
+
+func (f *fakeRedis) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n, _ := strconv.ParseInt(string(f.data[key]), 10, 64)
+	n++
+	f.data[key] = []byte(strconv.FormatInt(n, 10))
+	if n == 1 {
+		f.ttls[key] = ttl
+	}
+	return n, nil
+}
+
+func (f *fakeRedis) SetNX(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.data[key]; ok {
+		return false, nil
+	}
+	f.data[key] = value
+	f.ttls[key] = ttl
+	return true, nil
+}
+
+func (f *fakeRedis) Get(_ context.Context, key string) ([]byte, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.data[key]
+	return v, ok, nil
+}
+
+func (f *fakeRedis) Del(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.data, key)
+	delete(f.ttls, key)
+	return nil
+}
+
+type countingProcessor struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *countingProcessor) ParseCommand(ctx context.Context, input string) (*intent.NormalizedCommand, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	return &intent.NormalizedCommand{
+		Intent:   intent.IntentCheckBalance,
+		ID:       "cmd-" + strconv.Itoa(p.calls),
+		RawInput: input,
+		Valid:    true,
+	}, nil
+}
+
+func (p *countingProcessor) Name() string                 { return "counting" }
+func (p *countingProcessor) SupportedLanguages() []string { return []string{"en"} }
+
+func TestRateLimitMiddleware_SharedAcrossReplicas(t *testing.T) {
+	redis := newFakeRedis()
+	replica := func() intent.Processor {
+		limiter, err := NewRedisRateLimiter(redis, 2, time.Hour)
+		if err != nil {
+			t.Fatalf("NewRedisRateLimiter error: %v", err)
+		}
+		return intent.Chain(&countingProcessor{}, RateLimitMiddleware(limiter))
+	}
+	a, b := replica(), replica()
+	alice := intent.WithUser(context.Background(), intent.User{ID: "alice"})
+	bob := intent.WithUser(context.Background(), intent.User{ID: "bob"})
+
+	if _, err := a.ParseCommand(alice, "check balance"); err != nil {
+		t.Fatalf("first parse error: %v", err)
+	}
+	if _, err := b.ParseCommand(alice, "check balance"); err != nil {
+		t.Fatalf("second parse error: %v", err)
+	}
+	if _, err := a.ParseCommand(alice, "check balance"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("third parse error = %v, want ErrRateLimited", err)
+	}
+	if _, err := b.ParseCommand(bob, "check balance"); err != nil {
+		t.Errorf("other user error = %v, want nil", err)
+	}
+
+	for key, ttl := range redis.ttls {
+		if ttl != time.Hour {
+			t.Errorf("ttl of %s = %v, want 1h", key, ttl)
+		}
+	}
+}
+
+func TestRedisRateLimiter_Window(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := intent.NewStepClock(start, 30*time.Second)
+	limiter, err := NewRedisRateLimiter(newFakeRedis(), 1, time.Minute, WithRateLimiterClock(clock))
+	if err != nil {
+		t.Fatalf("NewRedisRateLimiter error: %v", err)
+	}
+
+	// 12:00:00 and 12:00:30 share a window, 12:01:00 starts the next one
+	for i, want := range []bool{true, false, true} {
+		ok, err := limiter.Allow(ctx, "alice")
+		if err != nil {
+			t.Fatalf("Allow #%d error: %v", i, err)
+		}
+		if ok != want {
+			t.Errorf("Allow #%d = %v, want %v", i, ok, want)
+		}
+	}
+}
+
+func TestNewRedisRateLimiter_RejectsEmptyWindow(t *testing.T) {
+	for _, window := range []time.Duration{0, -time.Second} {
+		if _, err := NewRedisRateLimiter(newFakeRedis(), 1, window); err == nil {
+			t.Errorf("window %v: want error", window)
+		}
+	}
+}
+
+func TestNewRedisGuards_RejectNonPositiveDurations(t *testing.T) {
+	for _, d := range []time.Duration{0, -time.Second} {
+		if _, err := NewRedisDuplicateDetector(newFakeRedis(), d); err == nil {
+			t.Errorf("dedupe window %v: want error", d)
+		}
+		if _, err := NewRedisIdempotencyStore(newFakeRedis(), d); err == nil {
+			t.Errorf("idempotency ttl %v: want error", d)
+		}
+	}
+}
+
+// newDetector returns a detector with a one minute window
+func newDetector(t *testing.T, client RedisClient) *RedisDuplicateDetector {
+	t.Helper()
+	d, err := NewRedisDuplicateDetector(client, time.Minute)
+	if err != nil {
+		t.Fatalf("NewRedisDuplicateDetector error: %v", err)
+	}
+	return d
+}
+
+// newStore returns an idempotency store keeping results for an hour
+func newStore(t *testing.T, client RedisClient) *RedisIdempotencyStore {
+	t.Helper()
+	s, err := NewRedisIdempotencyStore(client, time.Hour)
+	if err != nil {
+		t.Fatalf("NewRedisIdempotencyStore error: %v", err)
+	}
+	return s
+}
+
+func TestDedupeMiddleware(t *testing.T) {
+	redis := newFakeRedis()
+	next := &countingProcessor{}
+	a := intent.Chain(next, DedupeMiddleware(newDetector(t, redis)))
+	b := intent.Chain(next, DedupeMiddleware(newDetector(t, redis)))
+	alice := intent.WithUser(context.Background(), intent.User{ID: "alice"})
+	bob := intent.WithUser(context.Background(), intent.User{ID: "bob"})
+
+	if _, err := a.ParseCommand(alice, "close BTC"); err != nil {
+		t.Fatalf("first parse error: %v", err)
+	}
+	if _, err := b.ParseCommand(alice, "  Close   btc "); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("duplicate error = %v, want ErrDuplicate", err)
+	}
+	if _, err := b.ParseCommand(bob, "close BTC"); err != nil {
+		t.Errorf("other user error = %v, want nil", err)
+	}
+	if next.calls != 2 {
+		t.Errorf("backend calls = %d, want 2", next.calls)
+	}
+}
+
+// flakyProcessor fails its first parse, like a backend timing out
+type flakyProcessor struct {
+	countingProcessor
+}
+
+func (p *flakyProcessor) ParseCommand(ctx context.Context, input string) (*intent.NormalizedCommand, error) {
+	cmd, _ := p.countingProcessor.ParseCommand(ctx, input)
+	if p.calls == 1 {
+		return nil, context.DeadlineExceeded
+	}
+	return cmd, nil
+}
+
+func TestDedupeMiddleware_RetryAfterError(t *testing.T) {
+	next := &flakyProcessor{}
+	p := intent.Chain(next, DedupeMiddleware(newDetector(t, newFakeRedis())))
+	alice := intent.WithUser(context.Background(), intent.User{ID: "alice"})
+
+	if _, err := p.ParseCommand(alice, "close BTC"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("first parse error = %v, want DeadlineExceeded", err)
+	}
+	if _, err := p.ParseCommand(alice, "close BTC"); err != nil {
+		t.Fatalf("retry error = %v, want nil", err)
+	}
+	if _, err := p.ParseCommand(alice, "close BTC"); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("duplicate after success error = %v, want ErrDuplicate", err)
+	}
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	redis := newFakeRedis()
+	next := &countingProcessor{}
+	store := newStore(t, redis)
+	a := intent.Chain(next, IdempotencyMiddleware(store))
+	b := intent.Chain(next, IdempotencyMiddleware(store))
+	ctx := WithIdempotencyKey(context.Background(), "msg-42")
+
+	first, err := a.ParseCommand(ctx, "check balance")
+	if err != nil {
+		t.Fatalf("first parse error: %v", err)
+	}
+	retry, err := b.ParseCommand(ctx, "check balance")
+	if err != nil {
+		t.Fatalf("retry error: %v", err)
+	}
+	if retry.ID != first.ID || next.calls != 1 {
+		t.Errorf("retry ID = %q (calls %d), want %q from 1 call", retry.ID, next.calls, first.ID)
+	}
+
+	// Without a key every parse reaches the backend
+	if _, err := a.ParseCommand(context.Background(), "check balance"); err != nil {
+		t.Fatalf("unkeyed parse error: %v", err)
+	}
+	if next.calls != 2 {
+		t.Errorf("backend calls = %d, want 2", next.calls)
+	}
+}
+
+func TestIdempotencyMiddleware_ScopedByUser(t *testing.T) {
+	next := &countingProcessor{}
+	p := intent.Chain(next, IdempotencyMiddleware(newStore(t, newFakeRedis())))
+	alice := WithIdempotencyKey(intent.WithUser(context.Background(), intent.User{ID: "alice"}), "msg-1")
+	bob := WithIdempotencyKey(intent.WithUser(context.Background(), intent.User{ID: "bob"}), "msg-1")
+
+	first, err := p.ParseCommand(alice, "check balance")
+	if err != nil {
+		t.Fatalf("alice parse error: %v", err)
+	}
+	other, err := p.ParseCommand(bob, "check balance")
+	if err != nil {
+		t.Fatalf("bob parse error: %v", err)
+	}
+	if other.ID == first.ID || next.calls != 2 {
+		t.Errorf("bob got ID %q (calls %d), want a separate result", other.ID, next.calls)
+	}
+}
+
+func TestRedisIdempotencyStore_FirstWriterWins(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t, newFakeRedis())
+
+	first := &intent.NormalizedCommand{ID: "first"}
+	if got, err := store.Store(ctx, "k", first); err != nil || got.ID != "first" {
+		t.Fatalf("Store = (%v, %v), want first", got, err)
+	}
+	got, err := store.Store(ctx, "k", &intent.NormalizedCommand{ID: "second"})
+	if err != nil || got.ID != "first" {
+		t.Errorf("second Store = (%v, %v), want first", got, err)
+	}
+}
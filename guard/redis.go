@@ -0,0 +1,163 @@
+package guard
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// RedisClient is the subset of a Redis client used by the guards. Adapt
+// go-redis, rueidis or similar with a few lines.
+type RedisClient interface {
+	// Incr increments key and returns the new value. ttl is applied when
+	// the key is created (INCR followed by EXPIRE NX).
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+
+	// SetNX stores value under key with ttl unless key exists, and reports
+	// whether it was stored
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+
+	// Get returns the value of key; a missing key is found == false, not an error
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+
+	// Del removes key; a missing key is not an error
+	Del(ctx context.Context, key string) error
+}
+
+// RedisRateLimiter allows limit commands per key in fixed windows
+type RedisRateLimiter struct {
+	client RedisClient
+	prefix string
+	limit  int64
+	window time.Duration
+	clock  intent.Clock
+}
+
+// RateLimiterOption configures a RedisRateLimiter
+type RateLimiterOption func(*RedisRateLimiter)
+
+// WithRateLimiterClock sets the clock that picks the current window
+// (default intent.SystemClock)
+func WithRateLimiterClock(clock intent.Clock) RateLimiterOption {
+	return func(r *RedisRateLimiter) {
+		r.clock = clock
+	}
+}
+
+// NewRedisRateLimiter allows limit commands per key every window, which
+// must be positive
+func NewRedisRateLimiter(client RedisClient, limit int, window time.Duration, opts ...RateLimiterOption) (*RedisRateLimiter, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("rate limit window must be positive, got %v", window)
+	}
+
+	r := &RedisRateLimiter{
+		client: client,
+		prefix: "intent:ratelimit:",
+		limit:  int64(limit),
+		window: window,
+		clock:  intent.SystemClock{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// Allow counts one command for key and reports whether it is within the limit
+func (r *RedisRateLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	// The window index in the key makes every replica share the same bucket
+	bucket := r.clock.Now().UnixNano() / int64(r.window)
+	count, err := r.client.Incr(ctx, r.prefix+key+":"+strconv.FormatInt(bucket, 10), r.window)
+	if err != nil {
+		return false, err
+	}
+	return count <= r.limit, nil
+}
+
+// RedisDuplicateDetector remembers keys for a window
+type RedisDuplicateDetector struct {
+	client RedisClient
+	prefix string
+	window time.Duration
+}
+
+// NewRedisDuplicateDetector treats a key seen again within window as a
+// duplicate. window must be positive.
+func NewRedisDuplicateDetector(client RedisClient, window time.Duration) (*RedisDuplicateDetector, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("dedupe window must be positive, got %v", window)
+	}
+	return &RedisDuplicateDetector{client: client, prefix: "intent:dedupe:", window: window}, nil
+}
+
+// Seen records key and reports whether it was already recorded
+func (d *RedisDuplicateDetector) Seen(ctx context.Context, key string) (bool, error) {
+	stored, err := d.client.SetNX(ctx, d.prefix+key, []byte{1}, d.window)
+	if err != nil {
+		return false, err
+	}
+	return !stored, nil
+}
+
+// Forget removes key
+func (d *RedisDuplicateDetector) Forget(ctx context.Context, key string) error {
+	return d.client.Del(ctx, d.prefix+key)
+}
+
+// RedisIdempotencyStore keeps parse results as JSON for a TTL
+type RedisIdempotencyStore struct {
+	client RedisClient
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisIdempotencyStore keeps results for ttl, which must be positive
+func NewRedisIdempotencyStore(client RedisClient, ttl time.Duration) (*RedisIdempotencyStore, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("idempotency ttl must be positive, got %v", ttl)
+	}
+	return &RedisIdempotencyStore{client: client, prefix: "intent:idempotency:", ttl: ttl}, nil
+}
+
+// Load returns the command stored under key
+func (s *RedisIdempotencyStore) Load(ctx context.Context, key string) (*intent.NormalizedCommand, bool, error) {
+	data, found, err := s.client.Get(ctx, s.prefix+key)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	cmd, err := decodeCommand(data)
+	if err != nil {
+		return nil, false, err
+	}
+	return cmd, true, nil
+}
+
+// Store saves cmd under key unless another result got there first
+func (s *RedisIdempotencyStore) Store(ctx context.Context, key string, cmd *intent.NormalizedCommand) (*intent.NormalizedCommand, error) {
+	data, err := encodeCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.client.SetNX(ctx, s.prefix+key, data, s.ttl)
+	if err != nil {
+		return nil, err
+	}
+	if stored {
+		return cmd, nil
+	}
+
+	existing, found, err := s.Load(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		// Expired between SetNX and Get, ours is as good as any
+		return cmd, nil
+	}
+	return existing, nil
+}
@@ -1,6 +1,9 @@
 package intent
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 // Processor defines the interface for NLP intent processing
 type Processor interface {
@@ -13,3 +16,51 @@ type Processor interface {
 	// SupportedLanguages returns list of supported language codes
 	SupportedLanguages() []string
 }
+
+// BatchProcessor is implemented by processors that can parse many inputs
+// efficiently, e.g. for backfilling historical chat logs
+type BatchProcessor interface {
+	Processor
+
+	// ParseCommands parses every input and returns the commands in input order.
+	// Inputs that fail leave a nil entry and contribute to the joined error.
+	ParseCommands(ctx context.Context, inputs []string) ([]*NormalizedCommand, error)
+}
+
+// Pinger is implemented by processors that can cheaply check backend health,
+// so load balancers and fallbacks can detect a dead backend before user
+// traffic hits it
+type Pinger interface {
+	// Ping returns nil when the backend is reachable and authorized
+	Ping(ctx context.Context) error
+}
+
+// Ping checks the health of p. Decorators are unwrapped until a Pinger is
+// found; processors that can't be pinged are assumed healthy.
+func Ping(ctx context.Context, p Processor) error {
+	for p != nil {
+		if pinger, ok := p.(Pinger); ok {
+			return pinger.Ping(ctx)
+		}
+		p = Unwrap(p)
+	}
+	return nil
+}
+
+// Unwrap returns the processor wrapped by p, or nil when p is not a decorator
+func Unwrap(p Processor) Processor {
+	if u, ok := p.(interface{ Unwrap() Processor }); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// SpeechProcessor is implemented by processors that can parse voice commands
+// directly from audio (e.g. Telegram/WhatsApp voice notes)
+type SpeechProcessor interface {
+	Processor
+
+	// ParseSpeech transcribes audio of the given MIME type and returns the
+	// normalized command; RawInput holds the transcription
+	ParseSpeech(ctx context.Context, audio io.Reader, contentType string) (*NormalizedCommand, error)
+}
@@ -0,0 +1,107 @@
+// Package intenttest is a conformance suite for intent.Processor
+// implementations. Third-party processors run it from their own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		intenttest.Run(t, func(t *testing.T) intent.Processor {
+//			return myprocessor.New(...)
+//		})
+//	}
+//
+// RoundTrip and RoundTripChain check that serialization formats preserve
+// every field of NormalizedCommand. This repository ships only JSON
+// encodings (encoding/json and the decimal package); protobuf, msgpack and
+// other codecs belong to the callers that maintain their schemas, who run
+// the same checks against them.
+package intenttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// DefaultInputs are parsed by Run when no inputs are given
+var DefaultInputs = []string{
+	"open long BTC at 45000 with stop loss 44500 and risk 2%",
+	"close ETH",
+	"show my positions",
+	"mostrar mis posiciones",
+	"",
+}
+
+// cancelGrace bounds how long a processor may take to return once its
+// context is cancelled
+const cancelGrace = time.Second
+
+// Run checks the behavioral contract every Processor must honor:
+//   - Name and SupportedLanguages are non-empty
+//   - ParseCommand never returns a nil command with a nil error
+//   - returned commands keep the input as RawInput and have a Timestamp
+//   - a cancelled context makes ParseCommand fail promptly
+//
+// newProcessor is called once per check. Inputs default to DefaultInputs.
+func Run(t *testing.T, newProcessor func(t *testing.T) intent.Processor, inputs ...string) {
+	t.Helper()
+	if len(inputs) == 0 {
+		inputs = DefaultInputs
+	}
+
+	t.Run("Metadata", func(t *testing.T) {
+		p := newProcessor(t)
+		if p.Name() == "" {
+			t.Error("Name() is empty")
+		}
+		langs := p.SupportedLanguages()
+		if len(langs) == 0 {
+			t.Error("SupportedLanguages() is empty")
+		}
+		for _, lang := range langs {
+			if lang == "" {
+				t.Errorf("SupportedLanguages() = %q, contains an empty code", langs)
+			}
+		}
+	})
+
+	t.Run("ParseCommand", func(t *testing.T) {
+		p := newProcessor(t)
+		for _, input := range inputs {
+			cmd, err := p.ParseCommand(context.Background(), input)
+			if err != nil {
+				continue
+			}
+			if cmd == nil {
+				t.Errorf("ParseCommand(%q) returned a nil command and a nil error", input)
+				continue
+			}
+			if cmd.RawInput != input {
+				t.Errorf("ParseCommand(%q) RawInput = %q, want the input unchanged", input, cmd.RawInput)
+			}
+			if cmd.Timestamp.IsZero() {
+				t.Errorf("ParseCommand(%q) Timestamp is zero", input)
+			}
+		}
+	})
+
+	t.Run("CancelledContext", func(t *testing.T) {
+		p := newProcessor(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := p.ParseCommand(ctx, inputs[0])
+			done <- err
+		}()
+
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Error("ParseCommand with a cancelled context returned no error")
+			}
+		case <-time.After(cancelGrace):
+			t.Errorf("ParseCommand with a cancelled context did not return within %v", cancelGrace)
+		}
+	})
+}
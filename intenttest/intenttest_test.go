@@ -0,0 +1,32 @@
+package intenttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// echoProcessor is a minimal conforming processor
+type echoProcessor struct{}
+
+func (echoProcessor) Name() string                 { return "echo" }
+func (echoProcessor) SupportedLanguages() []string { return []string{"en"} }
+
+func (echoProcessor) ParseCommand(ctx context.Context, input string) (*intent.NormalizedCommand, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &intent.NormalizedCommand{Intent: intent.IntentUnknown, RawInput: input, Timestamp: time.Now()}, nil
+}
+
+func TestRun(t *testing.T) {
+	Run(t, func(*testing.T) intent.Processor { return echoProcessor{} })
+}
+
+func TestRun_CachingProcessor(t *testing.T) {
+	Run(t, func(*testing.T) intent.Processor {
+		return intent.NewCachingProcessor(echoProcessor{}, time.Minute, 10)
+	})
+}
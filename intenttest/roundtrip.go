@@ -0,0 +1,185 @@
+package intenttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// Codec is a serialization format for NormalizedCommand, e.g. JSON or a
+// protobuf or msgpack encoder maintained next to the caller's schema
+type Codec struct {
+	Name   string
+	Encode func(cmd *intent.NormalizedCommand) ([]byte, error)
+	Decode func(data []byte) (*intent.NormalizedCommand, error)
+}
+
+// JSON is the encoding/json codec, the format commands are exchanged in
+var JSON = Codec{
+	Name:   "json",
+	Encode: func(cmd *intent.NormalizedCommand) ([]byte, error) { return json.Marshal(cmd) },
+	Decode: func(data []byte) (*intent.NormalizedCommand, error) {
+		var cmd intent.NormalizedCommand
+		err := json.Unmarshal(data, &cmd)
+		return &cmd, err
+	},
+}
+
+// RoundTrip checks that codec preserves every field of NormalizedCommand
+// exactly. Commands are filled by reflection, so a field added to the
+// struct without updating the encoder fails the check:
+//   - every field, nested ones included, set to a distinct non-zero value
+//   - every pointer set to its zero value, which must not decode as nil
+//
+// Empty slices and maps are left out: formats such as JSON with omitempty
+// can't tell them from nil.
+func RoundTrip(t *testing.T, codec Codec) {
+	t.Helper()
+	RoundTripChain(t, codec)
+}
+
+// RoundTripChain checks that a command converted through codecs in order
+// keeps every field, as when a gateway decodes JSON and re-encodes it in a
+// service's binary format: each codec decodes its own encoding and hands
+// the command to the next. End with the first codec to cover the way back,
+// e.g. JSON, proto, JSON. The commands are the ones RoundTrip uses.
+func RoundTripChain(t *testing.T, codecs ...Codec) {
+	t.Helper()
+
+	names := make([]string, len(codecs))
+	for i, codec := range codecs {
+		names[i] = codec.Name
+	}
+	name := strings.Join(names, " → ")
+
+	t.Run(name+"/all fields", func(t *testing.T) {
+		cmd := &intent.NormalizedCommand{}
+		n := 0
+		if err := fillValue(reflect.ValueOf(cmd).Elem(), "NormalizedCommand", &n, nil); err != nil {
+			t.Fatal(err)
+		}
+		checkRoundTrip(t, name, codecs, cmd)
+	})
+
+	t.Run(name+"/zero pointers", func(t *testing.T) {
+		cmd := &intent.NormalizedCommand{}
+		v := reflect.ValueOf(cmd).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.Kind() == reflect.Ptr {
+				f.Set(reflect.New(f.Type().Elem()))
+			}
+		}
+		checkRoundTrip(t, name, codecs, cmd)
+	})
+}
+
+func checkRoundTrip(t *testing.T, name string, codecs []Codec, cmd *intent.NormalizedCommand) {
+	t.Helper()
+
+	got := cmd
+	for _, codec := range codecs {
+		data, err := codec.Encode(got)
+		if err != nil {
+			t.Fatalf("%s: Encode: %v", codec.Name, err)
+		}
+		if got, err = codec.Decode(data); err != nil {
+			t.Fatalf("%s: Decode: %v", codec.Name, err)
+		}
+	}
+
+	want := reflect.ValueOf(cmd).Elem()
+	have := reflect.ValueOf(got).Elem()
+	for i := 0; i < want.NumField(); i++ {
+		if !reflect.DeepEqual(want.Field(i).Interface(), have.Field(i).Interface()) {
+			t.Errorf("%s: %s = %s after round trip, want %s",
+				name, want.Type().Field(i).Name, describe(have.Field(i)), describe(want.Field(i)))
+		}
+	}
+}
+
+// describe formats v for a failure message, showing what pointers point to
+func describe(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "nil"
+		}
+		return fmt.Sprintf("&%+v", v.Elem().Interface())
+	}
+	return fmt.Sprintf("%+v", v.Interface())
+}
+
+// baseTime is the first time set by fillValue; it is in UTC with whole
+// seconds so every format can represent it exactly
+var baseTime = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// fillValue sets v to a non-zero value distinct from the ones set before it,
+// counting in n. Pointers to a type already being filled (SubCommands) are
+// left nil so the recursion ends.
+func fillValue(v reflect.Value, path string, n *int, filling []reflect.Type) error {
+	*n++
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		v.Set(reflect.ValueOf(baseTime.Add(time.Duration(*n) * time.Hour)))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(*n))
+	case reflect.Float32, reflect.Float64:
+		// A quarter keeps the value exact in binary and decimal formats
+		v.SetFloat(float64(*n) + 0.25)
+	case reflect.String:
+		v.SetString(fmt.Sprintf("v%d", *n))
+	case reflect.Ptr:
+		for _, typ := range filling {
+			if typ == v.Type().Elem() {
+				return nil
+			}
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		return fillValue(v.Elem(), path, n, append(filling, v.Type().Elem()))
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		for i := 0; i < v.Len(); i++ {
+			if err := fillValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), n, filling); err != nil {
+				return err
+			}
+		}
+		// Drop elements left nil by the recursion guard
+		if v.Type().Elem().Kind() == reflect.Ptr && v.Index(0).IsNil() {
+			v.SetZero()
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("intenttest: can't fill %s: map key %s", path, v.Type().Key())
+		}
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		key.SetString(fmt.Sprintf("k%d", *n))
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := fillValue(elem, path+"["+key.String()+"]", n, filling); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				return fmt.Errorf("intenttest: can't fill %s.%s: unexported", path, field.Name)
+			}
+			if err := fillValue(v.Field(i), path+"."+field.Name, n, filling); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("intenttest: can't fill %s of kind %s", path, v.Kind())
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+package intenttest
+
+import "testing"
+
+func TestRoundTrip_JSON(t *testing.T) {
+	RoundTrip(t, JSON)
+}
@@ -0,0 +1,175 @@
+package intent
+
+import "slices"
+
+// IssueCode identifies a validation problem independently of the wording of
+// its message, so consumers can branch on it and render localized text
+type IssueCode string
+
+const (
+	// IssueCodeMissing is a required field that wasn't given
+	IssueCodeMissing IssueCode = "missing"
+	// IssueCodeUnknownIntent is an intent no validator or IntentSpec knows
+	IssueCodeUnknownIntent IssueCode = "unknown_intent"
+	// IssueCodeLowConfidence is an intent discarded for a confidence below
+	// the processor threshold
+	IssueCodeLowConfidence IssueCode = "low_confidence"
+	// IssueCodeNotPositive is a price, size or distance that isn't greater than 0
+	IssueCodeNotPositive IssueCode = "not_positive"
+	// IssueCodeOutOfRange is a value outside its bounds, e.g. leverage or a
+	// percentage sum above 100%
+	IssueCodeOutOfRange IssueCode = "out_of_range"
+	// IssueCodeUnsupported is a value the package doesn't recognize, e.g.
+	// an unknown order type or timeframe
+	IssueCodeUnsupported IssueCode = "unsupported"
+	// IssueCodeConflict is a field that can't be combined with another one
+	IssueCodeConflict IssueCode = "conflict"
+	// IssueCodePriceSide is a price on the wrong side of the entry, or
+	// levels in the wrong order, for the position side
+	IssueCodePriceSide IssueCode = "price_side"
+	// IssueCodeNotAllowed is a value the validation policy forbids
+	IssueCodeNotAllowed IssueCode = "not_allowed"
+	// IssueCodeNotFound is a reference to something that doesn't exist,
+	// e.g. an unknown account
+	IssueCodeNotFound IssueCode = "not_found"
+	// IssueCodeFarFromEntry is a target unusually far from the entry price
+	IssueCodeFarFromEntry IssueCode = "far_from_entry"
+	// IssueCodeAboveUsual is a value above what the user usually chooses,
+	// e.g. the risk per trade
+	IssueCodeAboveUsual IssueCode = "above_usual"
+	// IssueCodeInvalid is any other invalid value, including errors
+	// reported without a code (see SyncIssues)
+	IssueCodeInvalid IssueCode = "invalid"
+)
+
+// Severity tells how a ValidationIssue affects the command
+type Severity string
+
+const (
+	// SeverityError makes the command invalid
+	SeverityError Severity = "error"
+	// SeverityWarning leaves the command valid but asks for confirmation,
+	// e.g. "are you sure?" before a risk above the user's usual
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationIssue is a validation problem in structured form. Field is the
+// JSON name of the field concerned, if any; Message is the English text
+// also reported in Errors or Warnings (or Missing, which holds the Field).
+type ValidationIssue struct {
+	Code     IssueCode `json:"code"`
+	Field    string    `json:"field,omitempty"`
+	Message  string    `json:"message"`
+	Severity Severity  `json:"severity"`
+}
+
+// PrimaryIssue returns the most important issue with the command, so a chat
+// bot can ask one focused question per turn. Errors come before warnings,
+// and among errors missing fields come first, since an error often goes
+// away once the user fills in the gap; within each rank the validator's
+// order is kept. Missing, Errors and Warnings entries without an issue are
+// considered too (see SyncIssues). ok is false when there is nothing to
+// report.
+func (c *NormalizedCommand) PrimaryIssue() (issue ValidationIssue, ok bool) {
+	synced := NormalizedCommand{
+		Issues:   slices.Clone(c.Issues),
+		Missing:  c.Missing,
+		Errors:   c.Errors,
+		Warnings: c.Warnings,
+	}
+	synced.SyncIssues()
+
+	best := -1
+	for _, candidate := range synced.Issues {
+		if rank := issueRank(candidate); rank > best {
+			issue, best = candidate, rank
+		}
+	}
+	return issue, best >= 0
+}
+
+// issueRank orders issues for PrimaryIssue, higher first
+func issueRank(issue ValidationIssue) int {
+	switch {
+	case issue.Severity == SeverityWarning:
+		return 0
+	case issue.Code == IssueCodeMissing:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// MissingIssue returns the issue for the required field that wasn't given
+func MissingIssue(field string) ValidationIssue {
+	return ValidationIssue{
+		Code:     IssueCodeMissing,
+		Field:    field,
+		Message:  field + " is required",
+		Severity: SeverityError,
+	}
+}
+
+// AddIssue records issue on the command. An error, the default severity,
+// marks the command invalid; a warning leaves Valid alone and sets
+// ConfirmationRequired instead. For consumers of the string lists, a
+// missing field is also appended to Missing, a warning's Message to
+// Warnings and any other issue's Message to Errors.
+func (c *NormalizedCommand) AddIssue(issue ValidationIssue) {
+	if issue.Severity == "" {
+		issue.Severity = SeverityError
+	}
+	c.Issues = append(c.Issues, issue)
+	if issue.Severity == SeverityWarning {
+		c.Warnings = append(c.Warnings, issue.Message)
+		c.ConfirmationRequired = true
+		return
+	}
+	if issue.Code == IssueCodeMissing {
+		c.Missing = append(c.Missing, issue.Field)
+	} else {
+		c.Errors = append(c.Errors, issue.Message)
+	}
+	c.Valid = false
+}
+
+// SyncIssues adds an issue for every Missing, Errors and Warnings entry
+// without one, e.g. appended by a plugin or custom validator that predates
+// Issues. The added issues have IssueCodeMissing and IssueCodeInvalid.
+func (c *NormalizedCommand) SyncIssues() {
+	missing := make(map[string]int)
+	errs := make(map[string]int)
+	warnings := make(map[string]int)
+	for _, issue := range c.Issues {
+		switch {
+		case issue.Severity == SeverityWarning:
+			warnings[issue.Message]++
+		case issue.Code == IssueCodeMissing:
+			missing[issue.Field]++
+		default:
+			errs[issue.Message]++
+		}
+	}
+
+	for _, field := range c.Missing {
+		if missing[field] > 0 {
+			missing[field]--
+			continue
+		}
+		c.Issues = append(c.Issues, MissingIssue(field))
+	}
+	for _, msg := range c.Errors {
+		if errs[msg] > 0 {
+			errs[msg]--
+			continue
+		}
+		c.Issues = append(c.Issues, ValidationIssue{Code: IssueCodeInvalid, Message: msg, Severity: SeverityError})
+	}
+	for _, msg := range c.Warnings {
+		if warnings[msg] > 0 {
+			warnings[msg]--
+			continue
+		}
+		c.Issues = append(c.Issues, ValidationIssue{Code: IssueCodeInvalid, Message: msg, Severity: SeverityWarning})
+	}
+}
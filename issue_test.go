@@ -0,0 +1,122 @@
+package intent
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizedCommand_PrimaryIssue(t *testing.T) {
+	tests := []struct {
+		name   string
+		cmd    NormalizedCommand
+		want   ValidationIssue
+		wantOK bool
+	}{
+		{
+			name:   "No issues",
+			cmd:    NormalizedCommand{Valid: true},
+			wantOK: false,
+		},
+		{
+			name:   "Missing before errors",
+			cmd:    NormalizedCommand{Missing: []string{"stop_loss", "risk_percent"}, Errors: []string{"leverage must be between 1 and 125"}},
+			want:   MissingIssue("stop_loss"),
+			wantOK: true,
+		},
+		{
+			name:   "Errors before warnings",
+			cmd:    NormalizedCommand{Errors: []string{"stop_loss must be below entry_price for LONG"}, Warnings: []string{"risk_percent 5% is above your usual 1%"}},
+			want:   ValidationIssue{Code: IssueCodeInvalid, Message: "stop_loss must be below entry_price for LONG", Severity: SeverityError},
+			wantOK: true,
+		},
+		{
+			name:   "Warning",
+			cmd:    NormalizedCommand{Valid: true, Warnings: []string{"risk_percent 5% is above your usual 1%"}},
+			want:   ValidationIssue{Code: IssueCodeInvalid, Message: "risk_percent 5% is above your usual 1%", Severity: SeverityWarning},
+			wantOK: true,
+		},
+		{
+			name:   "First error",
+			cmd:    NormalizedCommand{Errors: []string{"quantity must be greater than 0", "unsupported margin mode: portfolio"}},
+			want:   ValidationIssue{Code: IssueCodeInvalid, Message: "quantity must be greater than 0", Severity: SeverityError},
+			wantOK: true,
+		},
+		{
+			name: "Structured issues ranked by severity",
+			cmd: NormalizedCommand{Issues: []ValidationIssue{
+				{Code: IssueCodeAboveUsual, Field: "risk_percent", Message: "risk_percent 5% is above your usual 1%", Severity: SeverityWarning},
+				{Code: IssueCodeOutOfRange, Field: "leverage", Message: "leverage must be between 1x and 125x", Severity: SeverityError},
+				MissingIssue("stop_loss"),
+			}},
+			want:   MissingIssue("stop_loss"),
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.cmd.PrimaryIssue()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("PrimaryIssue() = (%+v, %v), want (%+v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNormalizedCommand_AddIssue(t *testing.T) {
+	cmd := NormalizedCommand{Valid: true}
+	cmd.AddIssue(MissingIssue("stop_loss"))
+	cmd.AddIssue(ValidationIssue{Code: IssueCodeOutOfRange, Field: "leverage", Message: "leverage must be between 1x and 125x"})
+
+	if cmd.Valid {
+		t.Error("Valid = true, want false")
+	}
+	if len(cmd.Missing) != 1 || cmd.Missing[0] != "stop_loss" {
+		t.Errorf("Missing = %v, want [stop_loss]", cmd.Missing)
+	}
+	if len(cmd.Errors) != 1 || cmd.Errors[0] != "leverage must be between 1x and 125x" {
+		t.Errorf("Errors = %v, want the leverage message", cmd.Errors)
+	}
+	if len(cmd.Issues) != 2 || cmd.Issues[1].Severity != SeverityError {
+		t.Errorf("Issues = %+v, want two issues defaulting to error severity", cmd.Issues)
+	}
+}
+
+func TestNormalizedCommand_SyncIssues(t *testing.T) {
+	cmd := NormalizedCommand{}
+	cmd.AddIssue(MissingIssue("symbol"))
+	cmd.AddIssue(ValidationIssue{Code: IssueCodeConflict, Field: "order_id", Message: "order_id and cancel_all are mutually exclusive"})
+	// Reported by a plugin without an issue
+	cmd.Missing = append(cmd.Missing, "amount")
+	cmd.Errors = append(cmd.Errors, "symbol is banned")
+
+	cmd.SyncIssues()
+	cmd.SyncIssues()
+
+	want := []ValidationIssue{
+		MissingIssue("symbol"),
+		{Code: IssueCodeConflict, Field: "order_id", Message: "order_id and cancel_all are mutually exclusive", Severity: SeverityError},
+		MissingIssue("amount"),
+		{Code: IssueCodeInvalid, Message: "symbol is banned", Severity: SeverityError},
+	}
+	if !slices.Equal(cmd.Issues, want) {
+		t.Errorf("Issues = %+v, want %+v", cmd.Issues, want)
+	}
+}
+
+func TestNormalizedCommand_AddIssue_Warning(t *testing.T) {
+	cmd := NormalizedCommand{Valid: true}
+	cmd.AddIssue(ValidationIssue{Code: IssueCodeAboveUsual, Field: "risk_percent", Message: "risk_percent 5% is above your usual 1%", Severity: SeverityWarning})
+
+	if !cmd.Valid || !cmd.ConfirmationRequired {
+		t.Errorf("Valid = %v, ConfirmationRequired = %v, want both true", cmd.Valid, cmd.ConfirmationRequired)
+	}
+	if len(cmd.Warnings) != 1 || len(cmd.Errors) != 0 {
+		t.Errorf("Warnings = %v, Errors = %v, want the message as a warning only", cmd.Warnings, cmd.Errors)
+	}
+
+	cmd.SyncIssues()
+	if len(cmd.Issues) != 1 {
+		t.Errorf("Issues = %+v, want the warning once", cmd.Issues)
+	}
+}
@@ -0,0 +1,175 @@
+// Package journal exports parsed commands and their execution outcomes to
+// trade-journal CSV layouts
+package journal
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// Format selects the CSV layout written by WriteCSV
+type Format string
+
+const (
+	// FormatTradervue writes one row per execution (entry and exit fills)
+	// using Tradervue's generic import columns
+	FormatTradervue Format = "tradervue"
+
+	// FormatEdgewonk writes one row per round-trip trade for Edgewonk's
+	// custom import template
+	FormatEdgewonk Format = "edgewonk"
+)
+
+// Outcome describes how an executed command played out
+type Outcome struct {
+	Quantity   float64
+	EntryPrice float64
+	ExitPrice  float64
+	OpenedAt   time.Time
+	ClosedAt   time.Time
+	Fees       float64
+	PnL        float64
+}
+
+// Entry pairs a stored command with its execution outcome
+type Entry struct {
+	Command *intent.NormalizedCommand
+	Outcome Outcome
+}
+
+// WriteCSV writes entries to w in the given journal format
+func WriteCSV(w io.Writer, format Format, entries []Entry) error {
+	cw := csv.NewWriter(w)
+
+	var err error
+	switch format {
+	case FormatTradervue:
+		err = writeTradervue(cw, entries)
+	case FormatEdgewonk:
+		err = writeEdgewonk(cw, entries)
+	default:
+		return fmt.Errorf("unsupported journal format: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeTradervue(cw *csv.Writer, entries []Entry) error {
+	if err := cw.Write([]string{"Date", "Time", "Symbol", "Quantity", "Price", "Side", "Commission"}); err != nil {
+		return err
+	}
+
+	for i, e := range entries {
+		side, err := entrySide(i, e)
+		if err != nil {
+			return err
+		}
+
+		openSide, closeSide := "Buy", "Sell"
+		if side == intent.SideShort {
+			openSide, closeSide = "Short", "Cover"
+		}
+
+		// Fees are attributed to the opening execution
+		rows := [][]string{
+			executionRow(e.Command.Symbol, e.Outcome.OpenedAt, e.Outcome.Quantity, e.Outcome.EntryPrice, openSide, e.Outcome.Fees),
+		}
+		if !e.Outcome.ClosedAt.IsZero() {
+			rows = append(rows, executionRow(e.Command.Symbol, e.Outcome.ClosedAt, e.Outcome.Quantity, e.Outcome.ExitPrice, closeSide, 0))
+		}
+
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func executionRow(symbol string, at time.Time, qty, price float64, side string, fees float64) []string {
+	return []string{
+		at.Format("01/02/2006"),
+		at.Format("15:04:05"),
+		symbol,
+		formatFloat(qty),
+		formatFloat(price),
+		side,
+		formatFloat(fees),
+	}
+}
+
+func writeEdgewonk(cw *csv.Writer, entries []Entry) error {
+	header := []string{
+		"Instrument", "Direction", "Entry Date", "Entry Price", "Exit Date", "Exit Price",
+		"Position Size", "Stop Loss", "Take Profit", "Commission", "Result", "Notes",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i, e := range entries {
+		side, err := entrySide(i, e)
+		if err != nil {
+			return err
+		}
+
+		direction := "Long"
+		if side == intent.SideShort {
+			direction = "Short"
+		}
+
+		exitDate := ""
+		if !e.Outcome.ClosedAt.IsZero() {
+			exitDate = e.Outcome.ClosedAt.Format("2006-01-02 15:04")
+		}
+
+		row := []string{
+			e.Command.Symbol,
+			direction,
+			e.Outcome.OpenedAt.Format("2006-01-02 15:04"),
+			formatFloat(e.Outcome.EntryPrice),
+			exitDate,
+			formatFloat(e.Outcome.ExitPrice),
+			formatFloat(e.Outcome.Quantity),
+			formatOptional(e.Command.StopLoss),
+			formatOptional(e.Command.TakeProfit),
+			formatFloat(e.Outcome.Fees),
+			formatFloat(e.Outcome.PnL),
+			e.Command.RawInput,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entrySide returns the trade direction, which every journal format requires
+func entrySide(i int, e Entry) (intent.Side, error) {
+	if e.Command == nil {
+		return "", fmt.Errorf("entry %d: command is required", i)
+	}
+	if e.Command.Side == nil {
+		return "", fmt.Errorf("entry %d: side is required", i)
+	}
+	return *e.Command.Side, nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatOptional(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return formatFloat(*v)
+}
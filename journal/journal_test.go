@@ -0,0 +1,99 @@
+package journal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+func testEntries() []Entry {
+	long, short := intent.SideLong, intent.SideShort
+	sl, tp := 44500.0, 46000.0
+	opened := time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC)
+
+	return []Entry{
+		{
+			Command: &intent.NormalizedCommand{
+				Intent:     intent.IntentOpenPosition,
+				Symbol:     "BTC-USDT",
+				Side:       &long,
+				StopLoss:   &sl,
+				TakeProfit: &tp,
+				RawInput:   "open long BTC at 45000, SL 44500, TP 46000",
+			},
+			Outcome: Outcome{
+				Quantity:   0.5,
+				EntryPrice: 45000,
+				ExitPrice:  46000,
+				OpenedAt:   opened,
+				ClosedAt:   opened.Add(2 * time.Hour),
+				Fees:       4.5,
+				PnL:        495.5,
+			},
+		},
+		{
+			Command: &intent.NormalizedCommand{
+				Intent:   intent.IntentOpenPosition,
+				Symbol:   "ETH-USDT",
+				Side:     &short,
+				RawInput: "short ETH",
+			},
+			Outcome: Outcome{
+				Quantity:   2,
+				EntryPrice: 3000,
+				OpenedAt:   opened,
+			},
+		},
+	}
+}
+
+func TestWriteCSV_Tradervue(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, FormatTradervue, testEntries()); err != nil {
+		t.Fatalf("WriteCSV error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"Date,Time,Symbol,Quantity,Price,Side,Commission",
+		"03/02/2026,14:30:00,BTC-USDT,0.5,45000,Buy,4.5",
+		"03/02/2026,16:30:00,BTC-USDT,0.5,46000,Sell,0",
+		"03/02/2026,14:30:00,ETH-USDT,2,3000,Short,0",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCSV =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteCSV_Edgewonk(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, FormatEdgewonk, testEntries()); err != nil {
+		t.Fatalf("WriteCSV error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"Instrument,Direction,Entry Date,Entry Price,Exit Date,Exit Price,Position Size,Stop Loss,Take Profit,Commission,Result,Notes",
+		`BTC-USDT,Long,2026-03-02 14:30,45000,2026-03-02 16:30,46000,0.5,44500,46000,4.5,495.5,"open long BTC at 45000, SL 44500, TP 46000"`,
+		"ETH-USDT,Short,2026-03-02 14:30,3000,,0,2,,,0,0,short ETH",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCSV =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteCSV_Errors(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := WriteCSV(&buf, Format("excel"), nil); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+
+	noSide := []Entry{{Command: &intent.NormalizedCommand{Symbol: "BTC-USDT"}}}
+	if err := WriteCSV(&buf, FormatEdgewonk, noSide); err == nil {
+		t.Error("expected error for entry without side")
+	}
+}
@@ -0,0 +1,114 @@
+package intent
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// actionKeywords are English and Spanish words that mark a sentence as a
+// trading instruction rather than commentary
+var actionKeywords = map[string]bool{
+	"open": true, "close": true, "buy": true, "sell": true, "long": true, "short": true,
+	"stop": true, "sl": true, "tp": true, "target": true, "risk": true, "leverage": true,
+	"cancel": true, "set": true, "move": true, "hedge": true, "alert": true, "withdraw": true,
+	"show": true, "entry": true, "limit": true, "market": true, "trailing": true,
+	"abrir": true, "abrí": true, "cerrar": true, "cerrá": true, "comprar": true, "vender": true,
+	"largo": true, "corto": true, "riesgo": true, "apalancamiento": true, "cancelar": true,
+	"poner": true, "mover": true, "cubrir": true, "alerta": true, "retirar": true,
+	"mostrar": true, "entrada": true, "mercado": true,
+}
+
+// ExtractActionable reduces input to at most maxLen characters by keeping
+// the trading instruction and dropping commentary, for users who paste a long
+// analysis that ends in a command. It keeps the last sentence containing an
+// action keyword ("open", "stop", "cerrar", ...) along with the keyword
+// sentences right before it that still fit, or the last sentence when none
+// has a keyword. A sentence longer than maxLen keeps its tail, cut at a word
+// boundary. Input that already fits is returned unchanged with omitted "".
+func ExtractActionable(input string, maxLen int) (actionable, omitted string) {
+	if maxLen <= 0 || utf8.RuneCountInString(input) <= maxLen {
+		return input, ""
+	}
+
+	sentences := splitSentences(input)
+	last := len(sentences) - 1
+	for i := last; i >= 0; i-- {
+		if hasActionKeyword(sentences[i]) {
+			last = i
+			break
+		}
+	}
+
+	first := last
+	length := utf8.RuneCountInString(sentences[last])
+	for first > 0 && hasActionKeyword(sentences[first-1]) {
+		n := length + 1 + utf8.RuneCountInString(sentences[first-1])
+		if n > maxLen {
+			break
+		}
+		first--
+		length = n
+	}
+
+	actionable = strings.Join(sentences[first:last+1], " ")
+	dropped := append(append([]string(nil), sentences[:first]...), sentences[last+1:]...)
+	if length > maxLen {
+		head, tail := splitTail(actionable, maxLen)
+		actionable = tail
+		dropped = append(append(append([]string(nil), sentences[:first]...), head), sentences[last+1:]...)
+	}
+	return actionable, strings.Join(dropped, " ")
+}
+
+// splitSentences splits text after '.', '!', '?', ';' and line breaks.
+// Punctuation not followed by a space, like the dot in "45000.5", doesn't
+// end a sentence.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	runes := []rune(text)
+	for i, r := range runes {
+		end := r == '\n'
+		if strings.ContainsRune(".!?;", r) {
+			end = i+1 == len(runes) || unicode.IsSpace(runes[i+1])
+		}
+		if !end {
+			continue
+		}
+		if s := strings.TrimSpace(string(runes[start : i+1])); s != "" {
+			sentences = append(sentences, s)
+		}
+		start = i + 1
+	}
+	if s := strings.TrimSpace(string(runes[start:])); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
+
+func hasActionKeyword(sentence string) bool {
+	words := strings.FieldsFunc(strings.ToLower(sentence), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if actionKeywords[w] {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTail returns the last maxLen characters of s, starting at a word
+// boundary, and what precedes them
+func splitTail(s string, maxLen int) (head, tail string) {
+	runes := []rune(s)
+	cut := len(runes) - maxLen
+	for i := cut; i < len(runes); i++ {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimSpace(string(runes[:cut])), strings.TrimSpace(string(runes[cut:]))
+}
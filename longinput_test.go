@@ -0,0 +1,70 @@
+package intent
+
+import "testing"
+
+func TestExtractActionable(t *testing.T) {
+	analysis := "BTC has been ranging for weeks and funding is flat. " +
+		"The daily close above the 200 EMA looks constructive to me, volume is picking up. "
+
+	tests := []struct {
+		name           string
+		input          string
+		maxLen         int
+		wantActionable string
+		wantOmitted    string
+	}{
+		{
+			name:           "Fits",
+			input:          "open long BTC at 45000",
+			maxLen:         280,
+			wantActionable: "open long BTC at 45000",
+		},
+		{
+			name:           "Disabled",
+			input:          analysis + "open long BTC at 45000",
+			maxLen:         0,
+			wantActionable: analysis + "open long BTC at 45000",
+		},
+		{
+			name:           "Command after analysis",
+			input:          analysis + "Open long BTC at 45000.5. Stop at 44000, risk 1%. Thanks!",
+			maxLen:         60,
+			wantActionable: "Open long BTC at 45000.5. Stop at 44000, risk 1%.",
+			wantOmitted: "BTC has been ranging for weeks and funding is flat. " +
+				"The daily close above the 200 EMA looks constructive to me, volume is picking up. Thanks!",
+		},
+		{
+			name:           "Keyword sentences that don't fit",
+			input:          analysis + "Open long BTC at 45000. Stop at 44000, risk 1%.",
+			maxLen:         30,
+			wantActionable: "Stop at 44000, risk 1%.",
+			wantOmitted:    analysis + "Open long BTC at 45000.",
+		},
+		{
+			name:           "Spanish",
+			input:          "El mercado viene lateral hace semanas y no veo volumen.\nAbrir largo ETH en 3000 con stop 2900",
+			maxLen:         50,
+			wantActionable: "Abrir largo ETH en 3000 con stop 2900",
+			wantOmitted:    "El mercado viene lateral hace semanas y no veo volumen.",
+		},
+		{
+			name:           "Single long sentence keeps the tail",
+			input:          "so after all of that I think the best idea right now is to open long BTC at 45000",
+			maxLen:         30,
+			wantActionable: "is to open long BTC at 45000",
+			wantOmitted:    "so after all of that I think the best idea right now",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionable, omitted := ExtractActionable(tt.input, tt.maxLen)
+			if actionable != tt.wantActionable {
+				t.Errorf("actionable = %q, want %q", actionable, tt.wantActionable)
+			}
+			if omitted != tt.wantOmitted {
+				t.Errorf("omitted = %q, want %q", omitted, tt.wantOmitted)
+			}
+		})
+	}
+}
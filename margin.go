@@ -0,0 +1,11 @@
+package intent
+
+// MarginMode is how collateral backs a position
+type MarginMode string
+
+const (
+	// MarginModeCross shares the account balance across positions
+	MarginModeCross MarginMode = "cross"
+	// MarginModeIsolated limits the collateral to the position's own margin
+	MarginModeIsolated MarginMode = "isolated"
+)
@@ -0,0 +1,55 @@
+package intent
+
+import (
+	"context"
+	"time"
+)
+
+// MetricsSink receives parse lifecycle measurements from processors.
+// Implementations must be safe for concurrent use.
+type MetricsSink interface {
+	// ParseStarted is called before the backend is contacted
+	ParseStarted(processor string)
+
+	// ParseSucceeded is called with the resulting intent and confidence
+	ParseSucceeded(processor string, intent Intent, confidence float64, latency time.Duration)
+
+	// ParseFailed is called when ParseCommand returns an error
+	ParseFailed(processor string, err error, latency time.Duration)
+}
+
+// DecodeAnomalySink is optionally implemented by a MetricsSink to count
+// backend responses that don't match the expected schema, so silent schema
+// drift from the NLP provider is detected early
+type DecodeAnomalySink interface {
+	DecodeAnomaly(processor string, err error)
+}
+
+// NopMetrics is a MetricsSink that discards all measurements
+type NopMetrics struct{}
+
+func (NopMetrics) ParseStarted(string)                                   {}
+func (NopMetrics) ParseSucceeded(string, Intent, float64, time.Duration) {}
+func (NopMetrics) ParseFailed(string, error, time.Duration)              {}
+func (NopMetrics) DecodeAnomaly(string, error)                           {}
+
+// MetricsMiddleware reports every ParseCommand call to sink.
+// Use it for processors that don't accept a MetricsSink themselves.
+func MetricsMiddleware(sink MetricsSink) ProcessorMiddleware {
+	return func(next Processor) Processor {
+		return WrapParse(next, func(ctx context.Context, input string) (*NormalizedCommand, error) {
+			name := next.Name()
+			start := time.Now()
+			sink.ParseStarted(name)
+
+			cmd, err := next.ParseCommand(ctx, input)
+			if err != nil {
+				sink.ParseFailed(name, err, time.Since(start))
+				return nil, err
+			}
+
+			sink.ParseSucceeded(name, cmd.Intent, cmd.Confidence, time.Since(start))
+			return cmd, nil
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	mu        sync.Mutex
+	started   int
+	succeeded []Intent
+	failed    []error
+}
+
+func (r *recordingSink) ParseStarted(string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.started++
+}
+
+func (r *recordingSink) ParseSucceeded(_ string, i Intent, _ float64, _ time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.succeeded = append(r.succeeded, i)
+}
+
+func (r *recordingSink) ParseFailed(_ string, err error, _ time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed = append(r.failed, err)
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	sink := &recordingSink{}
+	ctx := context.Background()
+
+	ok := Chain(&stubProcessor{}, MetricsMiddleware(sink))
+	ok.ParseCommand(ctx, "show my positions")
+
+	failing := Chain(&stubProcessor{err: errors.New("boom")}, MetricsMiddleware(sink))
+	failing.ParseCommand(ctx, "show my positions")
+
+	if sink.started != 2 {
+		t.Errorf("started = %d, want 2", sink.started)
+	}
+	if len(sink.succeeded) != 1 || sink.succeeded[0] != IntentViewPositions {
+		t.Errorf("succeeded = %v, want [%v]", sink.succeeded, IntentViewPositions)
+	}
+	if len(sink.failed) != 1 {
+		t.Errorf("failed = %v, want one error", sink.failed)
+	}
+}
@@ -0,0 +1,51 @@
+package intent
+
+import (
+	"context"
+	"time"
+)
+
+// ProcessorMiddleware decorates a Processor with cross-cutting behavior
+// (logging, metrics, caching, auth, ...)
+type ProcessorMiddleware func(Processor) Processor
+
+// Chain wraps p with the given middlewares.
+// The first middleware is the outermost, so it sees the call first:
+// Chain(p, a, b) is equivalent to a(b(p)).
+func Chain(p Processor, mws ...ProcessorMiddleware) Processor {
+	for i := len(mws) - 1; i >= 0; i-- {
+		p = mws[i](p)
+	}
+	return p
+}
+
+// ParseFunc has the signature of Processor.ParseCommand
+type ParseFunc func(ctx context.Context, input string) (*NormalizedCommand, error)
+
+// WrapParse returns a Processor that uses parse for ParseCommand and
+// delegates Name and SupportedLanguages to next. It removes the wrapping
+// boilerplate from middlewares that only intercept parsing.
+func WrapParse(next Processor, parse ParseFunc) Processor {
+	return &wrappedProcessor{Processor: next, parse: parse}
+}
+
+type wrappedProcessor struct {
+	Processor
+	parse ParseFunc
+}
+
+func (w *wrappedProcessor) ParseCommand(ctx context.Context, input string) (*NormalizedCommand, error) {
+	return w.parse(ctx, input)
+}
+
+// Unwrap returns the decorated processor
+func (w *wrappedProcessor) Unwrap() Processor {
+	return w.Processor
+}
+
+// CacheMiddleware returns a middleware that wraps processors with NewCachingProcessor
+func CacheMiddleware(ttl time.Duration, maxEntries int, opts ...CacheOption) ProcessorMiddleware {
+	return func(next Processor) Processor {
+		return NewCachingProcessor(next, ttl, maxEntries, opts...)
+	}
+}
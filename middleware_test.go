@@ -0,0 +1,73 @@
+package intent
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestChain_Order(t *testing.T) {
+	var calls []string
+
+	record := func(name string) ProcessorMiddleware {
+		return func(next Processor) Processor {
+			return WrapParse(next, func(ctx context.Context, input string) (*NormalizedCommand, error) {
+				calls = append(calls, name)
+				return next.ParseCommand(ctx, input)
+			})
+		}
+	}
+
+	p := Chain(&stubProcessor{}, record("outer"), record("inner"))
+	if _, err := p.ParseCommand(context.Background(), "check balance"); err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	want := []string{"outer", "inner"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("calls[%d] = %q, want %q", i, calls[i], want[i])
+		}
+	}
+}
+
+func TestChain_NoMiddlewares(t *testing.T) {
+	stub := &stubProcessor{}
+	if p := Chain(stub); p != stub {
+		t.Errorf("Chain() without middlewares = %v, want the processor itself", p)
+	}
+}
+
+func TestWrapParse_DelegatesMetadata(t *testing.T) {
+	p := WrapParse(&stubProcessor{}, func(ctx context.Context, input string) (*NormalizedCommand, error) {
+		return &NormalizedCommand{Intent: IntentUnknown, RawInput: input}, nil
+	})
+
+	if p.Name() != "stub" {
+		t.Errorf("Name() = %q, want %q", p.Name(), "stub")
+	}
+	if langs := p.SupportedLanguages(); len(langs) != 1 || langs[0] != "en" {
+		t.Errorf("SupportedLanguages() = %v, want [en]", langs)
+	}
+
+	cmd, _ := p.ParseCommand(context.Background(), "hello")
+	if cmd.Intent != IntentUnknown {
+		t.Errorf("Intent = %v, want %v", cmd.Intent, IntentUnknown)
+	}
+}
+
+func TestCacheMiddleware(t *testing.T) {
+	stub := &stubProcessor{}
+	p := Chain(stub, CacheMiddleware(time.Minute, 10))
+
+	for i := 0; i < 3; i++ {
+		p.ParseCommand(context.Background(), "show my positions")
+	}
+
+	if got := stub.calls.Load(); got != 1 {
+		t.Errorf("backend calls = %d, want 1", got)
+	}
+}
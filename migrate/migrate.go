@@ -0,0 +1,87 @@
+// Package migrate upgrades serialized NormalizedCommands written by older
+// versions of intent-go, so commands persisted in audit logs and queues can
+// still be read after the struct changes
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// ErrNewerVersion is returned for commands written with a schema version
+// newer than intent.CurrentSchemaVersion, i.e. by a later release
+var ErrNewerVersion = errors.New("migrate: command schema version is newer than this release")
+
+// step upgrades the decoded JSON object of a command from version from to
+// from+1. Steps work on the raw object, so they can rename or drop keys
+// NormalizedCommand no longer has.
+type step struct {
+	from    int
+	upgrade func(cmd map[string]any) error
+}
+
+// steps holds one step per schema version, in order. Add a step whenever
+// intent.CurrentSchemaVersion is bumped.
+var steps = []step{
+	// Version 0 commands were stored before schema_version existed; the
+	// layout is the same as version 1
+	{from: 0, upgrade: func(map[string]any) error { return nil }},
+}
+
+// Upgrade returns the JSON command in data at intent.CurrentSchemaVersion.
+// Commands already at the current version are returned unchanged.
+func Upgrade(data []byte) ([]byte, error) {
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("migrate: decoding command: %w", err)
+	}
+
+	version, err := schemaVersion(obj)
+	if err != nil {
+		return nil, err
+	}
+	if version == intent.CurrentSchemaVersion {
+		return data, nil
+	}
+	if version > intent.CurrentSchemaVersion {
+		return nil, fmt.Errorf("%w: %d > %d", ErrNewerVersion, version, intent.CurrentSchemaVersion)
+	}
+
+	for _, s := range steps[version:] {
+		if err := s.upgrade(obj); err != nil {
+			return nil, fmt.Errorf("migrate: upgrading from version %d: %w", s.from, err)
+		}
+	}
+	obj["schema_version"] = intent.CurrentSchemaVersion
+	return json.Marshal(obj)
+}
+
+// Command decodes the JSON command in data, upgrading it to
+// intent.CurrentSchemaVersion first
+func Command(data []byte) (*intent.NormalizedCommand, error) {
+	upgraded, err := Upgrade(data)
+	if err != nil {
+		return nil, err
+	}
+	var cmd intent.NormalizedCommand
+	if err := json.Unmarshal(upgraded, &cmd); err != nil {
+		return nil, fmt.Errorf("migrate: decoding command: %w", err)
+	}
+	return &cmd, nil
+}
+
+// schemaVersion returns the schema_version of obj, 0 when absent
+func schemaVersion(obj map[string]any) (int, error) {
+	raw, ok := obj["schema_version"]
+	if !ok {
+		return 0, nil
+	}
+	version, ok := raw.(float64)
+	if !ok || version < 0 || version != float64(int(version)) {
+		return 0, fmt.Errorf("migrate: invalid schema_version %v", raw)
+	}
+	return int(version), nil
+}
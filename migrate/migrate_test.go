@@ -0,0 +1,55 @@
+package migrate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/intent-go"
+)
+
+func TestSteps_CoverEveryVersion(t *testing.T) {
+	if len(steps) != intent.CurrentSchemaVersion {
+		t.Fatalf("%d migration steps for schema version %d", len(steps), intent.CurrentSchemaVersion)
+	}
+	for i, s := range steps {
+		if s.from != i {
+			t.Errorf("steps[%d] upgrades from version %d, want %d", i, s.from, i)
+		}
+	}
+}
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"Unversioned", `{"intent":"open_position","symbol":"BTC-USDT","stop_loss":44500,"valid":true,"raw_input":"open long BTC"}`},
+		{"Current", `{"intent":"open_position","symbol":"BTC-USDT","stop_loss":44500,"valid":true,"raw_input":"open long BTC","schema_version":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := Command([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("Command error: %v", err)
+			}
+			if cmd.SchemaVersion != intent.CurrentSchemaVersion {
+				t.Errorf("SchemaVersion = %d, want %d", cmd.SchemaVersion, intent.CurrentSchemaVersion)
+			}
+			if cmd.Intent != intent.IntentOpenPosition || cmd.Symbol != "BTC-USDT" || cmd.StopLoss == nil || *cmd.StopLoss != 44500 {
+				t.Errorf("Command = %+v, want the stored open_position", cmd)
+			}
+		})
+	}
+}
+
+func TestUpgrade_Errors(t *testing.T) {
+	if _, err := Upgrade([]byte(`{"intent":"open_position","schema_version":99}`)); !errors.Is(err, ErrNewerVersion) {
+		t.Errorf("newer version error = %v, want ErrNewerVersion", err)
+	}
+	for _, data := range []string{`{"schema_version":"1"}`, `{"schema_version":1.5}`, `{"schema_version":-1}`, `not json`} {
+		if _, err := Upgrade([]byte(data)); err == nil {
+			t.Errorf("Upgrade(%s) accepted", data)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package intent
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// NamespaceSeparator splits a namespaced intent such as
+// "custom:fund_transfer" into its namespace and name. Built-in intents have
+// no namespace, so intents registered by applications embedding the package
+// in adjacent domains (fund operations, alerting) can't collide with them.
+const NamespaceSeparator = ":"
+
+// Namespaced returns the intent name in namespace, e.g.
+// Namespaced("custom", "fund_transfer") is "custom:fund_transfer"
+func Namespaced(namespace, name string) Intent {
+	return Intent(namespace + NamespaceSeparator + name)
+}
+
+// SplitIntent returns the namespace and name of i. Built-in intents have
+// an empty namespace.
+func SplitIntent(i Intent) (namespace, name string) {
+	if namespace, name, ok := strings.Cut(string(i), NamespaceSeparator); ok {
+		return namespace, name
+	}
+	return "", string(i)
+}
+
+// IntentSpec describes a namespaced intent registered with RegisterIntent
+type IntentSpec struct {
+	// Intent is the namespaced name, e.g. Namespaced("custom", "fund_transfer")
+	Intent Intent
+
+	// ReadOnly marks a query, see IsReadOnly
+	ReadOnly bool
+
+	// Validate checks the intent's fields like the built-in validators do,
+	// appending to Missing and Errors and clearing Valid. Nil accepts every
+	// command.
+	Validate func(cmd *NormalizedCommand)
+}
+
+var (
+	intentsMu sync.RWMutex
+	intents   = map[Intent]IntentSpec{}
+)
+
+// RegisterIntent makes a namespaced intent known to the backends, which
+// then map it instead of returning IntentUnknown, and to validation. It is
+// meant to be called from an init function and panics if the intent has no
+// namespace or is already registered.
+func RegisterIntent(spec IntentSpec) {
+	intentsMu.Lock()
+	defer intentsMu.Unlock()
+
+	if namespace, name := SplitIntent(spec.Intent); namespace == "" || name == "" {
+		panic(fmt.Sprintf("intent: RegisterIntent needs a namespaced intent, got %q", spec.Intent))
+	}
+	if _, ok := intents[spec.Intent]; ok {
+		panic(fmt.Sprintf("intent: RegisterIntent called twice for %q", spec.Intent))
+	}
+	intents[spec.Intent] = spec
+}
+
+// LookupIntent returns the spec of a registered namespaced intent
+func LookupIntent(i Intent) (IntentSpec, bool) {
+	intentsMu.RLock()
+	defer intentsMu.RUnlock()
+	spec, ok := intents[i]
+	return spec, ok
+}
@@ -0,0 +1,67 @@
+package intent
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSplitIntent(t *testing.T) {
+	tests := []struct {
+		intent        Intent
+		wantNamespace string
+		wantName      string
+	}{
+		{Namespaced("custom", "fund_transfer"), "custom", "fund_transfer"},
+		{IntentOpenPosition, "", "open_position"},
+		{Intent("funds:ops:transfer"), "funds", "ops:transfer"},
+	}
+
+	for _, tt := range tests {
+		namespace, name := SplitIntent(tt.intent)
+		if namespace != tt.wantNamespace || name != tt.wantName {
+			t.Errorf("SplitIntent(%q) = %q, %q, want %q, %q", tt.intent, namespace, name, tt.wantNamespace, tt.wantName)
+		}
+	}
+}
+
+func TestRegisterIntent(t *testing.T) {
+	query := Namespaced("test", "fund_balance")
+	RegisterIntent(IntentSpec{Intent: query, ReadOnly: true})
+
+	if _, ok := LookupIntent(query); !ok {
+		t.Fatal("registered intent not returned by LookupIntent")
+	}
+	if _, ok := LookupIntent(Namespaced("test", "missing")); ok {
+		t.Error("LookupIntent found an unregistered intent")
+	}
+	if !IsReadOnly(query) {
+		t.Error("IsReadOnly = false for a read-only registered intent")
+	}
+
+	for _, i := range []Intent{query, "fund_transfer", "test:", ":fund_transfer"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterIntent(%q) did not panic", i)
+				}
+			}()
+			RegisterIntent(IntentSpec{Intent: i})
+		}()
+	}
+}
+
+func TestNamespacedIntent_JSON(t *testing.T) {
+	cmd := &NormalizedCommand{Intent: Namespaced("custom", "fund_transfer")}
+
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got NormalizedCommand
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Intent != cmd.Intent {
+		t.Errorf("Intent = %q after round trip, want %q", got.Intent, cmd.Intent)
+	}
+}
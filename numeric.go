@@ -0,0 +1,81 @@
+package intent
+
+import (
+	"fmt"
+	"math"
+)
+
+// NumericError reports a numeric field with a value it can never take, e.g.
+// a negative stop loss or a NaN leverage
+type NumericError struct {
+	Field string // JSON name, e.g. "stop_loss" or "tp_levels[1].price"
+	Value float64
+}
+
+// Finite reports whether Value is a number, as opposed to NaN or ±Inf
+func (e *NumericError) Finite() bool {
+	return !math.IsNaN(e.Value) && !math.IsInf(e.Value, 0)
+}
+
+func (e *NumericError) Error() string {
+	if !e.Finite() {
+		return fmt.Sprintf("%s must be a finite number", e.Field)
+	}
+	return fmt.Sprintf("%s must be greater than 0", e.Field)
+}
+
+// CheckNumericFields returns a NumericError for every numeric field that is
+// set to NaN or ±Inf, and for every price, size, distance or ladder
+// percentage that is not greater than 0, whatever the intent, so
+// validators don't each have to remember the check. Bounded values such as
+// leverage and percentages are only checked for NaN and ±Inf here; their
+// ranges are checked by the validators.
+func CheckNumericFields(c *NormalizedCommand) []*NumericError {
+	var errs []*NumericError
+	check := func(name string, value *float64, positive bool) {
+		if value == nil {
+			return
+		}
+		err := &NumericError{Field: name, Value: *value}
+		if !err.Finite() || positive && !(*value > 0) {
+			errs = append(errs, err)
+		}
+	}
+
+	check("entry_price", c.EntryPrice, true)
+	check("stop_loss", c.StopLoss, true)
+	check("take_profit", c.TakeProfit, true)
+	check("trigger_price", c.TriggerPrice, true)
+	check("stop_loss_offset", c.StopLossOffset, false)
+	check("risk_percent", c.RiskPercent, false)
+	check("rr_ratio", c.RRRatio, true)
+	check("leverage", c.Leverage, false)
+	check("quantity", c.Quantity, true)
+	check("notional_usd", c.NotionalUSD, true)
+	check("close_percent", c.ClosePercent, false)
+	check("hedge_ratio", c.HedgeRatio, false)
+	check("callback_rate", c.CallbackRate, true)
+	check("distance", c.Distance, true)
+	check("size_factor", c.SizeFactor, true)
+	check("grid_lower", c.GridLower, true)
+	check("grid_upper", c.GridUpper, true)
+	check("grid_level_size", c.GridLevelSize, true)
+	check("alert_price", c.AlertPrice, true)
+	check("amount", c.Amount, true)
+	if c.Condition != nil {
+		check("condition_price", c.Condition.Price, true)
+	}
+	for i := range c.TPLevels {
+		check(fmt.Sprintf("tp_levels[%d].price", i), &c.TPLevels[i].Price, true)
+		check(fmt.Sprintf("tp_levels[%d].percentage", i), &c.TPLevels[i].Percentage, true)
+	}
+	for i := range c.SLLevels {
+		check(fmt.Sprintf("sl_levels[%d].price", i), &c.SLLevels[i].Price, true)
+		check(fmt.Sprintf("sl_levels[%d].percentage", i), &c.SLLevels[i].Percentage, true)
+	}
+	for i := range c.EntryLevels {
+		check(fmt.Sprintf("entry_levels[%d].price", i), &c.EntryLevels[i].Price, true)
+		check(fmt.Sprintf("entry_levels[%d].percentage", i), &c.EntryLevels[i].Percentage, true)
+	}
+	return errs
+}
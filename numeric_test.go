@@ -0,0 +1,75 @@
+package intent
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestCheckNumericFields(t *testing.T) {
+	neg, zero, pos := -45000.0, 0.0, 1.5
+	cmd := &NormalizedCommand{
+		Intent:     IntentOpenPosition,
+		EntryPrice: &neg,
+		StopLoss:   &pos,
+		Distance:   &zero,
+		Condition:  &PriceCondition{Price: &neg},
+	}
+
+	errs := CheckNumericFields(cmd)
+
+	want := []NumericError{
+		{Field: "entry_price", Value: -45000},
+		{Field: "distance", Value: 0},
+		{Field: "condition_price", Value: -45000},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("CheckNumericFields() = %v, want %d errors", errs, len(want))
+	}
+	for i, err := range errs {
+		if *err != want[i] {
+			t.Errorf("error %d = %+v, want %+v", i, *err, want[i])
+		}
+	}
+
+	var numErr *NumericError
+	if !errors.As(error(errs[0]), &numErr) || numErr.Error() != "entry_price must be greater than 0" {
+		t.Errorf("Error() = %q", errs[0].Error())
+	}
+}
+
+func TestCheckNumericFields_NonFiniteAndLevels(t *testing.T) {
+	nan, inf, risk := math.NaN(), math.Inf(1), -2.0
+	cmd := &NormalizedCommand{
+		Intent:      IntentSetTakeProfit,
+		Leverage:    &nan,
+		StopLoss:    &inf,
+		RiskPercent: &risk, // Range-checked by the validators
+		TPLevels:    []TPLevel{{Price: 46000, Percentage: 50}, {Price: -47000, Percentage: nan}},
+		EntryLevels: []EntryLevel{{Price: 44000, Percentage: 0}},
+		SLLevels:    []SLLevel{{Price: inf, Percentage: 100}},
+	}
+
+	var got []string
+	for _, err := range CheckNumericFields(cmd) {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		"stop_loss must be a finite number",
+		"leverage must be a finite number",
+		"tp_levels[1].price must be greater than 0",
+		"tp_levels[1].percentage must be a finite number",
+		"sl_levels[0].price must be a finite number",
+		"entry_levels[0].percentage must be greater than 0",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("CheckNumericFields() = %q, want %q", got, want)
+	}
+}
+
+func TestCheckNumericFields_Unset(t *testing.T) {
+	if errs := CheckNumericFields(&NormalizedCommand{Intent: IntentViewPositions}); len(errs) != 0 {
+		t.Errorf("CheckNumericFields() = %v, want none", errs)
+	}
+}
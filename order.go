@@ -0,0 +1,24 @@
+package intent
+
+// OrderType is how an order executes
+type OrderType string
+
+const (
+	OrderTypeMarket    OrderType = "market"
+	OrderTypeLimit     OrderType = "limit"
+	OrderTypeStopLimit OrderType = "stop_limit"
+)
+
+// TimeInForce is how long an order stays on the book
+type TimeInForce string
+
+const (
+	// TimeInForceGTC rests until filled or cancelled
+	TimeInForceGTC TimeInForce = "gtc"
+	// TimeInForceIOC fills what it can immediately and cancels the rest
+	TimeInForceIOC TimeInForce = "ioc"
+	// TimeInForceFOK fills entirely at once or not at all
+	TimeInForceFOK TimeInForce = "fok"
+	// TimeInForcePostOnly only adds liquidity and is rejected if it would match
+	TimeInForcePostOnly TimeInForce = "post_only"
+)
@@ -0,0 +1,137 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is returned when a parse exceeds the per-call timeout set with WithTimeout
+var ErrTimeout = errors.New("intent: parse timed out")
+
+// Coordinates is a geographic position used to resolve location-sensitive entities
+type Coordinates struct {
+	Lat  float64 `json:"lat"`
+	Long float64 `json:"long"`
+}
+
+// ParseOptions carries per-request hints a backend can use to resolve
+// datetime and locale-sensitive entities, e.g. "tomorrow at 9" for a user in
+// Buenos Aires. Zero values mean "not set".
+type ParseOptions struct {
+	ReferenceTime time.Time
+	Timezone      string // IANA name, e.g. "America/Argentina/Buenos_Aires"
+	Locale        string // e.g. "es_AR"
+	Coords        *Coordinates
+
+	// DynamicEntities maps an entity name to extra keywords recognized for
+	// this request only, e.g. {"symbol": watchlist}
+	DynamicEntities map[string][]string
+
+	// Timeout bounds a single parse on top of any deadline already in the
+	// context (see ApplyTimeout)
+	Timeout time.Duration
+
+	// Progress is called every ProgressInterval during the backend call
+	// (see WithProgress and StartProgress)
+	Progress         func(elapsed time.Duration)
+	ProgressInterval time.Duration
+}
+
+// ParseOption configures ParseOptions
+type ParseOption func(*ParseOptions)
+
+// WithReferenceTime sets the time relative expressions are resolved against
+func WithReferenceTime(t time.Time) ParseOption {
+	return func(o *ParseOptions) {
+		o.ReferenceTime = t
+	}
+}
+
+// WithTimezone sets the user's IANA timezone
+func WithTimezone(tz string) ParseOption {
+	return func(o *ParseOptions) {
+		o.Timezone = tz
+	}
+}
+
+// WithLocale sets the user's locale, e.g. "en_US"
+func WithLocale(locale string) ParseOption {
+	return func(o *ParseOptions) {
+		o.Locale = locale
+	}
+}
+
+// WithCoordinates sets the user's position
+func WithCoordinates(lat, long float64) ParseOption {
+	return func(o *ParseOptions) {
+		o.Coords = &Coordinates{Lat: lat, Long: long}
+	}
+}
+
+// WithTimeout bounds a single parse to d, so one slow backend call doesn't
+// consume the whole handler budget. The parse fails with ErrTimeout.
+func WithTimeout(d time.Duration) ParseOption {
+	return func(o *ParseOptions) {
+		o.Timeout = d
+	}
+}
+
+// WithDynamicEntities biases entity recognition towards per-request keywords,
+// e.g. the user's watchlist for the "symbol" entity
+func WithDynamicEntities(entities map[string][]string) ParseOption {
+	return func(o *ParseOptions) {
+		merged := make(map[string][]string, len(o.DynamicEntities)+len(entities))
+		for name, keywords := range o.DynamicEntities {
+			merged[name] = keywords
+		}
+		for name, keywords := range entities {
+			merged[name] = append([]string(nil), keywords...)
+		}
+		o.DynamicEntities = merged
+	}
+}
+
+type parseOptionsContextKey struct{}
+
+// WithParseOptions returns a copy of ctx carrying parse options for the
+// processors that receive it. Options are applied on top of any already in ctx.
+func WithParseOptions(ctx context.Context, opts ...ParseOption) context.Context {
+	o := ParseOptionsFromContext(ctx)
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return context.WithValue(ctx, parseOptionsContextKey{}, o)
+}
+
+// ParseOptionsFromContext returns the parse options stored in ctx, or the
+// zero value when none were set
+func ParseOptionsFromContext(ctx context.Context) ParseOptions {
+	o, _ := ctx.Value(parseOptionsContextKey{}).(ParseOptions)
+	if o.Coords != nil {
+		coords := *o.Coords
+		o.Coords = &coords
+	}
+	return o
+}
+
+// ApplyTimeout layers the per-call timeout from the parse options in ctx onto
+// ctx. Processors call it at the start of a parse and wrap the resulting
+// error with TimeoutError.
+func ApplyTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := ParseOptionsFromContext(ctx).Timeout
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, timeout, ErrTimeout)
+}
+
+// TimeoutError returns err marked as ErrTimeout when ctx (from ApplyTimeout)
+// was cancelled by the per-call timeout, and err unchanged otherwise
+func TimeoutError(ctx context.Context, err error) error {
+	if err == nil || errors.Is(err, ErrTimeout) || !errors.Is(context.Cause(ctx), ErrTimeout) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrTimeout, err)
+}
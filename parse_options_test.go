@@ -0,0 +1,84 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseOptionsContext(t *testing.T) {
+	ctx := context.Background()
+
+	if got := ParseOptionsFromContext(ctx); !got.ReferenceTime.IsZero() || got.Coords != nil || got.DynamicEntities != nil {
+		t.Errorf("ParseOptionsFromContext on empty context = %+v, want zero", got)
+	}
+
+	ref := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	ctx = WithParseOptions(ctx, WithReferenceTime(ref), WithTimezone("America/New_York"))
+	ctx = WithParseOptions(ctx, WithLocale("en_US"), WithCoordinates(40.7, -74))
+
+	got := ParseOptionsFromContext(ctx)
+	if !got.ReferenceTime.Equal(ref) {
+		t.Errorf("ReferenceTime = %v, want %v", got.ReferenceTime, ref)
+	}
+	if got.Timezone != "America/New_York" {
+		t.Errorf("Timezone = %q, want %q", got.Timezone, "America/New_York")
+	}
+	if got.Locale != "en_US" {
+		t.Errorf("Locale = %q, want %q", got.Locale, "en_US")
+	}
+	if got.Coords == nil || got.Coords.Lat != 40.7 || got.Coords.Long != -74 {
+		t.Errorf("Coords = %+v, want {40.7 -74}", got.Coords)
+	}
+
+	// Options are copied out, so mutating them doesn't leak into ctx
+	got.Coords.Lat = 0
+	if again := ParseOptionsFromContext(ctx); again.Coords.Lat != 40.7 {
+		t.Errorf("Coords mutated through returned options")
+	}
+}
+
+func TestWithDynamicEntities(t *testing.T) {
+	watchlist := []string{"PEPE", "WIF"}
+	ctx := WithParseOptions(context.Background(),
+		WithDynamicEntities(map[string][]string{"symbol": watchlist}),
+		WithDynamicEntities(map[string][]string{"account": {"scalping"}}),
+	)
+	watchlist[0] = "DOGE"
+
+	got := ParseOptionsFromContext(ctx).DynamicEntities
+	if len(got) != 2 {
+		t.Fatalf("DynamicEntities = %v, want symbol and account", got)
+	}
+	if got["symbol"][0] != "PEPE" || got["account"][0] != "scalping" {
+		t.Errorf("DynamicEntities = %v", got)
+	}
+}
+
+func TestApplyTimeout(t *testing.T) {
+	ctx, cancel := ApplyTimeout(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("ApplyTimeout without WithTimeout set a deadline")
+	}
+
+	ctx = WithParseOptions(context.Background(), WithTimeout(time.Millisecond))
+	ctx, cancel = ApplyTimeout(ctx)
+	defer cancel()
+	<-ctx.Done()
+
+	err := TimeoutError(ctx, ctx.Err())
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TimeoutError = %v, want ErrTimeout wrapping DeadlineExceeded", err)
+	}
+
+	// Cancellation by the caller is not a timeout
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel = ApplyTimeout(WithParseOptions(parent, WithTimeout(time.Hour)))
+	defer cancel()
+	cancelParent()
+	if err := TimeoutError(ctx, ctx.Err()); errors.Is(err, ErrTimeout) {
+		t.Errorf("TimeoutError after caller cancel = %v, want plain context.Canceled", err)
+	}
+}
@@ -0,0 +1,25 @@
+package intent
+
+// Period is the reporting window of a query like "how much did I make this week"
+type Period string
+
+const (
+	PeriodToday     Period = "today"
+	PeriodYesterday Period = "yesterday"
+	PeriodThisWeek  Period = "this_week"
+	PeriodLastWeek  Period = "last_week"
+	PeriodThisMonth Period = "this_month"
+	PeriodLastMonth Period = "last_month"
+	PeriodThisYear  Period = "this_year"
+	PeriodAllTime   Period = "all_time"
+)
+
+// Known reports whether p is one of the Period constants
+func (p Period) Known() bool {
+	switch p {
+	case PeriodToday, PeriodYesterday, PeriodThisWeek, PeriodLastWeek,
+		PeriodThisMonth, PeriodLastMonth, PeriodThisYear, PeriodAllTime:
+		return true
+	}
+	return false
+}
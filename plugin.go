@@ -0,0 +1,103 @@
+package intent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Plugin bundles optional extension points a third-party module registers
+// at init, e.g. a language pack rewriting slang before parsing or an
+// exchange module validating its own symbols. Nil hooks are skipped.
+type Plugin struct {
+	// Name identifies the plugin; it must be unique
+	Name string
+
+	// Version is recorded in the ParseMeta of every command the plugin
+	// took part in, e.g. "2024.06" for a language pack. Empty leaves it out.
+	Version string
+
+	// PreProcess rewrites user input before it reaches the NLP backend.
+	// RawInput keeps the original input.
+	PreProcess func(ctx context.Context, input string) string
+
+	// PostProcess adjusts the extracted command before validation, e.g. to
+	// fill entities the backend missed
+	PostProcess func(ctx context.Context, cmd *NormalizedCommand)
+
+	// Validate runs after the built-in validation and may append to
+	// Missing and Errors, clearing Valid
+	Validate func(cmd *NormalizedCommand)
+
+	// Emit receives every pipeline event (see EventBus)
+	Emit func(Event)
+}
+
+var (
+	pluginsMu sync.RWMutex
+	plugins   []Plugin
+)
+
+// RegisterPlugin makes p available to processors created afterwards. It is
+// meant to be called from an init function and panics if the name is empty
+// or already registered.
+func RegisterPlugin(p Plugin) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+
+	if p.Name == "" {
+		panic("intent: RegisterPlugin with empty name")
+	}
+	for _, existing := range plugins {
+		if existing.Name == p.Name {
+			panic(fmt.Sprintf("intent: RegisterPlugin called twice for %q", p.Name))
+		}
+	}
+	plugins = append(plugins, p)
+}
+
+// Plugins returns the registered plugins in registration order
+func Plugins() []Plugin {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+	return append([]Plugin(nil), plugins...)
+}
+
+// PreProcessInput runs the PreProcess hooks of plugins in order
+func PreProcessInput(ctx context.Context, plugins []Plugin, input string) string {
+	for _, p := range plugins {
+		if p.PreProcess != nil {
+			input = p.PreProcess(ctx, input)
+		}
+	}
+	return input
+}
+
+// PostProcessCommand runs the PostProcess hooks of plugins in order
+func PostProcessCommand(ctx context.Context, plugins []Plugin, cmd *NormalizedCommand) {
+	for _, p := range plugins {
+		if p.PostProcess != nil {
+			p.PostProcess(ctx, cmd)
+		}
+	}
+}
+
+// ValidateWithPlugins runs the Validate hooks of plugins in order, then
+// adds Issues for what they reported through Missing and Errors
+func ValidateWithPlugins(plugins []Plugin, cmd *NormalizedCommand) {
+	for _, p := range plugins {
+		if p.Validate != nil {
+			p.Validate(cmd)
+		}
+	}
+	cmd.SyncIssues()
+}
+
+// SubscribePlugins subscribes the Emit hooks of plugins to bus
+func SubscribePlugins(bus *EventBus, plugins []Plugin) {
+	for _, p := range plugins {
+		if p.Emit != nil {
+			bus.Subscribe(p.Emit)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package intent
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRegisterPlugin(t *testing.T) {
+	RegisterPlugin(Plugin{Name: "test-register"})
+
+	found := false
+	for _, p := range Plugins() {
+		if p.Name == "test-register" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("registered plugin not returned by Plugins()")
+	}
+
+	for _, p := range []Plugin{{Name: "test-register"}, {}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterPlugin(%q) did not panic", p.Name)
+				}
+			}()
+			RegisterPlugin(p)
+		}()
+	}
+}
+
+func TestPluginHooks(t *testing.T) {
+	ctx := context.Background()
+	plugins := []Plugin{
+		{Name: "lower", PreProcess: func(_ context.Context, s string) string { return strings.ToLower(s) }},
+		{Name: "slang", PreProcess: func(_ context.Context, s string) string { return strings.ReplaceAll(s, "ape into", "buy") }},
+		{Name: "default-symbol", PostProcess: func(_ context.Context, cmd *NormalizedCommand) {
+			if cmd.Symbol == "" {
+				cmd.Symbol = "BTC-USDT"
+			}
+		}},
+		{Name: "no-memes", Validate: func(cmd *NormalizedCommand) {
+			if cmd.Symbol == "DOGE-USDT" {
+				cmd.Errors = append(cmd.Errors, "DOGE is not allowed")
+				cmd.Valid = false
+			}
+		}},
+	}
+
+	if got := PreProcessInput(ctx, plugins, "APE INTO sol"); got != "buy sol" {
+		t.Errorf("PreProcessInput = %q, want %q", got, "buy sol")
+	}
+
+	cmd := &NormalizedCommand{Valid: true}
+	PostProcessCommand(ctx, plugins, cmd)
+	if cmd.Symbol != "BTC-USDT" {
+		t.Errorf("Symbol = %q, want BTC-USDT", cmd.Symbol)
+	}
+
+	cmd = &NormalizedCommand{Symbol: "DOGE-USDT", Valid: true}
+	ValidateWithPlugins(plugins, cmd)
+	if cmd.Valid || len(cmd.Errors) != 1 {
+		t.Errorf("Valid = %v, Errors = %v, want invalid with one error", cmd.Valid, cmd.Errors)
+	}
+}
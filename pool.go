@@ -0,0 +1,116 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrPoolClosed is returned by Pool.Submit after Close has been called
+var ErrPoolClosed = errors.New("intent: pool closed")
+
+// Result is the outcome of a parse submitted to a Pool
+type Result struct {
+	Command *NormalizedCommand
+	Err     error
+}
+
+// Pool owns a Processor and a fixed number of workers, giving servers
+// bounded concurrency and backpressure when parsing high volumes of messages
+type Pool struct {
+	processor Processor
+	jobs      chan poolJob
+	wg        sync.WaitGroup
+
+	mu      sync.RWMutex
+	closed  bool
+	done    chan struct{} // Closed by Close to release blocked submitters
+	senders sync.WaitGroup
+}
+
+type poolJob struct {
+	ctx    context.Context
+	input  string
+	result chan Result
+}
+
+// NewPool starts workers goroutines parsing with processor.
+// queueSize bounds how many submitted inputs may wait for a worker.
+func NewPool(processor Processor, workers, queueSize int) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	p := &Pool{
+		processor: processor,
+		jobs:      make(chan poolJob, queueSize),
+		done:      make(chan struct{}),
+	}
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+
+	return p
+}
+
+// Submit queues input for parsing and returns a channel that receives exactly
+// one Result. It blocks while the queue is full until ctx is done or the pool
+// is closed.
+func (p *Pool) Submit(ctx context.Context, input string) (<-chan Result, error) {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return nil, ErrPoolClosed
+	}
+	p.senders.Add(1)
+	p.mu.RUnlock()
+	defer p.senders.Done()
+
+	job := poolJob{ctx: ctx, input: input, result: make(chan Result, 1)}
+	select {
+	case p.jobs <- job:
+		return job.result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.done:
+		return nil, ErrPoolClosed
+	}
+}
+
+// Close stops accepting new inputs and waits for queued ones to finish.
+// Submit calls blocked on a full queue return ErrPoolClosed.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.done)
+	p.mu.Unlock()
+
+	// The queue can only be closed once no Submit is sending to it
+	p.senders.Wait()
+	close(p.jobs)
+	p.wg.Wait()
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for job := range p.jobs {
+		// Skip inputs whose caller gave up while queued
+		if err := job.ctx.Err(); err != nil {
+			job.result <- Result{Err: err}
+			continue
+		}
+
+		cmd, err := p.processor.ParseCommand(job.ctx, job.input)
+		job.result <- Result{Command: cmd, Err: err}
+	}
+}
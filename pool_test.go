@@ -0,0 +1,131 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPool_SubmitAndReceive(t *testing.T) {
+	pool := NewPool(&echoProcessor{}, 2, 4)
+	defer pool.Close()
+
+	ctx := context.Background()
+	inputs := []string{"a", "b", "c", "d"}
+	results := make([]<-chan Result, len(inputs))
+	for i, input := range inputs {
+		ch, err := pool.Submit(ctx, input)
+		if err != nil {
+			t.Fatalf("Submit(%q) error: %v", input, err)
+		}
+		results[i] = ch
+	}
+
+	for i, ch := range results {
+		res := <-ch
+		if res.Err != nil {
+			t.Fatalf("result %d error: %v", i, res.Err)
+		}
+		if res.Command.Symbol != inputs[i] {
+			t.Errorf("result %d Symbol = %q, want %q", i, res.Command.Symbol, inputs[i])
+		}
+	}
+}
+
+func TestPool_BoundedConcurrency(t *testing.T) {
+	p := &echoProcessor{}
+	pool := NewPool(p, 2, 16)
+
+	for i := 0; i < 10; i++ {
+		pool.Submit(context.Background(), "x")
+	}
+	pool.Close()
+
+	if max := p.maxSeen.Load(); max > 2 {
+		t.Errorf("max concurrent calls = %d, want <= 2", max)
+	}
+}
+
+func TestPool_Backpressure(t *testing.T) {
+	// One worker and no queue: a second submit must wait for the first parse
+	pool := NewPool(&echoProcessor{}, 1, 0)
+	defer pool.Close()
+
+	pool.Submit(context.Background(), "a")
+	pool.Submit(context.Background(), "b")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := pool.Submit(ctx, "c"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Submit on full pool error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestPool_Closed(t *testing.T) {
+	pool := NewPool(&echoProcessor{}, 1, 1)
+	pool.Close()
+	pool.Close() // idempotent
+
+	if _, err := pool.Submit(context.Background(), "a"); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit after Close error = %v, want ErrPoolClosed", err)
+	}
+}
+
+// gateProcessor blocks every parse until release is closed
+type gateProcessor struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (g *gateProcessor) Name() string                 { return "gate" }
+func (g *gateProcessor) SupportedLanguages() []string { return []string{"en"} }
+
+func (g *gateProcessor) ParseCommand(ctx context.Context, input string) (*NormalizedCommand, error) {
+	g.started <- struct{}{}
+	<-g.release
+	return &NormalizedCommand{RawInput: input}, nil
+}
+
+func TestPool_CloseReleasesBlockedSubmit(t *testing.T) {
+	gate := &gateProcessor{started: make(chan struct{}, 1), release: make(chan struct{})}
+	pool := NewPool(gate, 1, 0)
+
+	first, err := pool.Submit(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Submit error: %v", err)
+	}
+	<-gate.started
+
+	// The worker is busy and there is no queue, so this blocks
+	blocked := make(chan error, 1)
+	go func() {
+		_, err := pool.Submit(context.Background(), "b")
+		blocked <- err
+	}()
+
+	closed := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(closed)
+	}()
+
+	select {
+	case err := <-blocked:
+		if !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("blocked Submit error = %v, want ErrPoolClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close didn't release the blocked Submit")
+	}
+
+	close(gate.release)
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close didn't return")
+	}
+	if res := <-first; res.Err != nil || res.Command.RawInput != "a" {
+		t.Errorf("queued result = %+v, want the parsed input", res)
+	}
+}
@@ -0,0 +1,25 @@
+package intent
+
+import (
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// ProfilingMiddleware runs every parse under the pprof label
+// processor=<name> and inside a runtime/trace region, so CPU profiles and
+// execution traces of a busy bot attribute time to backends. Processors that
+// support it (e.g. witai.WithProfiling) add stage and intent labels below.
+func ProfilingMiddleware() ProcessorMiddleware {
+	return func(next Processor) Processor {
+		name := next.Name()
+		return WrapParse(next, func(ctx context.Context, input string) (cmd *NormalizedCommand, err error) {
+			pprof.Do(ctx, pprof.Labels("processor", name), func(ctx context.Context) {
+				trace.WithRegion(ctx, "intent.parse", func() {
+					cmd, err = next.ParseCommand(ctx, input)
+				})
+			})
+			return cmd, err
+		})
+	}
+}
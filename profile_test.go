@@ -0,0 +1,24 @@
+package intent
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestProfilingMiddleware_Labels(t *testing.T) {
+	var label string
+	inner := WrapParse(&stubProcessor{}, func(ctx context.Context, input string) (*NormalizedCommand, error) {
+		label, _ = pprof.Label(ctx, "processor")
+		return &NormalizedCommand{Intent: IntentViewPositions}, nil
+	})
+
+	p := Chain(inner, ProfilingMiddleware())
+	cmd, err := p.ParseCommand(context.Background(), "show my positions")
+	if err != nil || cmd.Intent != IntentViewPositions {
+		t.Fatalf("ParseCommand = (%v, %v)", cmd, err)
+	}
+	if label != "stub" {
+		t.Errorf("processor label = %q, want %q", label, "stub")
+	}
+}
@@ -0,0 +1,57 @@
+package intent
+
+import (
+	"context"
+	"time"
+)
+
+// WithProgress calls fn every interval while the backend call of a parse is
+// in flight, with the time elapsed since the call started. Chat adapters use
+// it to keep a "typing…" indicator alive for exactly as long as a slow
+// backend takes. fn is not called for calls shorter than interval, and never
+// after the parse returns.
+func WithProgress(interval time.Duration, fn func(elapsed time.Duration)) ParseOption {
+	return func(o *ParseOptions) {
+		o.ProgressInterval = interval
+		o.Progress = fn
+	}
+}
+
+// StartProgress starts the progress callback from the parse options in ctx,
+// if any. Processors call it right before the backend call and call the
+// returned stop when the call returns; stop waits for an in-flight callback
+// to finish, so none runs after it.
+func StartProgress(ctx context.Context) (stop func()) {
+	o := ParseOptionsFromContext(ctx)
+	if o.Progress == nil || o.ProgressInterval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		start := time.Now()
+		ticker := time.NewTicker(o.ProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				o.Progress(now.Sub(start))
+			}
+		}
+	}()
+
+	return func() {
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+		<-finished
+	}
+}
@@ -0,0 +1,67 @@
+package intent
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStartProgress_CallsUntilStopped(t *testing.T) {
+	var calls atomic.Int32
+	ctx := WithParseOptions(context.Background(), WithProgress(5*time.Millisecond, func(elapsed time.Duration) {
+		if elapsed <= 0 {
+			t.Errorf("elapsed = %v, want > 0", elapsed)
+		}
+		calls.Add(1)
+	}))
+
+	stop := StartProgress(ctx)
+	time.Sleep(30 * time.Millisecond)
+	stop()
+
+	got := calls.Load()
+	if got == 0 {
+		t.Fatal("progress callback was never called")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if calls.Load() != got {
+		t.Errorf("progress callback called after stop")
+	}
+	stop() // stopping twice is harmless
+}
+
+func TestStartProgress_FastCall(t *testing.T) {
+	var calls atomic.Int32
+	ctx := WithParseOptions(context.Background(), WithProgress(time.Hour, func(time.Duration) {
+		calls.Add(1)
+	}))
+
+	StartProgress(ctx)()
+
+	if calls.Load() != 0 {
+		t.Errorf("calls = %d, want 0 for a call shorter than the interval", calls.Load())
+	}
+}
+
+func TestStartProgress_NotConfigured(t *testing.T) {
+	stop := StartProgress(context.Background())
+	stop()
+}
+
+func TestStartProgress_StopsWithContext(t *testing.T) {
+	var calls atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = WithParseOptions(ctx, WithProgress(5*time.Millisecond, func(time.Duration) {
+		calls.Add(1)
+	}))
+
+	stop := StartProgress(ctx)
+	defer stop()
+	cancel()
+	time.Sleep(30 * time.Millisecond)
+
+	if calls.Load() > 1 {
+		t.Errorf("calls = %d after context cancellation, want at most 1", calls.Load())
+	}
+}
@@ -0,0 +1,55 @@
+package intent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+)
+
+// PanicError is returned by RecoverMiddleware when the wrapped processor panics
+type PanicError struct {
+	Processor string
+	Value     any    // Value passed to panic
+	Stack     []byte // Stack trace captured at recovery
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("processor %s panicked: %v", e.Processor, e.Value)
+}
+
+// Unwrap returns the panic value when it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// RecoverMiddleware returns a middleware that converts panics in the wrapped
+// processor (including its transformer) into a *PanicError, so a malformed
+// backend payload fails one command instead of crashing the process. Panics
+// are logged with their stack at error level through handler; a nil handler
+// disables logging.
+func RecoverMiddleware(handler slog.Handler) ProcessorMiddleware {
+	if handler == nil {
+		handler = slog.DiscardHandler
+	}
+	logger := slog.New(handler)
+
+	return func(next Processor) Processor {
+		return WrapParse(next, func(ctx context.Context, input string) (cmd *NormalizedCommand, err error) {
+			defer func() {
+				if v := recover(); v != nil {
+					perr := &PanicError{Processor: next.Name(), Value: v, Stack: debug.Stack()}
+					logger.ErrorContext(ctx, "processor panic recovered",
+						slog.String("processor", perr.Processor),
+						slog.String("user_id", UserIDFromContext(ctx)),
+						slog.Any("panic", v),
+						slog.String("stack", string(perr.Stack)),
+					)
+					cmd, err = nil, perr
+				}
+			}()
+			return next.ParseCommand(ctx, input)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package intent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	boom := errors.New("bad payload")
+	panicking := WrapParse(&stubProcessor{}, func(ctx context.Context, input string) (*NormalizedCommand, error) {
+		panic(boom)
+	})
+
+	var buf bytes.Buffer
+	p := Chain(panicking, RecoverMiddleware(slog.NewJSONHandler(&buf, nil)))
+
+	cmd, err := p.ParseCommand(context.Background(), "open long BTC")
+	if cmd != nil {
+		t.Errorf("cmd = %+v, want nil", cmd)
+	}
+
+	var perr *PanicError
+	if !errors.As(err, &perr) {
+		t.Fatalf("err = %v, want *PanicError", err)
+	}
+	if perr.Processor != "stub" {
+		t.Errorf("Processor = %q, want %q", perr.Processor, "stub")
+	}
+	if len(perr.Stack) == 0 {
+		t.Error("Stack is empty")
+	}
+	if !errors.Is(err, boom) {
+		t.Errorf("errors.Is(err, boom) = false, want true")
+	}
+
+	if out := buf.String(); !strings.Contains(out, "processor panic recovered") || !strings.Contains(out, "stack") {
+		t.Errorf("log output = %q, want panic record with stack", out)
+	}
+}
+
+func TestRecoverMiddleware_PassesThrough(t *testing.T) {
+	p := Chain(&stubProcessor{}, RecoverMiddleware(slog.NewTextHandler(io.Discard, nil)))
+
+	cmd, err := p.ParseCommand(context.Background(), "check balance")
+	if err != nil || cmd == nil {
+		t.Fatalf("ParseCommand = (%v, %v), want a command", cmd, err)
+	}
+}
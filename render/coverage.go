@@ -0,0 +1,96 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// LanguageCoverage lists what the catalog of one language lacks. Gaps don't
+// fail rendering: the raw name is shown instead ("set take profit"), so
+// they surface as untranslated text in front of users.
+type LanguageCoverage struct {
+	Language string
+
+	// Intents counts the intents checked; MissingIntents have no wording
+	// and MissingExamples no sample phrasing for Help
+	Intents         int
+	MissingIntents  []intent.Intent
+	MissingExamples []intent.Intent
+
+	// Fields counts the field labels of all catalogs together;
+	// MissingFields are labeled by another language but not this one
+	Fields        int
+	MissingFields []string
+}
+
+// Complete reports whether the language covers every intent and field
+func (c LanguageCoverage) Complete() bool {
+	return len(c.MissingIntents) == 0 && len(c.MissingExamples) == 0 && len(c.MissingFields) == 0
+}
+
+// Coverage reports, for every supported language, which of intents have
+// no wording or example and which field labels it lacks compared to the other
+// languages. Pass the intents a backend can produce, e.g. the mapped
+// witai.CanonicalIntents.
+func Coverage(intents []intent.Intent) []LanguageCoverage {
+	fields := make(map[string]bool)
+	for _, m := range catalog {
+		for name := range m.fields {
+			fields[name] = true
+		}
+	}
+	names := slices.Sorted(maps.Keys(fields))
+
+	var report []LanguageCoverage
+	for _, lang := range Languages() {
+		m := catalog[lang]
+		c := LanguageCoverage{Language: lang, Intents: len(intents), Fields: len(names)}
+		for _, i := range intents {
+			if _, ok := m.intents[i]; !ok {
+				c.MissingIntents = append(c.MissingIntents, i)
+			}
+			if _, ok := m.examples[i]; !ok {
+				c.MissingExamples = append(c.MissingExamples, i)
+			}
+		}
+		for _, name := range names {
+			if _, ok := m.fields[name]; !ok {
+				c.MissingFields = append(c.MissingFields, name)
+			}
+		}
+		report = append(report, c)
+	}
+	return report
+}
+
+// WriteCoverage writes report as plain text, one block per language
+func WriteCoverage(w io.Writer, report []LanguageCoverage) error {
+	for _, c := range report {
+		_, err := fmt.Fprintf(w, "%s: intents %d/%d, examples %d/%d, fields %d/%d\n", c.Language,
+			c.Intents-len(c.MissingIntents), c.Intents, c.Intents-len(c.MissingExamples), c.Intents,
+			c.Fields-len(c.MissingFields), c.Fields)
+		if err != nil {
+			return err
+		}
+		for _, i := range c.MissingIntents {
+			if _, err := fmt.Fprintf(w, "  missing intent %s\n", i); err != nil {
+				return err
+			}
+		}
+		for _, i := range c.MissingExamples {
+			if _, err := fmt.Fprintf(w, "  missing example %s\n", i); err != nil {
+				return err
+			}
+		}
+		for _, name := range c.MissingFields {
+			if _, err := fmt.Fprintf(w, "  missing field %s\n", name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package render
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/witai"
+)
+
+// canonicalIntents are the intents the Wit.ai backend can produce
+func canonicalIntents() []intent.Intent {
+	var intents []intent.Intent
+	for _, name := range witai.CanonicalIntents() {
+		intents = append(intents, intent.Intent(name))
+	}
+	return intents
+}
+
+func TestCoverage_EveryLanguageComplete(t *testing.T) {
+	for _, c := range Coverage(canonicalIntents()) {
+		for _, i := range c.MissingIntents {
+			t.Errorf("%s catalog has no wording for intent %s", c.Language, i)
+		}
+		for _, i := range c.MissingExamples {
+			t.Errorf("%s catalog has no example for intent %s", c.Language, i)
+		}
+		for _, name := range c.MissingFields {
+			t.Errorf("%s catalog has no label for field %s", c.Language, name)
+		}
+	}
+}
+
+func TestCoverage_ReportsGaps(t *testing.T) {
+	report := Coverage([]intent.Intent{intent.IntentViewPrice, "chart_analysis"})
+	if len(report) != len(Languages()) {
+		t.Fatalf("got %d languages, want %d", len(report), len(Languages()))
+	}
+	for _, c := range report {
+		if c.Complete() || len(c.MissingIntents) != 1 || c.MissingIntents[0] != "chart_analysis" {
+			t.Errorf("%s MissingIntents = %v, want [chart_analysis]", c.Language, c.MissingIntents)
+		}
+	}
+}
+
+func TestCoverage_Report(t *testing.T) {
+	var out strings.Builder
+	if err := WriteCoverage(&out, Coverage(canonicalIntents())); err != nil {
+		t.Fatalf("WriteCoverage error: %v", err)
+	}
+	checkGolden(t, filepath.Join("testdata", "coverage.golden"), out.String())
+}
+
+func TestHelp(t *testing.T) {
+	intents := []intent.Intent{intent.IntentViewPrice, intent.IntentHelp, "chart_analysis"}
+
+	want := "I can:\n- show the price: \"what's the price of ETH\"\n- chart analysis"
+	if got := Help(intents, "en"); got != want {
+		t.Errorf("Help(en) = %q, want %q", got, want)
+	}
+	if got := Help(intents, "es_AR"); !strings.HasPrefix(got, "Puedo:\n- mostrar el precio: \"precio de ETH\"") {
+		t.Errorf("Help(es_AR) = %q, want the Spanish list", got)
+	}
+}
+
+func TestHelp_Golden(t *testing.T) {
+	for _, lang := range Languages() {
+		t.Run(lang, func(t *testing.T) {
+			checkGolden(t, filepath.Join("testdata", "help."+lang+".golden"), Help(canonicalIntents(), lang)+"\n")
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package render
+
+import "github.com/agatticelli/intent-go"
+
+// messages is the wording of one language
+type messages struct {
+	// intents names what each intent does, as in "To <action> I still need ..."
+	intents map[intent.Intent]string
+
+	// examples are sample phrasings of each intent, listed by Help
+	examples map[intent.Intent]string
+
+	// fields labels command fields and the names validators report as missing
+	fields map[string]string
+
+	sides   map[intent.Side]string
+	periods map[intent.Period]string
+
+	and     string // Joins the last two items of a list
+	or      string // Joins alternatives like "stop loss or take profit"
+	confirm string // %s is the summary
+	caution string // %s are the summary and the warnings
+	missing string // %s are the action and the missing fields
+	invalid string // %s are the action and the errors
+	unknown string
+	help    string // Heads the list of Help
+}
+
+var catalog = map[string]*messages{
+	"en": {
+		intents: map[intent.Intent]string{
+			intent.IntentOpenPosition:     "open a position",
+			intent.IntentClosePosition:    "close the position",
+			intent.IntentViewPositions:    "show your positions",
+			intent.IntentViewOrders:       "show your orders",
+			intent.IntentCancelOrders:     "cancel orders",
+			intent.IntentCheckBalance:     "check your balance",
+			intent.IntentBreakEven:        "move the stop to break even",
+			intent.IntentTrailingStop:     "set a trailing stop",
+			intent.IntentCopyTrade:        "copy trades",
+			intent.IntentSetupGrid:        "set up a grid",
+			intent.IntentViewAlerts:       "show your alerts",
+			intent.IntentCancelAlert:      "cancel the alert",
+			intent.IntentSetRiskDefaults:  "change your default risk",
+			intent.IntentWithdraw:         "withdraw funds",
+			intent.IntentModifyPosition:   "modify the position",
+			intent.IntentSetLeverage:      "set the leverage",
+			intent.IntentDCAOrder:         "place a DCA ladder",
+			intent.IntentSetAlert:         "set a price alert",
+			intent.IntentViewPnL:          "show your PnL",
+			intent.IntentViewPrice:        "show the price",
+			intent.IntentViewFunding:      "show funding rates",
+			intent.IntentHedgePosition:    "hedge the position",
+			intent.IntentMoveStopLoss:     "move the stop loss",
+			intent.IntentViewHistory:      "show your trade history",
+			intent.IntentCheckOrderStatus: "check the order status",
+			intent.IntentViewPosition:     "show the position",
+			intent.IntentPanicClose:       "close every position at market",
+			intent.IntentSetTakeProfit:    "set the take profit",
+			intent.IntentSetStopLoss:      "set the stop loss",
+			intent.IntentHelp:             "show what I can do",
+			intent.IntentCloseAll:         "close all your positions",
+		},
+		examples: map[intent.Intent]string{
+			intent.IntentOpenPosition:     "open long BTC at 45000 with stop loss 44500 and risk 2%",
+			intent.IntentClosePosition:    "close 50% of my ETH position",
+			intent.IntentViewPositions:    "show my positions",
+			intent.IntentViewOrders:       "show my orders",
+			intent.IntentCancelOrders:     "cancel all my BTC orders",
+			intent.IntentCheckBalance:     "what's my balance",
+			intent.IntentBreakEven:        "move my BTC stop to break even",
+			intent.IntentTrailingStop:     "set trailing stop on BTC at 1%",
+			intent.IntentCopyTrade:        "copy trades from main to scalping at half size",
+			intent.IntentSetupGrid:        "grid on ETH from 3000 to 3500 with 10 levels",
+			intent.IntentViewAlerts:       "show my alerts",
+			intent.IntentCancelAlert:      "cancel my BTC alerts",
+			intent.IntentSetRiskDefaults:  "set my default risk to 1%",
+			intent.IntentWithdraw:         "withdraw 100 USDT to ledger",
+			intent.IntentModifyPosition:   "change my ETH stop to 2900",
+			intent.IntentSetLeverage:      "set BTC leverage to 10x",
+			intent.IntentDCAOrder:         "DCA into BTC at 44000 and 43000",
+			intent.IntentSetAlert:         "alert me when BTC goes above 50000",
+			intent.IntentViewPnL:          "how much did I make this week",
+			intent.IntentViewPrice:        "what's the price of ETH",
+			intent.IntentViewFunding:      "what's the funding on BTC",
+			intent.IntentHedgePosition:    "hedge 50% of my BTC long",
+			intent.IntentMoveStopLoss:     "move my BTC stop up 200",
+			intent.IntentViewHistory:      "show my trades from last week",
+			intent.IntentCheckOrderStatus: "did my last order fill?",
+			intent.IntentViewPosition:     "how's my BTC trade doing",
+			intent.IntentPanicClose:       "flatten everything now",
+			intent.IntentSetTakeProfit:    "set TP for my ETH at 3200",
+			intent.IntentSetStopLoss:      "put a stop on my BTC at 44000",
+			intent.IntentHelp:             "what can you do?",
+			intent.IntentCloseAll:         "close everything except ETH",
+		},
+		fields: map[string]string{
+			"symbol":           "symbol",
+			"exclude_symbols":  "except",
+			"side":             "side",
+			"exchange":         "exchange",
+			"account":          "account",
+			"entry_price":      "entry price",
+			"stop_loss":        "stop loss",
+			"stop_loss_offset": "stop loss offset",
+			"take_profit":      "take profit",
+			"tp_levels":        "take profit levels",
+			"trigger_price":    "trigger price",
+			"risk_percent":     "risk",
+			"leverage":         "leverage",
+			"quantity":         "quantity",
+			"notional_usd":     "notional",
+			"close_percent":    "close",
+			"hedge_ratio":      "hedge ratio",
+			"callback_rate":    "callback rate",
+			"distance":         "distance",
+			"target_account":   "target account",
+			"grid_lower":       "lower bound",
+			"grid_upper":       "upper bound",
+			"grid_levels":      "grid levels",
+			"entry_levels":     "entry levels",
+			"alert_id":         "alert",
+			"alert_price":      "alert price",
+			"order_id":         "order ID",
+			"asset":            "asset",
+			"amount":           "amount",
+			"address_ref":      "address",
+			"period":           "period",
+			"timeframe":        "timeframe",
+			"from":             "from",
+			"to":               "until",
+		},
+		sides: map[intent.Side]string{intent.SideLong: "long", intent.SideShort: "short"},
+		periods: map[intent.Period]string{
+			intent.PeriodToday:     "today",
+			intent.PeriodYesterday: "yesterday",
+			intent.PeriodThisWeek:  "this week",
+			intent.PeriodLastWeek:  "last week",
+			intent.PeriodThisMonth: "this month",
+			intent.PeriodLastMonth: "last month",
+			intent.PeriodThisYear:  "this year",
+			intent.PeriodAllTime:   "all time",
+		},
+		and:     "and",
+		or:      "or",
+		confirm: "%s. Confirm?",
+		caution: "%s. Heads up: %s. Are you sure?",
+		missing: "To %s I still need: %s.",
+		invalid: "I can't %s: %s.",
+		unknown: "Sorry, I didn't understand that.",
+		help:    "I can:",
+	},
+	"es": {
+		intents: map[intent.Intent]string{
+			intent.IntentOpenPosition:     "abrir una posición",
+			intent.IntentClosePosition:    "cerrar la posición",
+			intent.IntentViewPositions:    "mostrar tus posiciones",
+			intent.IntentViewOrders:       "mostrar tus órdenes",
+			intent.IntentCancelOrders:     "cancelar órdenes",
+			intent.IntentCheckBalance:     "consultar tu balance",
+			intent.IntentBreakEven:        "mover el stop a break even",
+			intent.IntentTrailingStop:     "poner un trailing stop",
+			intent.IntentCopyTrade:        "copiar operaciones",
+			intent.IntentSetupGrid:        "configurar un grid",
+			intent.IntentViewAlerts:       "mostrar tus alertas",
+			intent.IntentCancelAlert:      "cancelar la alerta",
+			intent.IntentSetRiskDefaults:  "cambiar tu riesgo por defecto",
+			intent.IntentWithdraw:         "retirar fondos",
+			intent.IntentModifyPosition:   "modificar la posición",
+			intent.IntentSetLeverage:      "cambiar el apalancamiento",
+			intent.IntentDCAOrder:         "escalonar entradas",
+			intent.IntentSetAlert:         "crear una alerta de precio",
+			intent.IntentViewPnL:          "mostrar tu PnL",
+			intent.IntentViewPrice:        "mostrar el precio",
+			intent.IntentViewFunding:      "mostrar el funding",
+			intent.IntentHedgePosition:    "cubrir la posición",
+			intent.IntentMoveStopLoss:     "mover el stop loss",
+			intent.IntentViewHistory:      "mostrar tu historial de operaciones",
+			intent.IntentCheckOrderStatus: "consultar el estado de la orden",
+			intent.IntentViewPosition:     "mostrar la posición",
+			intent.IntentPanicClose:       "cerrar todas las posiciones a mercado",
+			intent.IntentSetTakeProfit:    "poner el take profit",
+			intent.IntentSetStopLoss:      "poner el stop loss",
+			intent.IntentHelp:             "mostrar lo que puedo hacer",
+			intent.IntentCloseAll:         "cerrar todas tus posiciones",
+		},
+		examples: map[intent.Intent]string{
+			intent.IntentOpenPosition:     "abrir largo BTC en 45000 con stop loss 44500 y riesgo 2%",
+			intent.IntentClosePosition:    "cerrar 50% de mi posición de ETH",
+			intent.IntentViewPositions:    "mostrar mis posiciones",
+			intent.IntentViewOrders:       "mostrar mis órdenes",
+			intent.IntentCancelOrders:     "cancelar todas mis órdenes de BTC",
+			intent.IntentCheckBalance:     "cuánto tengo de balance",
+			intent.IntentBreakEven:        "mover el stop de BTC a break even",
+			intent.IntentTrailingStop:     "poner trailing stop en BTC al 1%",
+			intent.IntentCopyTrade:        "copiar operaciones de main a scalping a la mitad",
+			intent.IntentSetupGrid:        "grid en ETH de 3000 a 3500 con 10 niveles",
+			intent.IntentViewAlerts:       "mostrar mis alertas",
+			intent.IntentCancelAlert:      "cancelar mis alertas de BTC",
+			intent.IntentSetRiskDefaults:  "poner mi riesgo por defecto en 1%",
+			intent.IntentWithdraw:         "retirar 100 USDT a ledger",
+			intent.IntentModifyPosition:   "cambiar el stop de ETH a 2900",
+			intent.IntentSetLeverage:      "poner apalancamiento 10x en BTC",
+			intent.IntentDCAOrder:         "entrar escalonado en BTC en 44000 y 43000",
+			intent.IntentSetAlert:         "avisame cuando BTC pase 50000",
+			intent.IntentViewPnL:          "cuánto gané esta semana",
+			intent.IntentViewPrice:        "precio de ETH",
+			intent.IntentViewFunding:      "funding de BTC",
+			intent.IntentHedgePosition:    "cubrir 50% de mi long de BTC",
+			intent.IntentMoveStopLoss:     "subir el stop de BTC 200",
+			intent.IntentViewHistory:      "historial de operaciones de la semana pasada",
+			intent.IntentCheckOrderStatus: "se ejecutó mi última orden?",
+			intent.IntentViewPosition:     "cómo va mi trade de BTC",
+			intent.IntentPanicClose:       "cerrá todo ya",
+			intent.IntentSetTakeProfit:    "poné el TP de ETH en 3200",
+			intent.IntentSetStopLoss:      "poné stop en 44000 a mi BTC",
+			intent.IntentHelp:             "qué podés hacer?",
+			intent.IntentCloseAll:         "cerrá todo menos ETH",
+		},
+		fields: map[string]string{
+			"symbol":           "símbolo",
+			"exclude_symbols":  "excepto",
+			"side":             "dirección",
+			"exchange":         "exchange",
+			"account":          "cuenta",
+			"entry_price":      "precio de entrada",
+			"stop_loss":        "stop loss",
+			"stop_loss_offset": "desplazamiento del stop",
+			"take_profit":      "take profit",
+			"tp_levels":        "niveles de take profit",
+			"trigger_price":    "precio de activación",
+			"risk_percent":     "riesgo",
+			"leverage":         "apalancamiento",
+			"quantity":         "cantidad",
+			"notional_usd":     "monto en dólares",
+			"close_percent":    "cierre",
+			"hedge_ratio":      "cobertura",
+			"callback_rate":    "tasa de retroceso",
+			"distance":         "distancia",
+			"target_account":   "cuenta destino",
+			"grid_lower":       "límite inferior",
+			"grid_upper":       "límite superior",
+			"grid_levels":      "niveles del grid",
+			"entry_levels":     "niveles de entrada",
+			"alert_id":         "alerta",
+			"alert_price":      "precio de alerta",
+			"order_id":         "ID de orden",
+			"asset":            "activo",
+			"amount":           "monto",
+			"address_ref":      "dirección de retiro",
+			"period":           "período",
+			"timeframe":        "temporalidad",
+			"from":             "desde",
+			"to":               "hasta",
+		},
+		sides: map[intent.Side]string{intent.SideLong: "largo", intent.SideShort: "corto"},
+		periods: map[intent.Period]string{
+			intent.PeriodToday:     "hoy",
+			intent.PeriodYesterday: "ayer",
+			intent.PeriodThisWeek:  "esta semana",
+			intent.PeriodLastWeek:  "la semana pasada",
+			intent.PeriodThisMonth: "este mes",
+			intent.PeriodLastMonth: "el mes pasado",
+			intent.PeriodThisYear:  "este año",
+			intent.PeriodAllTime:   "desde siempre",
+		},
+		and:     "y",
+		or:      "o",
+		confirm: "%s. ¿Confirmás?",
+		caution: "%s. Ojo: %s. ¿Estás seguro?",
+		missing: "Para %s todavía necesito: %s.",
+		invalid: "No puedo %s: %s.",
+		unknown: "Perdón, no entendí.",
+		help:    "Puedo:",
+	},
+}
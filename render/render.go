@@ -0,0 +1,224 @@
+// Package render turns commands into user-facing text: confirmations to
+// show before executing and prompts asking for missing parameters.
+// English and Spanish are supported; other languages fall back to English.
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// DefaultLanguage is used for languages without a catalog
+const DefaultLanguage = "en"
+
+// Languages returns the supported language codes
+func Languages() []string {
+	return []string{"en", "es"}
+}
+
+// Confirmation summarizes a valid command and asks the user to confirm it,
+// e.g. "Open a position: long BTC-USDT, entry price 45000, stop loss 44500,
+// risk 2%. Confirm?". Validation warnings are spelled out before asking.
+func Confirmation(cmd *intent.NormalizedCommand, lang string) string {
+	m := lookup(lang)
+	summary := capitalize(m.action(cmd.Intent))
+	if details := m.details(cmd); len(details) > 0 {
+		summary += ": " + strings.Join(details, ", ")
+	}
+	if len(cmd.Warnings) > 0 {
+		return fmt.Sprintf(m.caution, summary, strings.Join(cmd.Warnings, "; "))
+	}
+	return fmt.Sprintf(m.confirm, summary)
+}
+
+// Clarification asks for what keeps cmd from being valid: its missing
+// parameters, otherwise its validation errors. It returns "" for a valid command.
+func Clarification(cmd *intent.NormalizedCommand, lang string) string {
+	m := lookup(lang)
+	if cmd.Intent == intent.IntentUnknown {
+		return m.unknown
+	}
+
+	if len(cmd.Missing) > 0 {
+		var fields []string
+		for _, name := range cmd.Missing {
+			fields = append(fields, m.missingField(name))
+		}
+		return fmt.Sprintf(m.missing, m.action(cmd.Intent), m.list(fields))
+	}
+
+	if len(cmd.Errors) > 0 {
+		return fmt.Sprintf(m.invalid, m.action(cmd.Intent), strings.Join(cmd.Errors, "; "))
+	}
+	return ""
+}
+
+// Help answers "what can you do?" in lang with one line per intent and a
+// sample phrasing, e.g. `- show the price: "what's the price of ETH"`.
+// Pass the intents the backend can produce, such as the mapped
+// witai.CanonicalIntents, so the answer follows new intents without edits.
+// IntentHelp and IntentUnknown are left out.
+func Help(intents []intent.Intent, lang string) string {
+	m := lookup(lang)
+	lines := []string{m.help}
+	for _, i := range intents {
+		if i == intent.IntentHelp || i == intent.IntentUnknown {
+			continue
+		}
+		line := "- " + m.action(i)
+		if example, ok := m.examples[i]; ok {
+			line += `: "` + example + `"`
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lookup returns the catalog of lang, accepting locales like "es_AR" or "es-AR"
+func lookup(lang string) *messages {
+	base, _, _ := strings.Cut(strings.ToLower(lang), "_")
+	base, _, _ = strings.Cut(base, "-")
+	if m, ok := catalog[base]; ok {
+		return m
+	}
+	return catalog[DefaultLanguage]
+}
+
+func (m *messages) action(i intent.Intent) string {
+	if action, ok := m.intents[i]; ok {
+		return action
+	}
+	_, name := intent.SplitIntent(i)
+	return strings.ReplaceAll(name, "_", " ")
+}
+
+func (m *messages) field(name string) string {
+	if label, ok := m.fields[name]; ok {
+		return label
+	}
+	return strings.ReplaceAll(name, "_", " ")
+}
+
+// missingField labels a name reported in Missing, including alternatives
+// like "stop_loss or take_profit", listed as "a, b or c"
+func (m *messages) missingField(name string) string {
+	alternatives := strings.Split(name, " or ")
+	for i, alt := range alternatives {
+		alternatives[i] = m.field(alt)
+	}
+	last := len(alternatives) - 1
+	if last == 0 {
+		return alternatives[0]
+	}
+	return strings.Join(alternatives[:last], ", ") + " " + m.or + " " + alternatives[last]
+}
+
+// list joins items as "a, b and c"
+func (m *messages) list(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " " + m.and + " " + items[len(items)-1]
+}
+
+// details renders the parameters of cmd in a stable order
+func (m *messages) details(cmd *intent.NormalizedCommand) []string {
+	var details []string
+
+	target := cmd.Symbol
+	if cmd.Side != nil {
+		target = strings.TrimSpace(m.sides[*cmd.Side] + " " + target)
+	}
+	if target != "" {
+		details = append(details, target)
+	}
+	if len(cmd.ExcludeSymbols) > 0 {
+		details = append(details, m.field("exclude_symbols")+" "+m.list(cmd.ExcludeSymbols))
+	}
+	if cmd.Exchange != "" {
+		details = append(details, m.field("exchange")+" "+cmd.Exchange)
+	}
+	if cmd.Account != "" {
+		details = append(details, m.field("account")+" "+cmd.Account)
+	}
+
+	add := func(name string, value *float64, suffix string) {
+		if value != nil {
+			details = append(details, m.field(name)+" "+formatNumber(*value)+suffix)
+		}
+	}
+	add("entry_price", cmd.EntryPrice, "")
+	add("stop_loss", cmd.StopLoss, "")
+	add("stop_loss_offset", cmd.StopLossOffset, "")
+	add("take_profit", cmd.TakeProfit, "")
+	add("trigger_price", cmd.TriggerPrice, "")
+	add("risk_percent", cmd.RiskPercent, "%")
+	add("leverage", cmd.Leverage, "x")
+	add("quantity", cmd.Quantity, "")
+	if cmd.NotionalUSD != nil {
+		details = append(details, m.field("notional_usd")+" $"+formatNumber(*cmd.NotionalUSD))
+	}
+	add("close_percent", cmd.ClosePercent, "%")
+	if cmd.HedgeRatio != nil {
+		details = append(details, m.field("hedge_ratio")+" "+formatNumber(*cmd.HedgeRatio*100)+"%")
+	}
+	add("callback_rate", cmd.CallbackRate, "%")
+	add("distance", cmd.Distance, "")
+	add("alert_price", cmd.AlertPrice, "")
+
+	for _, tp := range cmd.TPLevels {
+		details = append(details, m.field("take_profit")+" "+formatNumber(tp.Price)+" ("+formatNumber(tp.Percentage)+"%)")
+	}
+	for _, sl := range cmd.SLLevels {
+		details = append(details, m.field("stop_loss")+" "+formatNumber(sl.Price)+" ("+formatNumber(sl.Percentage)+"%)")
+	}
+	for _, level := range cmd.EntryLevels {
+		details = append(details, m.field("entry_price")+" "+formatNumber(level.Price)+" ("+formatNumber(level.Percentage)+"%)")
+	}
+
+	if cmd.Amount != nil {
+		details = append(details, m.field("amount")+" "+strings.TrimSpace(formatNumber(*cmd.Amount)+" "+cmd.Asset))
+	}
+	if cmd.AddressRef != "" {
+		details = append(details, m.field("address_ref")+" "+cmd.AddressRef)
+	}
+	if cmd.OrderID != "" {
+		details = append(details, m.field("order_id")+" "+cmd.OrderID)
+	}
+	if cmd.AlertID != "" {
+		details = append(details, m.field("alert_id")+" "+cmd.AlertID)
+	}
+	if cmd.Period != "" {
+		period, ok := m.periods[cmd.Period]
+		if !ok {
+			period = strings.ReplaceAll(string(cmd.Period), "_", " ")
+		}
+		details = append(details, m.field("period")+" "+period)
+	}
+	if cmd.Timeframe != "" {
+		details = append(details, m.field("timeframe")+" "+cmd.Timeframe)
+	}
+	if cmd.From != nil {
+		details = append(details, m.field("from")+" "+cmd.From.Format(time.DateOnly))
+	}
+	if cmd.To != nil {
+		details = append(details, m.field("to")+" "+cmd.To.Format(time.DateOnly))
+	}
+	return details
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	return strings.ToUpper(string(r[0])) + string(r[1:])
+}
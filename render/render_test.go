@@ -0,0 +1,169 @@
+package render
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/validators"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func float64Ptr(f float64) *float64 { return &f }
+
+func sidePtr(s intent.Side) *intent.Side { return &s }
+
+// goldenCommands covers every rendering path: plain and ladder summaries,
+// missing parameters, alternatives, validation errors and unknown input
+var goldenCommands = []struct {
+	name   string
+	cmd    *intent.NormalizedCommand
+	policy *validators.Policy // DefaultPolicy when nil
+}{
+	{"open_position", &intent.NormalizedCommand{
+		Intent:      intent.IntentOpenPosition,
+		Symbol:      "BTC-USDT",
+		Side:        sidePtr(intent.SideLong),
+		EntryPrice:  float64Ptr(45000),
+		StopLoss:    float64Ptr(44500),
+		RiskPercent: float64Ptr(2),
+		Leverage:    float64Ptr(10),
+		TPLevels:    []intent.TPLevel{{Price: 46000, Percentage: 50}, {Price: 47000, Percentage: 50}},
+	}, nil},
+	{"open_position_warning", &intent.NormalizedCommand{
+		Intent:      intent.IntentOpenPosition,
+		Symbol:      "BTC-USDT",
+		Side:        sidePtr(intent.SideLong),
+		EntryPrice:  float64Ptr(4500),
+		StopLoss:    float64Ptr(4400),
+		RiskPercent: float64Ptr(2),
+		TakeProfit:  float64Ptr(46000),
+	}, &validators.Policy{Tolerance: validators.DefaultTolerance, MaxTakeProfitDistance: 0.5}},
+	{"open_position_missing", &intent.NormalizedCommand{
+		Intent: intent.IntentOpenPosition,
+		Symbol: "ETH-USDT",
+	}, nil},
+	{"close_position_partial", &intent.NormalizedCommand{
+		Intent:       intent.IntentClosePosition,
+		Symbol:       "ETH-USDT",
+		ClosePercent: float64Ptr(50),
+	}, nil},
+	{"modify_position_missing", &intent.NormalizedCommand{
+		Intent: intent.IntentModifyPosition,
+		Symbol: "SOL-USDT",
+	}, nil},
+	{"dca_order", &intent.NormalizedCommand{
+		Intent:      intent.IntentDCAOrder,
+		Symbol:      "BTC-USDT",
+		EntryLevels: []intent.EntryLevel{{Price: 44000, Percentage: 60}, {Price: 43000, Percentage: 40}},
+	}, nil},
+	{"withdraw", &intent.NormalizedCommand{
+		Intent:     intent.IntentWithdraw,
+		Asset:      "USDT",
+		Amount:     float64Ptr(250.5),
+		AddressRef: "ledger",
+	}, nil},
+	{"hedge_position", &intent.NormalizedCommand{
+		Intent:     intent.IntentHedgePosition,
+		Symbol:     "BTC-USDT",
+		Side:       sidePtr(intent.SideLong),
+		HedgeRatio: float64Ptr(0.5),
+	}, nil},
+	{"cancel_order", &intent.NormalizedCommand{
+		Intent:  intent.IntentCancelOrders,
+		OrderID: "12345",
+	}, nil},
+	{"view_pnl", &intent.NormalizedCommand{
+		Intent: intent.IntentViewPnL,
+		Period: intent.PeriodThisWeek,
+	}, nil},
+	{"set_leverage_out_of_range", &intent.NormalizedCommand{
+		Intent:   intent.IntentSetLeverage,
+		Symbol:   "BTC-USDT",
+		Leverage: float64Ptr(500),
+	}, nil},
+	{"panic_close", &intent.NormalizedCommand{
+		Intent:   intent.IntentPanicClose,
+		Exchange: "binance",
+	}, nil},
+	{"close_all", &intent.NormalizedCommand{
+		Intent:         intent.IntentCloseAll,
+		ExcludeSymbols: []string{"ETH-USDT", "SOL-USDT"},
+	}, nil},
+	{"unknown", &intent.NormalizedCommand{
+		Intent: intent.IntentUnknown,
+	}, nil},
+}
+
+func TestGolden(t *testing.T) {
+	for _, tt := range goldenCommands {
+		policy := validators.DefaultPolicy()
+		if tt.policy != nil {
+			policy = *tt.policy
+		}
+		validators.ValidateCommandWithPolicy(tt.cmd, policy)
+
+		for _, lang := range Languages() {
+			t.Run(tt.name+"."+lang, func(t *testing.T) {
+				var out strings.Builder
+				out.WriteString("confirmation: " + Confirmation(tt.cmd, lang) + "\n")
+				out.WriteString("clarification: " + Clarification(tt.cmd, lang) + "\n")
+
+				checkGolden(t, filepath.Join("testdata", tt.name+"."+lang+".golden"), out.String())
+			})
+		}
+	}
+}
+
+func checkGolden(t *testing.T, path, got string) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("write golden: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden (run go test -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run go test -update to accept):\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestLookup_LocaleFallback(t *testing.T) {
+	tests := []struct {
+		lang string
+		want *messages
+	}{
+		{"es", catalog["es"]},
+		{"es_AR", catalog["es"]},
+		{"es-MX", catalog["es"]},
+		{"EN", catalog["en"]},
+		{"fr", catalog["en"]},
+		{"", catalog["en"]},
+	}
+
+	for _, tt := range tests {
+		if got := lookup(tt.lang); got != tt.want {
+			t.Errorf("lookup(%q) returned the wrong catalog", tt.lang)
+		}
+	}
+}
+
+func TestCatalogsCoverEveryIntent(t *testing.T) {
+	for lang, m := range catalog {
+		for i := range catalog[DefaultLanguage].intents {
+			if _, ok := m.intents[i]; !ok {
+				t.Errorf("%s catalog has no wording for %s", lang, i)
+			}
+		}
+	}
+}
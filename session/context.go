@@ -0,0 +1,105 @@
+package session
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// Decay controls how much an earlier command's context is trusted when a
+// follow-up leaves it out, e.g. "close half" after "long BTC". Confidence
+// halves every HalfLife since the earlier command and every TurnHalfLife
+// commands given since; a zero value disables that decay. Context whose
+// confidence falls below Min is not inherited.
+type Decay struct {
+	HalfLife     time.Duration
+	TurnHalfLife int
+	Min          float64
+}
+
+// DefaultDecay stops inheriting a symbol after about ten minutes or five
+// turns, so one mentioned half an hour ago prompts a question instead of
+// silently becoming the target of an order
+func DefaultDecay() Decay {
+	return Decay{HalfLife: 10 * time.Minute, TurnHalfLife: 5, Min: 0.5}
+}
+
+// Confidence returns confidence decayed for context of the given age, given
+// turns commands before the current one
+func (d Decay) Confidence(confidence float64, age time.Duration, turns int) float64 {
+	halvings := 0.0
+	if d.HalfLife > 0 && age > 0 {
+		halvings += float64(age) / float64(d.HalfLife)
+	}
+	if d.TurnHalfLife > 0 && turns > 0 {
+		halvings += float64(turns) / float64(d.TurnHalfLife)
+	}
+	return confidence * math.Pow(0.5, halvings)
+}
+
+// Inherited reports context found for a field missing from a command
+type Inherited struct {
+	Field      string  `json:"field"` // JSON name, e.g. "symbol"
+	Value      string  `json:"value"`
+	From       string  `json:"from,omitempty"` // ID of the command it came from
+	Confidence float64 `json:"confidence"`     // After decay
+
+	// Stale means Confidence is below Decay.Min, so the value was not
+	// applied; the caller can ask to confirm it ("still BTC?")
+	Stale bool `json:"stale,omitempty"`
+}
+
+// Inherit fills the symbol of cmd from the latest command in the history
+// that has one, when the validators reported it missing. The inherited
+// value is recorded in cmd.FieldConfidence with its decayed confidence,
+// which starts from the earlier command's confidence for that field. Stale
+// context is reported but left out, so the field stays missing and the
+// user is asked for it. Call Inherit before appending cmd to the history
+// and validate cmd again when a value was applied.
+func (s *Session) Inherit(cmd *intent.NormalizedCommand, now time.Time, decay Decay) []Inherited {
+	if cmd.Symbol != "" || !slices.Contains(cmd.Missing, "symbol") {
+		return nil
+	}
+
+	for i := len(s.History) - 1; i >= 0; i-- {
+		source := s.History[i]
+		if source.Symbol == "" {
+			continue
+		}
+
+		var age time.Duration
+		if !source.Timestamp.IsZero() {
+			age = now.Sub(source.Timestamp)
+		}
+		inherited := Inherited{
+			Field:      "symbol",
+			Value:      source.Symbol,
+			From:       source.ID,
+			Confidence: decay.Confidence(sourceConfidence(source, "symbol"), age, len(s.History)-i),
+		}
+		inherited.Stale = inherited.Confidence < decay.Min
+		if !inherited.Stale {
+			cmd.Symbol = source.Symbol
+			if cmd.FieldConfidence == nil {
+				cmd.FieldConfidence = make(map[string]float64)
+			}
+			cmd.FieldConfidence["symbol"] = inherited.Confidence
+		}
+		return []Inherited{inherited}
+	}
+	return nil
+}
+
+// sourceConfidence returns the confidence of field on cmd, falling back to
+// the intent confidence and to 1 for commands built without one
+func sourceConfidence(cmd *intent.NormalizedCommand, field string) float64 {
+	if c, ok := cmd.FieldConfidence[field]; ok {
+		return c
+	}
+	if cmd.Confidence > 0 {
+		return cmd.Confidence
+	}
+	return 1
+}
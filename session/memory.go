@@ -0,0 +1,119 @@
+package session
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// MemoryStore is an in-process Store bounded by the number of sessions and
+// the history length of each session. When full, the least recently used
+// session is evicted.
+type MemoryStore struct {
+	maxSessions int
+	maxHistory  int
+	ttl         time.Duration
+	metrics     Metrics
+	clock       intent.Clock
+
+	mu       sync.Mutex
+	sessions map[string]*list.Element
+	order    *list.List // Front is the most recently used session
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore(opts ...Option) *MemoryStore {
+	o := newOptions(opts)
+	return &MemoryStore{
+		maxSessions: o.maxSessions,
+		maxHistory:  o.maxHistory,
+		ttl:         o.ttl,
+		metrics:     o.metrics,
+		clock:       o.clock,
+		sessions:    make(map[string]*list.Element),
+		order:       list.New(),
+	}
+}
+
+// Get returns a copy of the session of userID
+func (m *MemoryStore) Get(_ context.Context, userID string) (*Session, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.sessions[userID]
+	if !ok {
+		return nil, false, nil
+	}
+
+	s := elem.Value.(*Session)
+	if m.expired(s) {
+		m.remove(elem)
+		m.metrics.SessionEvicted(EvictedExpired, 1)
+		return nil, false, nil
+	}
+
+	m.order.MoveToFront(elem)
+	return s.Clone(), true, nil
+}
+
+// Put stores a copy of s, trimming its history and evicting the least
+// recently used session when the store is full
+func (m *MemoryStore) Put(_ context.Context, s *Session) error {
+	s = s.Clone()
+	s.UpdatedAt = m.clock.Now()
+	if dropped := trimHistory(s, m.maxHistory); dropped > 0 {
+		m.metrics.SessionEvicted(EvictedHistory, dropped)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.sessions[s.UserID]; ok {
+		elem.Value = s
+		m.order.MoveToFront(elem)
+		return nil
+	}
+
+	m.sessions[s.UserID] = m.order.PushFront(s)
+
+	if m.maxSessions > 0 && m.order.Len() > m.maxSessions {
+		oldest := m.order.Back()
+		reason := EvictedCapacity
+		if m.expired(oldest.Value.(*Session)) {
+			reason = EvictedExpired
+		}
+		m.remove(oldest)
+		m.metrics.SessionEvicted(reason, 1)
+	}
+	return nil
+}
+
+// Delete removes the session of userID
+func (m *MemoryStore) Delete(_ context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.sessions[userID]; ok {
+		m.remove(elem)
+	}
+	return nil
+}
+
+// Len returns the number of sessions held, including expired ones not yet evicted
+func (m *MemoryStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+func (m *MemoryStore) expired(s *Session) bool {
+	return m.ttl > 0 && m.clock.Now().Sub(s.UpdatedAt) > m.ttl
+}
+
+func (m *MemoryStore) remove(elem *list.Element) {
+	m.order.Remove(elem)
+	delete(m.sessions, elem.Value.(*Session).UserID)
+}
@@ -0,0 +1,80 @@
+package session
+
+import (
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// Option configures a MemoryStore or RedisStore
+type Option func(*options)
+
+type options struct {
+	maxSessions int
+	maxHistory  int
+	ttl         time.Duration
+	metrics     Metrics
+	clock       intent.Clock
+	keyPrefix   string
+}
+
+// WithMaxSessions bounds the number of sessions kept in memory (default
+// 10000, <= 0 disables the bound). Ignored by RedisStore, which relies on
+// the server's maxmemory policy.
+func WithMaxSessions(n int) Option {
+	return func(o *options) {
+		o.maxSessions = n
+	}
+}
+
+// WithMaxHistory bounds the history length of each session (default 20,
+// <= 0 disables the bound)
+func WithMaxHistory(n int) Option {
+	return func(o *options) {
+		o.maxHistory = n
+	}
+}
+
+// WithTTL expires sessions that weren't updated for ttl (default 30 minutes,
+// <= 0 keeps sessions until evicted)
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithMetrics reports evictions to m
+func WithMetrics(m Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+// WithClock sets the clock used for UpdatedAt and expiry (default intent.SystemClock)
+func WithClock(clock intent.Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithKeyPrefix sets the Redis key prefix (default "intent:session:")
+func WithKeyPrefix(prefix string) Option {
+	return func(o *options) {
+		o.keyPrefix = prefix
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		maxSessions: 10000,
+		maxHistory:  20,
+		ttl:         30 * time.Minute,
+		metrics:     NopMetrics{},
+		clock:       intent.SystemClock{},
+		keyPrefix:   "intent:session:",
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
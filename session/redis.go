@@ -0,0 +1,87 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// RedisClient is the subset of a Redis client used by RedisStore. Adapt
+// go-redis, rueidis or similar with a few lines; Get reports a missing key
+// with found == false rather than an error.
+type RedisClient interface {
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Del(ctx context.Context, key string) error
+}
+
+// RedisStore is a Store shared by several bot instances. Sessions are stored
+// as JSON with the store TTL; history is trimmed before writing. Bounding the
+// number of sessions is left to the Redis maxmemory policy.
+type RedisStore struct {
+	client     RedisClient
+	keyPrefix  string
+	maxHistory int
+	ttl        time.Duration
+	metrics    Metrics
+	clock      intent.Clock
+}
+
+// NewRedisStore creates a RedisStore using client
+func NewRedisStore(client RedisClient, opts ...Option) *RedisStore {
+	o := newOptions(opts)
+	return &RedisStore{
+		client:     client,
+		keyPrefix:  o.keyPrefix,
+		maxHistory: o.maxHistory,
+		ttl:        o.ttl,
+		metrics:    o.metrics,
+		clock:      o.clock,
+	}
+}
+
+// Get loads the session of userID
+func (r *RedisStore) Get(ctx context.Context, userID string) (*Session, bool, error) {
+	data, found, err := r.client.Get(ctx, r.keyPrefix+userID)
+	if err != nil {
+		return nil, false, fmt.Errorf("loading session: %w", err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, false, fmt.Errorf("decoding session: %w", err)
+	}
+	return &s, true, nil
+}
+
+// Put saves s with the store TTL
+func (r *RedisStore) Put(ctx context.Context, s *Session) error {
+	s = s.Clone()
+	s.UpdatedAt = r.clock.Now()
+	if dropped := trimHistory(s, r.maxHistory); dropped > 0 {
+		r.metrics.SessionEvicted(EvictedHistory, dropped)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encoding session: %w", err)
+	}
+	if err := r.client.Set(ctx, r.keyPrefix+s.UserID, data, max(r.ttl, 0)); err != nil {
+		return fmt.Errorf("saving session: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the session of userID
+func (r *RedisStore) Delete(ctx context.Context, userID string) error {
+	if err := r.client.Del(ctx, r.keyPrefix+userID); err != nil {
+		return fmt.Errorf("deleting session: %w", err)
+	}
+	return nil
+}
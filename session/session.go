@@ -0,0 +1,96 @@
+// Package session keeps short-lived per-user conversation state, such as the
+// last incomplete command awaiting a follow-up ("at what price?"), in a
+// memory-bounded store
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// Session is the conversation state of one user
+type Session struct {
+	UserID string `json:"user_id"`
+
+	// Pending is the last command that could not be executed yet, e.g.
+	// because fields were missing
+	Pending *intent.NormalizedCommand `json:"pending,omitempty"`
+
+	// History holds the most recent commands, oldest first
+	History []*intent.NormalizedCommand `json:"history,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Append adds cmd to the history
+func (s *Session) Append(cmd *intent.NormalizedCommand) {
+	s.History = append(s.History, cmd)
+}
+
+// Clone returns a deep copy of the session
+func (s *Session) Clone() *Session {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	clone.Pending = s.Pending.Clone()
+	if s.History != nil {
+		clone.History = make([]*intent.NormalizedCommand, len(s.History))
+		for i, cmd := range s.History {
+			clone.History[i] = cmd.Clone()
+		}
+	}
+	return &clone
+}
+
+// Store persists sessions by user ID. Implementations must be safe for
+// concurrent use and must not alias sessions passed to Put or returned by Get.
+type Store interface {
+	// Get returns the session of userID, or false when there is none
+	Get(ctx context.Context, userID string) (*Session, bool, error)
+
+	// Put stores s, replacing any previous session of s.UserID
+	Put(ctx context.Context, s *Session) error
+
+	// Delete removes the session of userID
+	Delete(ctx context.Context, userID string) error
+}
+
+// EvictionReason says why a store dropped session data
+type EvictionReason string
+
+const (
+	// EvictedCapacity means the least recently used session was dropped to
+	// stay within the maximum number of sessions
+	EvictedCapacity EvictionReason = "capacity"
+
+	// EvictedExpired means the session outlived its TTL
+	EvictedExpired EvictionReason = "expired"
+
+	// EvictedHistory means old history entries were trimmed to keep the
+	// session within its size bound
+	EvictedHistory EvictionReason = "history"
+)
+
+// Metrics receives store eviction events. Implementations must be safe for
+// concurrent use.
+type Metrics interface {
+	SessionEvicted(reason EvictionReason, count int)
+}
+
+// NopMetrics is a Metrics that discards all events
+type NopMetrics struct{}
+
+func (NopMetrics) SessionEvicted(EvictionReason, int) {}
+
+// trimHistory drops the oldest entries beyond max and returns how many were dropped
+func trimHistory(s *Session, max int) int {
+	if max <= 0 || len(s.History) <= max {
+		return 0
+	}
+	dropped := len(s.History) - max
+	s.History = append([]*intent.NormalizedCommand(nil), s.History[dropped:]...)
+	return dropped
+}
@@ -0,0 +1,239 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+type recordingMetrics struct {
+	mu      sync.Mutex
+	evicted map[EvictionReason]int
+}
+
+func (r *recordingMetrics) SessionEvicted(reason EvictionReason, count int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.evicted == nil {
+		r.evicted = make(map[EvictionReason]int)
+	}
+	r.evicted[reason] += count
+}
+
+type manualClock struct{ now time.Time }
+
+func (c *manualClock) Now() time.Time { return c.now }
+
+func history(n int) []*intent.NormalizedCommand {
+	var cmds []*intent.NormalizedCommand
+	for i := range n {
+		cmds = append(cmds, &intent.NormalizedCommand{ID: string(rune('a' + i))})
+	}
+	return cmds
+}
+
+func TestMemoryStore_LRUEviction(t *testing.T) {
+	ctx := context.Background()
+	metrics := &recordingMetrics{}
+	store := NewMemoryStore(WithMaxSessions(2), WithMetrics(metrics))
+
+	store.Put(ctx, &Session{UserID: "alice"})
+	store.Put(ctx, &Session{UserID: "bob"})
+	store.Get(ctx, "alice") // bob becomes least recently used
+	store.Put(ctx, &Session{UserID: "carol"})
+
+	if _, ok, _ := store.Get(ctx, "bob"); ok {
+		t.Error("bob was not evicted")
+	}
+	for _, user := range []string{"alice", "carol"} {
+		if _, ok, _ := store.Get(ctx, user); !ok {
+			t.Errorf("%s was evicted", user)
+		}
+	}
+	if store.Len() != 2 {
+		t.Errorf("Len = %d, want 2", store.Len())
+	}
+	if metrics.evicted[EvictedCapacity] != 1 {
+		t.Errorf("capacity evictions = %d, want 1", metrics.evicted[EvictedCapacity])
+	}
+}
+
+func TestMemoryStore_HistoryBound(t *testing.T) {
+	ctx := context.Background()
+	metrics := &recordingMetrics{}
+	store := NewMemoryStore(WithMaxHistory(3), WithMetrics(metrics))
+
+	store.Put(ctx, &Session{UserID: "alice", History: history(5)})
+
+	s, _, _ := store.Get(ctx, "alice")
+	if len(s.History) != 3 || s.History[0].ID != "c" {
+		t.Errorf("History = %d entries starting at %q, want 3 starting at c", len(s.History), s.History[0].ID)
+	}
+	if metrics.evicted[EvictedHistory] != 2 {
+		t.Errorf("history evictions = %d, want 2", metrics.evicted[EvictedHistory])
+	}
+}
+
+func TestMemoryStore_TTL(t *testing.T) {
+	ctx := context.Background()
+	clock := &manualClock{now: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)}
+	metrics := &recordingMetrics{}
+	store := NewMemoryStore(WithTTL(time.Minute), WithClock(clock), WithMetrics(metrics))
+
+	store.Put(ctx, &Session{UserID: "alice"})
+	clock.now = clock.now.Add(2 * time.Minute)
+
+	if _, ok, _ := store.Get(ctx, "alice"); ok {
+		t.Error("expired session returned")
+	}
+	if metrics.evicted[EvictedExpired] != 1 {
+		t.Errorf("expired evictions = %d, want 1", metrics.evicted[EvictedExpired])
+	}
+}
+
+func TestMemoryStore_NoAliasing(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	s := &Session{UserID: "alice", Pending: &intent.NormalizedCommand{Symbol: "BTC-USDT"}}
+	store.Put(ctx, s)
+	s.Pending.Symbol = "ETH-USDT"
+
+	got, _, _ := store.Get(ctx, "alice")
+	if got.Pending.Symbol != "BTC-USDT" {
+		t.Errorf("stored session mutated through Put argument: %q", got.Pending.Symbol)
+	}
+	got.Pending.Symbol = "SOL-USDT"
+
+	again, _, _ := store.Get(ctx, "alice")
+	if again.Pending.Symbol != "BTC-USDT" {
+		t.Errorf("stored session mutated through Get result: %q", again.Pending.Symbol)
+	}
+
+	store.Delete(ctx, "alice")
+	if _, ok, _ := store.Get(ctx, "alice"); ok {
+		t.Error("session still present after Delete")
+	}
+}
+
+// fakeRedis is an in-memory RedisClient
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	ttls map[string]time.Duration
+}
+
+func (f *fakeRedis) Get(_ context.Context, key string) ([]byte, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.data[key]
+	return v, ok, nil
+}
+
+func (f *fakeRedis) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = value
+	f.ttls[key] = ttl
+	return nil
+}
+
+func (f *fakeRedis) Del(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.data, key)
+	return nil
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	redis := &fakeRedis{data: map[string][]byte{}, ttls: map[string]time.Duration{}}
+	store := NewRedisStore(redis, WithKeyPrefix("bot:"), WithMaxHistory(2), WithTTL(time.Hour))
+
+	err := store.Put(ctx, &Session{
+		UserID:  "alice",
+		Pending: &intent.NormalizedCommand{Intent: intent.IntentOpenPosition, Symbol: "BTC-USDT"},
+		History: history(3),
+	})
+	if err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+	if redis.ttls["bot:alice"] != time.Hour {
+		t.Errorf("ttl = %v, want 1h", redis.ttls["bot:alice"])
+	}
+
+	s, ok, err := store.Get(ctx, "alice")
+	if err != nil || !ok {
+		t.Fatalf("Get = (%v, %v, %v)", s, ok, err)
+	}
+	if s.Pending.Symbol != "BTC-USDT" || len(s.History) != 2 {
+		t.Errorf("session = %+v", s)
+	}
+
+	store.Delete(ctx, "alice")
+	if _, ok, _ := store.Get(ctx, "alice"); ok {
+		t.Error("session still present after Delete")
+	}
+}
+
+var (
+	_ Store = (*MemoryStore)(nil)
+	_ Store = (*RedisStore)(nil)
+)
+
+func TestSession_Inherit(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	s := &Session{History: []*intent.NormalizedCommand{
+		{ID: "a", Intent: intent.IntentOpenPosition, Symbol: "BTC-USDT", Confidence: 0.95, Timestamp: start},
+		{ID: "b", Intent: intent.IntentViewPositions, Timestamp: start.Add(time.Minute)},
+	}}
+	followUp := func() *intent.NormalizedCommand {
+		return &intent.NormalizedCommand{Intent: intent.IntentClosePosition, Missing: []string{"symbol"}}
+	}
+
+	cmd := followUp()
+	got := s.Inherit(cmd, start.Add(2*time.Minute), DefaultDecay())
+	if len(got) != 1 || got[0].Stale || got[0].From != "a" {
+		t.Fatalf("Inherit = %+v, want fresh context from a", got)
+	}
+	if cmd.Symbol != "BTC-USDT" || cmd.FieldConfidence["symbol"] != got[0].Confidence {
+		t.Errorf("Symbol = %q, FieldConfidence = %v, want BTC-USDT with the decayed confidence", cmd.Symbol, cmd.FieldConfidence)
+	}
+	if got[0].Confidence >= 0.95 {
+		t.Errorf("Confidence = %v, want it decayed below the source's 0.95", got[0].Confidence)
+	}
+
+	// Half an hour later the symbol is reported but not applied
+	cmd = followUp()
+	got = s.Inherit(cmd, start.Add(30*time.Minute), DefaultDecay())
+	if len(got) != 1 || !got[0].Stale || got[0].Value != "BTC-USDT" {
+		t.Fatalf("Inherit = %+v, want stale BTC-USDT", got)
+	}
+	if cmd.Symbol != "" || cmd.FieldConfidence != nil {
+		t.Errorf("Symbol = %q, FieldConfidence = %v, want nothing inherited", cmd.Symbol, cmd.FieldConfidence)
+	}
+
+	// Turns decay context as well as time
+	many := &Session{History: append([]*intent.NormalizedCommand{{ID: "a", Symbol: "ETH-USDT"}}, history(9)...)}
+	if got := many.Inherit(followUp(), time.Time{}, Decay{TurnHalfLife: 5, Min: 0.5}); len(got) != 1 || !got[0].Stale {
+		t.Errorf("Inherit = %+v, want stale context ten turns back", got)
+	}
+
+	// Only a missing symbol is inherited
+	if got := s.Inherit(&intent.NormalizedCommand{Intent: intent.IntentViewPositions}, start, DefaultDecay()); got != nil {
+		t.Errorf("Inherit = %+v for a command not missing its symbol", got)
+	}
+}
+
+func TestDecay_Confidence(t *testing.T) {
+	d := Decay{HalfLife: 10 * time.Minute, TurnHalfLife: 2}
+	if got := d.Confidence(0.8, 10*time.Minute, 2); got != 0.2 {
+		t.Errorf("Confidence = %v, want 0.2 after two halvings", got)
+	}
+	if got := (Decay{}).Confidence(0.8, time.Hour, 10); got != 0.8 {
+		t.Errorf("zero Decay: Confidence = %v, want 0.8", got)
+	}
+}
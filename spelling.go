@@ -0,0 +1,82 @@
+package intent
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// phoneticAlphabet maps the NATO spelling alphabet to letters
+var phoneticAlphabet = map[string]rune{
+	"alpha": 'A', "alfa": 'A', "bravo": 'B', "charlie": 'C', "delta": 'D', "echo": 'E',
+	"foxtrot": 'F', "golf": 'G', "hotel": 'H', "india": 'I', "juliet": 'J', "juliett": 'J',
+	"kilo": 'K', "lima": 'L', "mike": 'M', "november": 'N', "oscar": 'O', "papa": 'P',
+	"quebec": 'Q', "romeo": 'R', "sierra": 'S', "tango": 'T', "uniform": 'U', "victor": 'V',
+	"whiskey": 'W', "xray": 'X', "x-ray": 'X', "yankee": 'Y', "zulu": 'Z',
+}
+
+// JoinSpelledLetters joins tickers spelled out in a voice transcript into
+// one word, so "open long b t c" becomes "open long BTC". Runs of two or
+// more single letters ("x r p", "B. T. C.") or NATO alphabet words ("bravo
+// tango charlie") are joined, as are dotted acronyms ("b.t.c."). Other
+// words are kept; runs of whitespace collapse to a single space.
+func JoinSpelledLetters(input string) string {
+	tokens := strings.Fields(input)
+	out := make([]string, 0, len(tokens))
+	for i := 0; i < len(tokens); {
+		var letters []rune
+		trailing := ""
+		j := i
+		for ; j < len(tokens); j++ {
+			word := strings.TrimRight(tokens[j], ",;:?!")
+			letter, ok := spelledLetter(word)
+			if !ok {
+				break
+			}
+			letters = append(letters, letter)
+			if word != tokens[j] {
+				// Punctuation ends the run: "b t c, then e t h"
+				trailing = tokens[j][len(word):]
+				j++
+				break
+			}
+		}
+		if len(letters) >= 2 {
+			out = append(out, string(letters)+trailing)
+			i = j
+			continue
+		}
+		out = append(out, joinDotted(tokens[i]))
+		i++
+	}
+	return strings.Join(out, " ")
+}
+
+// spelledLetter returns the letter word spells: a single letter, optionally
+// followed by a period ("b", "B."), or a NATO alphabet word ("bravo")
+func spelledLetter(word string) (rune, bool) {
+	trimmed := strings.TrimSuffix(word, ".")
+	if r, size := utf8.DecodeRuneInString(trimmed); size == len(trimmed) && r < unicode.MaxASCII && unicode.IsLetter(r) {
+		return unicode.ToUpper(r), true
+	}
+	letter, ok := phoneticAlphabet[strings.ToLower(word)]
+	return letter, ok
+}
+
+// joinDotted returns "BTC" for a dotted acronym like "b.t.c." and word
+// unchanged otherwise
+func joinDotted(word string) string {
+	parts := strings.Split(strings.TrimSuffix(word, "."), ".")
+	if len(parts) < 2 {
+		return word
+	}
+	letters := make([]rune, len(parts))
+	for i, part := range parts {
+		letter, ok := spelledLetter(part)
+		if !ok || len(part) != 1 {
+			return word
+		}
+		letters[i] = letter
+	}
+	return string(letters)
+}
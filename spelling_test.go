@@ -0,0 +1,28 @@
+package intent
+
+import "testing"
+
+func TestJoinSpelledLetters(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"open long b t c at 45000", "open long BTC at 45000"},
+		{"x r p", "XRP"},
+		{"price of B. T. C.?", "price of BTC?"},
+		{"close b.t.c. now", "close BTC now"},
+		{"bravo tango charlie", "BTC"},
+		{"sierra oscar lima long", "SOL long"},
+		{"a d a", "ADA"},
+		{"close b t c, then e t h", "close BTC, then ETH"},
+		{"open a long on ETH", "open a long on ETH"},
+		{"45 k", "45 k"},
+		{"show my positions", "show my positions"},
+	}
+
+	for _, tt := range tests {
+		if got := JoinSpelledLetters(tt.input); got != tt.want {
+			t.Errorf("JoinSpelledLetters(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
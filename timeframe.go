@@ -0,0 +1,17 @@
+package intent
+
+import "slices"
+
+// timeframes are the chart intervals a command's Timeframe may hold, in
+// the canonical form produced by the backends
+var timeframes = []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "12h", "1d", "1w"}
+
+// Timeframes returns the supported chart intervals, shortest first
+func Timeframes() []string {
+	return slices.Clone(timeframes)
+}
+
+// KnownTimeframe reports whether tf is a supported chart interval, e.g. "4h"
+func KnownTimeframe(tf string) bool {
+	return slices.Contains(timeframes, tf)
+}
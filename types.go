@@ -1,15 +1,17 @@
 package intent
 
 import (
+	"maps"
+	"time"
+
 	"github.com/agatticelli/trading-common-types"
 )
 
 // Re-export common types for backward compatibility
 type (
-	Intent            = types.Intent
-	Side              = types.Side
-	NormalizedCommand = types.NormalizedCommand
-	TPLevel           = types.TPLevel
+	Intent  = types.Intent
+	Side    = types.Side
+	TPLevel = types.TPLevel
 )
 
 // Re-export constants
@@ -27,3 +29,362 @@ const (
 	SideLong  = types.SideLong
 	SideShort = types.SideShort
 )
+
+// Intents specific to intent-go (not part of trading-common-types)
+const (
+	IntentCopyTrade   Intent = "copy_trade"
+	IntentSetupGrid   Intent = "setup_grid"
+	IntentViewAlerts  Intent = "view_alerts"
+	IntentCancelAlert Intent = "cancel_alert"
+
+	IntentSetRiskDefaults Intent = "set_risk_defaults"
+	IntentWithdraw        Intent = "withdraw"
+	IntentModifyPosition  Intent = "modify_position"
+	IntentSetLeverage     Intent = "set_leverage"
+	IntentDCAOrder        Intent = "dca_order"
+	IntentSetAlert        Intent = "set_alert"
+	IntentViewPnL         Intent = "view_pnl"
+	IntentViewPrice       Intent = "view_price"
+	IntentViewFunding     Intent = "view_funding"
+	IntentHedgePosition   Intent = "hedge_position"
+	IntentMoveStopLoss    Intent = "move_stop_loss"
+	IntentViewHistory     Intent = "view_history"
+
+	IntentCheckOrderStatus Intent = "check_order_status"
+	IntentViewPosition     Intent = "view_position"
+	IntentPanicClose       Intent = "panic_close"
+	IntentSetTakeProfit    Intent = "set_take_profit"
+	IntentSetStopLoss      Intent = "set_stop_loss"
+	IntentHelp             Intent = "help"
+	IntentCloseAll         Intent = "close_all"
+)
+
+// IsReadOnly reports whether i only queries account or market state, so an
+// older result for it can be shown without placing or changing anything
+func IsReadOnly(i Intent) bool {
+	switch i {
+	case IntentViewPositions, IntentViewOrders, IntentCheckBalance, IntentViewAlerts, IntentViewPnL,
+		IntentViewPrice, IntentViewFunding, IntentViewHistory, IntentCheckOrderStatus,
+		IntentViewPosition, IntentHelp:
+		return true
+	}
+	spec, ok := LookupIntent(i)
+	return ok && spec.ReadOnly
+}
+
+// IntentCandidate is a ranked intent the backend considered
+type IntentCandidate struct {
+	Intent     Intent  `json:"intent"`
+	Confidence float64 `json:"confidence"`
+}
+
+// EntryLevel is one rung of a scale-in (DCA) ladder, mirroring TPLevel
+type EntryLevel struct {
+	Price      float64 `json:"price"`
+	Percentage float64 `json:"percentage"` // 0-100 of the total size
+}
+
+// SLLevel is one stop of a multi-level stop loss, mirroring TPLevel
+type SLLevel struct {
+	Price      float64 `json:"price"`
+	Percentage float64 `json:"percentage"` // 0-100 of the position closed at this stop
+}
+
+// NormalizedCommand is the central data structure that flows through the system.
+// It carries every field of types.NormalizedCommand plus the fields intent-go
+// extracts on top of the shared schema.
+type NormalizedCommand struct {
+	// Intent classification
+	Intent     Intent  `json:"intent"`
+	Confidence float64 `json:"confidence"`
+
+	// Lower-ranked intents, highest confidence first, so UIs can offer
+	// "did you mean ...?" when the top intent is marginal
+	Alternatives []IntentCandidate `json:"alternatives,omitempty"`
+
+	// EntityMeta maps each entity the backend extracted, named as the
+	// backend reports it (e.g. "symbol", or "price:stop_loss" for a role),
+	// to the span of input it came from
+	EntityMeta map[string]EntitySpan `json:"entity_meta,omitempty"`
+
+	// FieldConfidence maps each extracted field, named by its JSON key
+	// (e.g. "stop_loss"), to the confidence of the entity it came from, so
+	// callers can require critical fields to be certain regardless of the
+	// intent confidence (see LowConfidenceFields)
+	FieldConfidence map[string]float64 `json:"field_confidence,omitempty"`
+
+	// Traits maps the backend traits configured for mapping to their
+	// value, e.g. {"urgency": "high"} for "close BTC NOW", so routing can
+	// prioritize the command
+	Traits map[string]string `json:"traits,omitempty"`
+
+	// Further commands of a compound utterance such as "open long BTC at
+	// 45000 and set an alert at 47000". The command itself is the first
+	// one; each sub-command is parsed and validated on its own.
+	SubCommands []*NormalizedCommand `json:"sub_commands,omitempty"`
+
+	// DependsOn lists the IDs of commands of the same utterance that must
+	// run first, e.g. the price question a conditional order was phrased
+	// against (see LinkDependencies)
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	// Extracted parameters
+	Symbol string `json:"symbol,omitempty"`
+	Side   *Side  `json:"side,omitempty"`
+
+	// Positions close_all leaves open, e.g. "close everything except ETH"
+	ExcludeSymbols []string `json:"exclude_symbols,omitempty"`
+
+	// Venue for users trading on several exchanges, e.g. "binance"; empty
+	// means the caller's default
+	Exchange string `json:"exchange,omitempty"`
+
+	// Account or subaccount name, e.g. "scalping"; empty means the
+	// caller's default
+	Account string `json:"account,omitempty"`
+
+	// Order execution, empty leaves the choice to the executor
+	OrderType   OrderType   `json:"order_type,omitempty"`
+	TimeInForce TimeInForce `json:"time_in_force,omitempty"`
+
+	// Good-till-date expiry of the order, nil keeps it until filled or
+	// cancelled
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// ReduceOnly restricts the order to shrinking a position, nil leaves
+	// the exchange default
+	ReduceOnly *bool `json:"reduce_only,omitempty"`
+
+	// Margin mode, empty keeps the account's current mode
+	MarginMode MarginMode `json:"margin_mode,omitempty"`
+
+	// Price parameters
+	EntryPrice   *float64 `json:"entry_price,omitempty"`
+	StopLoss     *float64 `json:"stop_loss,omitempty"`
+	TakeProfit   *float64 `json:"take_profit,omitempty"`
+	TriggerPrice *float64 `json:"trigger_price,omitempty"`
+
+	// Signed price offset applied to the current stop loss by
+	// move_stop_loss, e.g. 200 moves it up by 200
+	StopLossOffset *float64 `json:"stop_loss_offset,omitempty"`
+
+	// Multi-level take profits
+	TPLevels []TPLevel `json:"tp_levels,omitempty"`
+
+	// Multi-level stop loss, an alternative to StopLoss for scaling out
+	SLLevels []SLLevel `json:"sl_levels,omitempty"`
+
+	// Scale-in ladder for DCA orders
+	EntryLevels []EntryLevel `json:"entry_levels,omitempty"`
+
+	// Risk parameters
+	RiskPercent *float64 `json:"risk_percent,omitempty"` // 0-100
+	RRRatio     *float64 `json:"rr_ratio,omitempty"`     // e.g., 2.0 for 2:1
+	Leverage    *float64 `json:"leverage,omitempty"`     // e.g., 10 for 10x
+
+	// Absolute size in base-asset units, an alternative to RiskPercent
+	Quantity *float64 `json:"quantity,omitempty"`
+
+	// Size as a dollar amount, e.g. 500 in "buy $500 of BTC", another
+	// alternative to RiskPercent
+	NotionalUSD *float64 `json:"notional_usd,omitempty"`
+
+	// Partial close, nil closes the full position
+	ClosePercent *float64 `json:"close_percent,omitempty"` // (0-100]
+
+	// Hedge ratio, nil hedges the full position
+	HedgeRatio *float64 `json:"hedge_ratio,omitempty"` // (0-1], e.g. 0.5 for half
+
+	// Trailing parameters
+	CallbackRate *float64 `json:"callback_rate,omitempty"`
+	Distance     *float64 `json:"distance,omitempty"`
+
+	// Copy trading parameters
+	SourceAccount string   `json:"source_account,omitempty"`
+	TargetAccount string   `json:"target_account,omitempty"`
+	SizeFactor    *float64 `json:"size_factor,omitempty"` // e.g., 0.5 for half size
+
+	// Grid strategy parameters
+	GridLower     *float64 `json:"grid_lower,omitempty"`
+	GridUpper     *float64 `json:"grid_upper,omitempty"`
+	GridLevels    *int     `json:"grid_levels,omitempty"`
+	GridLevelSize *float64 `json:"grid_level_size,omitempty"` // Order size per grid level
+
+	// Order selectors for cancel_orders: one order, or all of them
+	// (optionally filtered by Symbol). check_order_status selects one
+	// order by ID, the latest one, or the latest one on Symbol.
+	OrderID     string `json:"order_id,omitempty"`
+	CancelAll   bool   `json:"cancel_all,omitempty"`
+	LatestOrder bool   `json:"latest_order,omitempty"`
+
+	// Alert parameters. An empty AlertDirection fires when the price
+	// crosses AlertPrice either way.
+	AlertID        string            `json:"alert_id,omitempty"`
+	AlertPrice     *float64          `json:"alert_price,omitempty"`
+	AlertDirection ConditionOperator `json:"alert_direction,omitempty"` // above or below
+
+	// Withdrawal parameters. AddressRef names a whitelisted address;
+	// raw addresses are never taken from natural language.
+	Asset      string   `json:"asset,omitempty"`
+	Amount     *float64 `json:"amount,omitempty"`
+	AddressRef string   `json:"address_ref,omitempty"`
+
+	// Reporting window for queries like view_pnl, empty means the
+	// consumer's default
+	Period Period `json:"period,omitempty"`
+
+	// Chart interval referenced by the input, e.g. "4h" in "BTC price on
+	// the 4 hour"; see Timeframes
+	Timeframe string `json:"timeframe,omitempty"`
+
+	// Time range for queries like view_history; To is exclusive
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+
+	// Condition that must hold before the command executes
+	Condition *PriceCondition `json:"condition,omitempty"`
+
+	// Validation
+	Valid   bool     `json:"valid"`
+	Missing []string `json:"missing,omitempty"` // Missing required parameters
+	Errors  []string `json:"errors,omitempty"`  // Validation errors
+
+	// Warnings are unusual but acceptable values, e.g. a take profit far
+	// from the entry; they don't affect Valid (see SeverityWarning)
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Issues holds Missing and Errors in structured form, with a code
+	// consumers can branch on (see AddIssue)
+	Issues []ValidationIssue `json:"issues,omitempty"`
+
+	// ConfirmationRequired asks the caller to confirm with the user before executing
+	ConfirmationRequired bool `json:"confirmation_required,omitempty"`
+
+	// Stale marks a cached result served because the backend failed
+	// (see WithStaleOnError); it may not reflect the latest input handling
+	Stale bool `json:"stale,omitempty"`
+
+	// Note holds input text left out of parsing, e.g. the commentary
+	// dropped by ExtractActionable from a long message
+	Note string `json:"note,omitempty"`
+
+	// Metadata
+	ID        string    `json:"id,omitempty"`
+	UserID    string    `json:"user_id,omitempty"` // From intent.WithUser
+	RawInput  string    `json:"raw_input"`
+	Language  string    `json:"language,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Meta records the processor and component versions that produced
+	// the command
+	Meta *ParseMeta `json:"meta,omitempty"`
+
+	// SchemaVersion is the layout version the command was produced with,
+	// CurrentSchemaVersion for new commands and 0 for ones serialized
+	// before versioning. Stored commands are upgraded by package migrate.
+	SchemaVersion int `json:"schema_version,omitempty"`
+}
+
+// CurrentSchemaVersion is the NormalizedCommand layout version. Bump it
+// with a migration in package migrate whenever a field is renamed, split
+// or changes meaning, so persisted commands can still be read.
+const CurrentSchemaVersion = 1
+
+// Common converts the command to the shared trading-common-types representation.
+// Fields that only exist in intent-go are dropped.
+func (c *NormalizedCommand) Common() *types.NormalizedCommand {
+	return &types.NormalizedCommand{
+		Intent:       c.Intent,
+		Confidence:   c.Confidence,
+		Symbol:       c.Symbol,
+		Side:         c.Side,
+		EntryPrice:   c.EntryPrice,
+		StopLoss:     c.StopLoss,
+		TakeProfit:   c.TakeProfit,
+		TriggerPrice: c.TriggerPrice,
+		TPLevels:     c.TPLevels,
+		RiskPercent:  c.RiskPercent,
+		RRRatio:      c.RRRatio,
+		CallbackRate: c.CallbackRate,
+		Distance:     c.Distance,
+		Valid:        c.Valid,
+		Missing:      c.Missing,
+		Errors:       c.Errors,
+		RawInput:     c.RawInput,
+		Language:     c.Language,
+		Timestamp:    c.Timestamp,
+	}
+}
+
+// Clone returns a deep copy of the command, so callers can mutate the copy
+// without affecting the original
+func (c *NormalizedCommand) Clone() *NormalizedCommand {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+	clone.Side = clonePtr(c.Side)
+	clone.ExpiresAt = clonePtr(c.ExpiresAt)
+	clone.Meta = c.Meta.Clone()
+	clone.ReduceOnly = clonePtr(c.ReduceOnly)
+	clone.EntryPrice = clonePtr(c.EntryPrice)
+	clone.StopLoss = clonePtr(c.StopLoss)
+	clone.StopLossOffset = clonePtr(c.StopLossOffset)
+	clone.TakeProfit = clonePtr(c.TakeProfit)
+	clone.TriggerPrice = clonePtr(c.TriggerPrice)
+	clone.RiskPercent = clonePtr(c.RiskPercent)
+	clone.RRRatio = clonePtr(c.RRRatio)
+	clone.Leverage = clonePtr(c.Leverage)
+	clone.Quantity = clonePtr(c.Quantity)
+	clone.NotionalUSD = clonePtr(c.NotionalUSD)
+	clone.ClosePercent = clonePtr(c.ClosePercent)
+	clone.HedgeRatio = clonePtr(c.HedgeRatio)
+	clone.CallbackRate = clonePtr(c.CallbackRate)
+	clone.Distance = clonePtr(c.Distance)
+	clone.SizeFactor = clonePtr(c.SizeFactor)
+	clone.GridLower = clonePtr(c.GridLower)
+	clone.GridUpper = clonePtr(c.GridUpper)
+	clone.GridLevels = clonePtr(c.GridLevels)
+	clone.GridLevelSize = clonePtr(c.GridLevelSize)
+	clone.AlertPrice = clonePtr(c.AlertPrice)
+	clone.Amount = clonePtr(c.Amount)
+	clone.From = clonePtr(c.From)
+	clone.To = clonePtr(c.To)
+	clone.Condition = c.Condition.Clone()
+	clone.ExcludeSymbols = cloneSlice(c.ExcludeSymbols)
+	clone.Alternatives = cloneSlice(c.Alternatives)
+	clone.EntityMeta = maps.Clone(c.EntityMeta)
+	clone.FieldConfidence = maps.Clone(c.FieldConfidence)
+	clone.Traits = maps.Clone(c.Traits)
+	if c.SubCommands != nil {
+		clone.SubCommands = make([]*NormalizedCommand, len(c.SubCommands))
+		for i, sub := range c.SubCommands {
+			clone.SubCommands[i] = sub.Clone()
+		}
+	}
+	clone.DependsOn = cloneSlice(c.DependsOn)
+	clone.TPLevels = cloneSlice(c.TPLevels)
+	clone.SLLevels = cloneSlice(c.SLLevels)
+	clone.EntryLevels = cloneSlice(c.EntryLevels)
+	clone.Missing = cloneSlice(c.Missing)
+	clone.Errors = cloneSlice(c.Errors)
+	clone.Warnings = cloneSlice(c.Warnings)
+	clone.Issues = cloneSlice(c.Issues)
+	return &clone
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append([]T(nil), s...)
+}
@@ -0,0 +1,67 @@
+package intent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizedCommand_CloneIsDeep(t *testing.T) {
+	side := SideLong
+	price := 45000.0
+	original := &NormalizedCommand{
+		Intent:     IntentOpenPosition,
+		Symbol:     "BTC-USDT",
+		Side:       &side,
+		EntryPrice: &price,
+		TPLevels:   []TPLevel{{Price: 46000, Percentage: 100}},
+		Missing:    []string{"stop_loss"},
+	}
+	fillPointers(reflect.ValueOf(original).Elem())
+
+	clone := original.Clone()
+	if !reflect.DeepEqual(original, clone) {
+		t.Fatalf("Clone() = %+v, want %+v", clone, original)
+	}
+
+	// Every pointer and slice field must be copied, not shared
+	ov := reflect.ValueOf(original).Elem()
+	cv := reflect.ValueOf(clone).Elem()
+	for i := 0; i < ov.NumField(); i++ {
+		field := ov.Type().Field(i)
+		switch field.Type.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			if !ov.Field(i).IsNil() && ov.Field(i).Pointer() == cv.Field(i).Pointer() {
+				t.Errorf("field %s is shared between original and clone", field.Name)
+			}
+		}
+	}
+}
+
+func TestNormalizedCommand_CloneNil(t *testing.T) {
+	var cmd *NormalizedCommand
+	if cmd.Clone() != nil {
+		t.Error("Clone() of nil command should be nil")
+	}
+}
+
+// fillPointers allocates every nil pointer and empty slice field so that
+// aliasing checks cover fields added after this test was written
+func fillPointers(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		switch f.Kind() {
+		case reflect.Ptr:
+			if f.IsNil() {
+				f.Set(reflect.New(f.Type().Elem()))
+			}
+		case reflect.Slice:
+			if f.IsNil() {
+				f.Set(reflect.MakeSlice(f.Type(), 1, 1))
+			}
+		case reflect.Map:
+			if f.IsNil() {
+				f.Set(reflect.MakeMap(f.Type()))
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package intent
+
+import "context"
+
+// Capabilities describes what a processor implements natively, as opposed
+// to the defaults UpgradeProcessor fills in
+type Capabilities struct {
+	Batch  bool `json:"batch"`  // Implements BatchProcessor
+	Ping   bool `json:"ping"`   // Implements Pinger
+	Speech bool `json:"speech"` // Implements SpeechProcessor
+
+	Languages []string `json:"languages"`
+}
+
+// CapabilitiesOf reports the capabilities of p. Batch and Speech are
+// reported only when p itself implements them, since callers reach them by
+// type assertion and decorators such as CachingProcessor don't forward
+// them. Ping unwraps decorators, like the Ping function does.
+func CapabilitiesOf(p Processor) Capabilities {
+	caps := Capabilities{Languages: p.SupportedLanguages()}
+
+	outer := p
+	if u, ok := outer.(upgraded); ok {
+		outer = u.Processor
+	}
+	_, caps.Batch = outer.(BatchProcessor)
+	_, caps.Speech = outer.(SpeechProcessor)
+
+	for q := p; q != nil && !caps.Ping; q = Unwrap(q) {
+		if _, ok := q.(upgraded); ok {
+			continue
+		}
+		_, caps.Ping = q.(Pinger)
+	}
+	return caps
+}
+
+// UpgradedProcessor is the full processor surface. As methods are added to
+// it, UpgradeProcessor gives existing processors a default implementation,
+// so third-party processors written against Processor keep compiling and
+// callers can rely on every method being present.
+type UpgradedProcessor interface {
+	BatchProcessor
+	Pinger
+
+	// ParseCommandWithOptions parses input with per-request options, on
+	// top of any already in ctx
+	ParseCommandWithOptions(ctx context.Context, input string, opts ...ParseOption) (*NormalizedCommand, error)
+
+	// Capabilities reports what the underlying processor implements natively
+	Capabilities() Capabilities
+}
+
+// UpgradeProcessor adapts p to UpgradedProcessor. Methods p implements are
+// used as is; the rest default to sequential parsing for ParseCommands, an
+// always-healthy Ping and options passed through the context. p is
+// returned unchanged when it already implements UpgradedProcessor.
+func UpgradeProcessor(p Processor) UpgradedProcessor {
+	if up, ok := p.(UpgradedProcessor); ok {
+		return up
+	}
+	return upgraded{p}
+}
+
+type upgraded struct {
+	Processor
+}
+
+func (u upgraded) ParseCommands(ctx context.Context, inputs []string) ([]*NormalizedCommand, error) {
+	return ParseCommands(ctx, u.Processor, inputs)
+}
+
+func (u upgraded) Ping(ctx context.Context) error {
+	return Ping(ctx, u.Processor)
+}
+
+func (u upgraded) ParseCommandWithOptions(ctx context.Context, input string, opts ...ParseOption) (*NormalizedCommand, error) {
+	return u.Processor.ParseCommand(WithParseOptions(ctx, opts...), input)
+}
+
+func (u upgraded) Capabilities() Capabilities {
+	return CapabilitiesOf(u.Processor)
+}
+
+// Unwrap returns the upgraded processor
+func (u upgraded) Unwrap() Processor {
+	return u.Processor
+}
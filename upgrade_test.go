@@ -0,0 +1,106 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// localeProcessor records the locale it was called with
+type localeProcessor struct {
+	stubProcessor
+	locale string
+}
+
+func (p *localeProcessor) ParseCommand(ctx context.Context, input string) (*NormalizedCommand, error) {
+	p.locale = ParseOptionsFromContext(ctx).Locale
+	return p.stubProcessor.ParseCommand(ctx, input)
+}
+
+// pingProcessor is a processor with a native health check
+type pingProcessor struct {
+	stubProcessor
+	pingErr error
+}
+
+func (p *pingProcessor) Ping(ctx context.Context) error { return p.pingErr }
+
+func TestUpgradeProcessor_Defaults(t *testing.T) {
+	legacy := &localeProcessor{}
+	up := UpgradeProcessor(legacy)
+
+	cmds, err := up.ParseCommands(context.Background(), []string{"a", "b"})
+	if err != nil || len(cmds) != 2 || cmds[1].RawInput != "b" {
+		t.Fatalf("ParseCommands() = %v, %v", cmds, err)
+	}
+	if err := up.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() = %v, want nil for a processor without a health check", err)
+	}
+	if _, err := up.ParseCommandWithOptions(context.Background(), "a", WithLocale("es_AR")); err != nil {
+		t.Fatalf("ParseCommandWithOptions() error: %v", err)
+	}
+	if legacy.locale != "es_AR" {
+		t.Errorf("locale = %q, want es_AR", legacy.locale)
+	}
+
+	caps := up.Capabilities()
+	if caps.Batch || caps.Ping || caps.Speech {
+		t.Errorf("Capabilities() = %+v, want nothing native", caps)
+	}
+	if len(caps.Languages) != 1 || caps.Languages[0] != "en" {
+		t.Errorf("Languages = %v, want [en]", caps.Languages)
+	}
+	if Unwrap(up) != legacy {
+		t.Error("Unwrap() doesn't return the legacy processor")
+	}
+}
+
+func TestUpgradeProcessor_NativeMethods(t *testing.T) {
+	errDown := errors.New("backend down")
+	up := UpgradeProcessor(&pingProcessor{pingErr: errDown})
+
+	if err := up.Ping(context.Background()); !errors.Is(err, errDown) {
+		t.Errorf("Ping() = %v, want the native result", err)
+	}
+	if caps := up.Capabilities(); !caps.Ping || caps.Batch {
+		t.Errorf("Capabilities() = %+v, want only Ping", caps)
+	}
+}
+
+// batchProcessor parses batches natively
+type batchProcessor struct {
+	stubProcessor
+}
+
+func (p *batchProcessor) ParseCommands(ctx context.Context, inputs []string) ([]*NormalizedCommand, error) {
+	return ParseConcurrently(ctx, p, inputs, 2)
+}
+
+func TestCapabilitiesOf_Decorators(t *testing.T) {
+	native := &batchProcessor{}
+	if caps := CapabilitiesOf(native); !caps.Batch {
+		t.Errorf("Capabilities() = %+v, want Batch", caps)
+	}
+
+	// Decorators don't forward ParseCommands, so a type assertion on them fails
+	for name, p := range map[string]Processor{
+		"cache":      NewCachingProcessor(native, 0, 0),
+		"middleware": Chain(native, EventsMiddleware(nil)),
+		"upgraded":   UpgradeProcessor(NewCachingProcessor(native, 0, 0)),
+	} {
+		if caps := CapabilitiesOf(p); caps.Batch {
+			t.Errorf("%s: Capabilities() = %+v, want no Batch", name, caps)
+		}
+	}
+
+	if caps := CapabilitiesOf(NewCachingProcessor(&pingProcessor{}, 0, 0)); !caps.Ping {
+		t.Errorf("Capabilities() = %+v, want Ping through the decorator", caps)
+	}
+}
+
+func TestUpgradeProcessor_Idempotent(t *testing.T) {
+	up := UpgradeProcessor(&stubProcessor{})
+	if again := UpgradeProcessor(up); again != up {
+		t.Error("upgrading an upgraded processor wraps it again")
+	}
+}
@@ -0,0 +1,30 @@
+package intent
+
+import "context"
+
+// User identifies who issued a command
+type User struct {
+	ID   string
+	Name string
+}
+
+type userContextKey struct{}
+
+// WithUser returns a copy of ctx carrying user. Processors stamp the user ID
+// on the commands they produce, and decorators such as the cache keep
+// per-user state, so identity flows without extra parameters.
+func WithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the user stored in ctx by WithUser
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userContextKey{}).(User)
+	return user, ok
+}
+
+// UserIDFromContext returns the ID of the user stored in ctx, or "" when absent
+func UserIDFromContext(ctx context.Context) string {
+	user, _ := UserFromContext(ctx)
+	return user.ID
+}
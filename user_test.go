@@ -0,0 +1,26 @@
+package intent
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUserContext(t *testing.T) {
+	ctx := context.Background()
+
+	if _, ok := UserFromContext(ctx); ok {
+		t.Error("UserFromContext on empty context returned ok")
+	}
+	if id := UserIDFromContext(ctx); id != "" {
+		t.Errorf("UserIDFromContext = %q, want empty", id)
+	}
+
+	ctx = WithUser(ctx, User{ID: "u-42", Name: "Ana"})
+	user, ok := UserFromContext(ctx)
+	if !ok || user.ID != "u-42" || user.Name != "Ana" {
+		t.Errorf("UserFromContext = (%+v, %v), want u-42/Ana", user, ok)
+	}
+	if id := UserIDFromContext(ctx); id != "u-42" {
+		t.Errorf("UserIDFromContext = %q, want %q", id, "u-42")
+	}
+}
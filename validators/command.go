@@ -2,115 +2,739 @@ package validators
 
 import (
 	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/agatticelli/intent-go"
 )
 
 // ValidateCommand validates a NormalizedCommand and populates errors
-func ValidateCommand(cmd *intent.NormalizedCommand) {
+// using DefaultPolicy. Options add checks such as WithRules.
+func ValidateCommand(cmd *intent.NormalizedCommand, opts ...ValidatorOption) {
+	validate(cmd, DefaultPolicy(), builtinValidator, opts)
+}
+
+// validate resets the validation status of cmd and checks it with the
+// validator registered for its intent, falling back to the Validate hook of
+// a custom intent, followed by the checks shared by every intent and the
+// rules in opts
+func validate(cmd *intent.NormalizedCommand, policy Policy, lookup func(intent.Intent) (Validator, bool), opts []ValidatorOption) {
+	o := newValidateOptions(opts)
 	cmd.Valid = true
 	cmd.Missing = []string{}
 	cmd.Errors = []string{}
+	cmd.Warnings = nil
+	cmd.Issues = nil
+	cmd.ConfirmationRequired = false
+
+	for _, err := range intent.CheckNumericFields(cmd) {
+		code := intent.IssueCodeNotPositive
+		if !err.Finite() {
+			code = intent.IssueCodeInvalid
+		}
+		reject(cmd, code, err.Field, err.Error())
+	}
+	validateRanges(cmd, policy)
+
+	if validator, ok := lookup(cmd.Intent); ok {
+		validator(cmd, policy)
+	} else if spec, ok := intent.LookupIntent(cmd.Intent); ok {
+		if spec.Validate != nil {
+			spec.Validate(cmd)
+		}
+	} else {
+		reject(cmd, intent.IssueCodeUnknownIntent, "intent", fmt.Sprintf("unknown intent: %s", cmd.Intent))
+	}
+
+	if cmd.OrderType != "" {
+		validateOrderType(cmd)
+	}
+	if cmd.Exchange != "" {
+		validateExchange(cmd, policy)
+	}
+	if cmd.Account != "" && policy.AccountExists != nil && !policy.AccountExists(cmd.Account) {
+		reject(cmd, intent.IssueCodeNotFound, "account", fmt.Sprintf("account %s not found", cmd.Account))
+	}
+	if cmd.TimeInForce != "" {
+		validateTimeInForce(cmd)
+	}
+	if cmd.ExpiresAt != nil {
+		validateExpiresAt(cmd, o.issuedAt(cmd))
+	}
+	if cmd.MarginMode != "" {
+		validateMarginMode(cmd)
+	}
+	if cmd.ReduceOnly != nil && *cmd.ReduceOnly {
+		validateReduceOnly(cmd)
+	}
+	if cmd.Timeframe != "" && !intent.KnownTimeframe(cmd.Timeframe) {
+		reject(cmd, intent.IssueCodeUnsupported, "timeframe", fmt.Sprintf("unsupported timeframe: %s", cmd.Timeframe))
+	}
+	if cmd.Condition != nil {
+		validateCondition(cmd)
+	}
+	if policy.MaxTakeProfitDistance > 0 && cmd.EntryPrice != nil {
+		warnFarTakeProfit(cmd, policy)
+	}
+	if policy.UsualRiskPercent != nil && cmd.RiskPercent != nil {
+		warnUnusualRisk(cmd, policy)
+	}
+	o.applyRules(cmd)
+
+	// Registered validators may report through Missing and Errors alone
+	cmd.SyncIssues()
+}
+
+func validateOrderType(cmd *intent.NormalizedCommand) {
+	switch cmd.OrderType {
+	case intent.OrderTypeMarket:
+		// Fills at the current price, no prices required
+	case intent.OrderTypeLimit:
+		requireField(cmd, cmd.EntryPrice != nil, "entry_price")
+	case intent.OrderTypeStopLimit:
+		requireField(cmd, cmd.EntryPrice != nil, "entry_price")
+		requireField(cmd, cmd.TriggerPrice != nil, "trigger_price")
+	default:
+		reject(cmd, intent.IssueCodeUnsupported, "order_type", fmt.Sprintf("unsupported order type: %s", cmd.OrderType))
+	}
+}
+
+func validateExchange(cmd *intent.NormalizedCommand, policy Policy) {
+	if len(policy.AllowedExchanges) > 0 && !containsString(policy.AllowedExchanges, cmd.Exchange) {
+		reject(cmd, intent.IssueCodeNotAllowed, "exchange", fmt.Sprintf("exchange %s is not allowed", cmd.Exchange))
+	}
+}
+
+// validateTimeInForce checks the time in force is known and can be combined
+// with the order type. An empty order type leaves the choice to the
+// executor, so only explicit combinations are rejected.
+func validateTimeInForce(cmd *intent.NormalizedCommand) {
+	switch cmd.TimeInForce {
+	case intent.TimeInForceIOC, intent.TimeInForceFOK:
+	case intent.TimeInForceGTC:
+		if cmd.OrderType == intent.OrderTypeMarket {
+			reject(cmd, intent.IssueCodeConflict, "time_in_force", "time in force gtc is not valid for market orders")
+		}
+	case intent.TimeInForcePostOnly:
+		if cmd.OrderType != "" && cmd.OrderType != intent.OrderTypeLimit {
+			reject(cmd, intent.IssueCodeConflict, "time_in_force", fmt.Sprintf("time in force post_only requires a limit order, got %s", cmd.OrderType))
+		}
+	default:
+		reject(cmd, intent.IssueCodeUnsupported, "time_in_force", fmt.Sprintf("unsupported time in force: %s", cmd.TimeInForce))
+	}
+}
 
+// validateReduceOnly rejects reduce-only on intents that open or add to a
+// position, which the exchange would refuse anyway
+func validateReduceOnly(cmd *intent.NormalizedCommand) {
 	switch cmd.Intent {
-	case intent.IntentOpenPosition:
-		validateOpenPosition(cmd)
-	case intent.IntentClosePosition:
-		validateClosePosition(cmd)
-	case intent.IntentTrailingStop:
-		validateTrailingStop(cmd)
-	case intent.IntentBreakEven:
-		validateBreakEven(cmd)
-	case intent.IntentCancelOrders, intent.IntentViewPositions, intent.IntentViewOrders, intent.IntentCheckBalance:
-		// These intents don't require validation (optional symbol filter)
+	case intent.IntentOpenPosition, intent.IntentDCAOrder:
+		reject(cmd, intent.IssueCodeConflict, "reduce_only", fmt.Sprintf("reduce_only is not allowed for %s", cmd.Intent))
+	}
+}
+
+// validateExpiresAt requires the expiry to be after issued (see
+// validateOptions.issuedAt)
+func validateExpiresAt(cmd *intent.NormalizedCommand, issued time.Time) {
+	if !cmd.ExpiresAt.After(issued) {
+		reject(cmd, intent.IssueCodeOutOfRange, "expires_at", "expires_at must be in the future")
+	}
+}
+
+func validateMarginMode(cmd *intent.NormalizedCommand) {
+	switch cmd.MarginMode {
+	case intent.MarginModeCross, intent.MarginModeIsolated:
 	default:
-		cmd.Valid = false
-		cmd.Errors = append(cmd.Errors, fmt.Sprintf("unknown intent: %s", cmd.Intent))
+		reject(cmd, intent.IssueCodeUnsupported, "margin_mode", fmt.Sprintf("unsupported margin mode: %s", cmd.MarginMode))
+	}
+}
+
+// requireField reports name as missing unless present or already reported
+func requireField(cmd *intent.NormalizedCommand, present bool, name string) {
+	if !present && !containsString(cmd.Missing, name) {
+		missing(cmd, name)
+	}
+}
+
+// warnFarTakeProfit warns about targets further from the entry than the
+// policy allows, a common sign of a misheard price ("47000" for "4700")
+func warnFarTakeProfit(cmd *intent.NormalizedCommand, policy Policy) {
+	entry := *cmd.EntryPrice
+	far := func(price float64) bool {
+		return policy.exceeds(math.Abs(price-entry), entry*policy.MaxTakeProfitDistance)
+	}
+
+	if cmd.TakeProfit != nil && far(*cmd.TakeProfit) {
+		warn(cmd, intent.IssueCodeFarFromEntry, "take_profit", fmt.Sprintf("take_profit is more than %g%% away from entry_price", policy.MaxTakeProfitDistance*100))
+	}
+	for _, tp := range cmd.TPLevels {
+		if far(tp.Price) {
+			warn(cmd, intent.IssueCodeFarFromEntry, "tp_levels", fmt.Sprintf("tp_levels are more than %g%% away from entry_price", policy.MaxTakeProfitDistance*100))
+			return
+		}
+	}
+}
+
+// warnUnusualRisk warns about a risk above the user's usual one
+func warnUnusualRisk(cmd *intent.NormalizedCommand, policy Policy) {
+	usual := policy.UsualRiskPercent(cmd.UserID)
+	if usual > 0 && policy.exceeds(*cmd.RiskPercent, usual) {
+		warn(cmd, intent.IssueCodeAboveUsual, "risk_percent", fmt.Sprintf("risk_percent %g%% is above your usual %g%%", *cmd.RiskPercent, usual))
+	}
+}
+
+// missing records a required field that wasn't given
+func missing(cmd *intent.NormalizedCommand, field string) {
+	cmd.AddIssue(intent.MissingIssue(field))
+}
+
+// reject records a validation error on field
+func reject(cmd *intent.NormalizedCommand, code intent.IssueCode, field, message string) {
+	cmd.AddIssue(intent.ValidationIssue{Code: code, Field: field, Message: message, Severity: intent.SeverityError})
+}
+
+// warn records a warning on field, which asks for confirmation without
+// rejecting the command
+func warn(cmd *intent.NormalizedCommand, code intent.IssueCode, field, message string) {
+	cmd.AddIssue(intent.ValidationIssue{Code: code, Field: field, Message: message, Severity: intent.SeverityWarning})
+}
+
+func validateCondition(cmd *intent.NormalizedCommand) {
+	// The observed symbol may differ from the action symbol, but both must resolve
+	if cmd.Condition.ObservedSymbol(cmd.Symbol) == "" {
+		missing(cmd, "condition_symbol")
+	}
+	if cmd.Condition.Symbol != "" && cmd.Symbol == "" && !containsString(cmd.Missing, "symbol") {
+		missing(cmd, "symbol")
+	}
+
+	// A condition can't be evaluated without both sides of the comparison
+	requireField(cmd, cmd.Condition.Operator != "", "condition_operator")
+	requireField(cmd, cmd.Condition.Price != nil, "condition_price")
+
+	if op := cmd.Condition.Operator; op != "" && op != intent.ConditionAbove && op != intent.ConditionBelow {
+		reject(cmd, intent.IssueCodeUnsupported, "condition", fmt.Sprintf("unsupported condition operator: %s", op))
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
 	}
+	return false
 }
 
-func validateOpenPosition(cmd *intent.NormalizedCommand) {
-	// Required: symbol, side, entry price, stop loss, risk
+func validateOpenPosition(cmd *intent.NormalizedCommand, policy Policy) {
+	// Required: symbol, side, entry price (unless at market), stop loss and
+	// a size, either as risk or as quantity
 	if cmd.Symbol == "" {
-		cmd.Missing = append(cmd.Missing, "symbol")
-		cmd.Valid = false
+		missing(cmd, "symbol")
 	}
 	if cmd.Side == nil {
-		cmd.Missing = append(cmd.Missing, "side")
-		cmd.Valid = false
+		missing(cmd, "side")
 	}
-	if cmd.EntryPrice == nil {
-		cmd.Missing = append(cmd.Missing, "entry_price")
-		cmd.Valid = false
+	if cmd.EntryPrice == nil && cmd.OrderType != intent.OrderTypeMarket {
+		missing(cmd, "entry_price")
 	}
-	if cmd.StopLoss == nil {
-		cmd.Missing = append(cmd.Missing, "stop_loss")
-		cmd.Valid = false
+	if cmd.StopLoss == nil && len(cmd.SLLevels) == 0 {
+		missing(cmd, "stop_loss")
 	}
-	if cmd.RiskPercent == nil {
-		cmd.Missing = append(cmd.Missing, "risk_percent")
-		cmd.Valid = false
+	if cmd.RiskPercent == nil && cmd.Quantity == nil && cmd.NotionalUSD == nil {
+		missing(cmd, "risk_percent or quantity or notional_usd")
 	}
 
-	// Validate ranges
-	if cmd.RiskPercent != nil && (*cmd.RiskPercent <= 0 || *cmd.RiskPercent > 100) {
-		cmd.Errors = append(cmd.Errors, "risk_percent must be between 0 and 100")
-		cmd.Valid = false
-	}
+	validateSizing(cmd)
 
 	// Validate price logic
+	validateStopLossSide(cmd, policy)
+
+	if len(cmd.SLLevels) > 0 {
+		validateSLLevels(cmd, policy)
+	}
+
+	validateTPPercentages(cmd, policy)
+}
+
+// validateTPPercentages checks that the TP levels close at most the whole
+// position
+func validateTPPercentages(cmd *intent.NormalizedCommand, policy Policy) {
+	totalPct := 0.0
+	for _, tp := range cmd.TPLevels {
+		totalPct += tp.Percentage
+	}
+	if policy.exceeds(totalPct, 100) {
+		reject(cmd, intent.IssueCodeOutOfRange, "tp_levels", fmt.Sprintf("TP percentages sum to %.1f%%, cannot exceed 100%%", totalPct))
+	}
+}
+
+func validateSetTakeProfit(cmd *intent.NormalizedCommand, policy Policy) {
+	// Required: the position and its new target, single or laddered
+	if cmd.Symbol == "" {
+		missing(cmd, "symbol")
+	}
+	if cmd.TakeProfit == nil && len(cmd.TPLevels) == 0 {
+		missing(cmd, "take_profit or tp_levels")
+	}
+	if cmd.TakeProfit != nil && len(cmd.TPLevels) > 0 {
+		reject(cmd, intent.IssueCodeConflict, "take_profit", "take_profit and tp_levels are mutually exclusive")
+	}
+	validateTPPercentages(cmd, policy)
+
+	if cmd.Side == nil {
+		return
+	}
+	// Targets sit on the winning side: for a LONG each one is above the
+	// previous price, starting at the entry when given; for a SHORT, below
+	validateTakeProfitSide(cmd, policy)
+	prev := cmd.EntryPrice
+	for i := range cmd.TPLevels {
+		price := cmd.TPLevels[i].Price
+		if prev != nil {
+			if *cmd.Side == intent.SideLong && !policy.exceeds(price, *prev) {
+				reject(cmd, intent.IssueCodePriceSide, "tp_levels", "tp_levels must be above entry_price and ascending for LONG")
+				return
+			}
+			if *cmd.Side == intent.SideShort && !policy.exceeds(*prev, price) {
+				reject(cmd, intent.IssueCodePriceSide, "tp_levels", "tp_levels must be below entry_price and descending for SHORT")
+				return
+			}
+		}
+		prev = &cmd.TPLevels[i].Price
+	}
+}
+
+// validateSizing rejects commands sized more than one way, e.g. both by
+// risk and by dollar amount
+func validateSizing(cmd *intent.NormalizedCommand) {
+	var sizes []string
+	if cmd.RiskPercent != nil {
+		sizes = append(sizes, "risk_percent")
+	}
+	if cmd.Quantity != nil {
+		sizes = append(sizes, "quantity")
+	}
+	if cmd.NotionalUSD != nil {
+		sizes = append(sizes, "notional_usd")
+	}
+	if len(sizes) > 1 {
+		list := strings.Join(sizes[:len(sizes)-1], ", ") + " and " + sizes[len(sizes)-1]
+		reject(cmd, intent.IssueCodeConflict, sizes[0], list+" are mutually exclusive")
+	}
+}
+
+// validateStopLossSide checks that the stop loss is on the losing side of
+// the entry, when both and the side are known
+func validateStopLossSide(cmd *intent.NormalizedCommand, policy Policy) {
 	if cmd.Side != nil && cmd.EntryPrice != nil && cmd.StopLoss != nil {
-		if *cmd.Side == intent.SideLong && *cmd.StopLoss >= *cmd.EntryPrice {
-			cmd.Errors = append(cmd.Errors, "stop_loss must be below entry_price for LONG")
-			cmd.Valid = false
+		if *cmd.Side == intent.SideLong && !policy.exceeds(*cmd.EntryPrice, *cmd.StopLoss) {
+			reject(cmd, intent.IssueCodePriceSide, "stop_loss", "stop_loss must be below entry_price for LONG")
 		}
-		if *cmd.Side == intent.SideShort && *cmd.StopLoss <= *cmd.EntryPrice {
-			cmd.Errors = append(cmd.Errors, "stop_loss must be above entry_price for SHORT")
-			cmd.Valid = false
+		if *cmd.Side == intent.SideShort && !policy.exceeds(*cmd.StopLoss, *cmd.EntryPrice) {
+			reject(cmd, intent.IssueCodePriceSide, "stop_loss", "stop_loss must be above entry_price for SHORT")
 		}
 	}
+}
 
-	// Validate TP levels
-	if len(cmd.TPLevels) > 0 {
-		totalPct := 0.0
-		for _, tp := range cmd.TPLevels {
-			totalPct += tp.Percentage
+// validateTakeProfitSide checks that the take profit is on the winning side
+// of the entry, when both and the side are known
+func validateTakeProfitSide(cmd *intent.NormalizedCommand, policy Policy) {
+	if cmd.Side != nil && cmd.EntryPrice != nil && cmd.TakeProfit != nil {
+		if *cmd.Side == intent.SideLong && !policy.exceeds(*cmd.TakeProfit, *cmd.EntryPrice) {
+			reject(cmd, intent.IssueCodePriceSide, "take_profit", "take_profit must be above entry_price for LONG")
 		}
-		if totalPct > 100 {
-			cmd.Errors = append(cmd.Errors, fmt.Sprintf("TP percentages sum to %.1f%%, cannot exceed 100%%", totalPct))
-			cmd.Valid = false
+		if *cmd.Side == intent.SideShort && !policy.exceeds(*cmd.EntryPrice, *cmd.TakeProfit) {
+			reject(cmd, intent.IssueCodePriceSide, "take_profit", "take_profit must be below entry_price for SHORT")
 		}
 	}
 }
 
+// validateSLLevels checks a multi-level stop loss: prices are on the losing
+// side of the entry and move away from it level by level, and the
+// percentages close at most the whole position
+func validateSLLevels(cmd *intent.NormalizedCommand, policy Policy) {
+	if cmd.StopLoss != nil {
+		reject(cmd, intent.IssueCodeConflict, "stop_loss", "stop_loss and sl_levels are mutually exclusive")
+	}
+
+	totalPct := 0.0
+	for _, sl := range cmd.SLLevels {
+		if !(sl.Price > 0 && sl.Percentage > 0) {
+			return // Reported by CheckNumericFields
+		}
+		totalPct += sl.Percentage
+	}
+	if policy.exceeds(totalPct, 100) {
+		reject(cmd, intent.IssueCodeOutOfRange, "sl_levels", fmt.Sprintf("SL percentages sum to %.1f%%, cannot exceed 100%%", totalPct))
+	}
+
+	if cmd.Side == nil {
+		return
+	}
+	// For a LONG every stop is below the previous price, starting at the
+	// entry; for a SHORT, above it
+	prev := cmd.EntryPrice
+	for i := range cmd.SLLevels {
+		price := cmd.SLLevels[i].Price
+		if prev != nil {
+			if *cmd.Side == intent.SideLong && !policy.exceeds(*prev, price) {
+				reject(cmd, intent.IssueCodePriceSide, "sl_levels", "sl_levels must be below entry_price and descending for LONG")
+				return
+			}
+			if *cmd.Side == intent.SideShort && !policy.exceeds(price, *prev) {
+				reject(cmd, intent.IssueCodePriceSide, "sl_levels", "sl_levels must be above entry_price and ascending for SHORT")
+				return
+			}
+		}
+		prev = &cmd.SLLevels[i].Price
+	}
+}
+
+// Leverage bounds accepted by validateRanges
+const (
+	minLeverage = 1
+	maxLeverage = 125
+)
+
+// validateRanges checks the bounded values of every intent: leverage and
+// percentages. NaN and ±Inf are left to CheckNumericFields.
+func validateRanges(cmd *intent.NormalizedCommand, policy Policy) {
+	if v := cmd.RiskPercent; finite(v) && (*v <= 0 || policy.exceeds(*v, 100)) {
+		reject(cmd, intent.IssueCodeOutOfRange, "risk_percent", "risk_percent must be between 0 and 100")
+	}
+	if v := cmd.Leverage; finite(v) && (*v < minLeverage || *v > maxLeverage) {
+		reject(cmd, intent.IssueCodeOutOfRange, "leverage", fmt.Sprintf("leverage must be between %dx and %dx", minLeverage, maxLeverage))
+	}
+	if v := cmd.ClosePercent; finite(v) && (*v <= 0 || policy.exceeds(*v, 100)) {
+		reject(cmd, intent.IssueCodeOutOfRange, "close_percent", "close_percent must be greater than 0 and at most 100")
+	}
+	if v := cmd.HedgeRatio; finite(v) && (*v <= 0 || *v > 1) {
+		reject(cmd, intent.IssueCodeOutOfRange, "hedge_ratio", "hedge_ratio must be greater than 0 and at most 1")
+	}
+}
+
+// finite reports whether v is set to a number other than NaN and ±Inf
+func finite(v *float64) bool {
+	return v != nil && !math.IsNaN(*v) && !math.IsInf(*v, 0)
+}
+
+func validateSetLeverage(cmd *intent.NormalizedCommand) {
+	// Required: symbol and the new leverage
+	if cmd.Symbol == "" {
+		missing(cmd, "symbol")
+	}
+	if cmd.Leverage == nil {
+		missing(cmd, "leverage")
+	}
+}
+
+// minEntryLevels is the smallest ladder accepted by validateDCAOrder
+const minEntryLevels = 2
+
+func validateDCAOrder(cmd *intent.NormalizedCommand, policy Policy) {
+	// Required: symbol and a ladder of entries
+	if cmd.Symbol == "" {
+		missing(cmd, "symbol")
+	}
+	if len(cmd.EntryLevels) == 0 {
+		missing(cmd, "entry_levels")
+		return
+	}
+
+	if len(cmd.EntryLevels) < minEntryLevels {
+		reject(cmd, intent.IssueCodeOutOfRange, "entry_levels", fmt.Sprintf("a DCA ladder needs at least %d entry levels", minEntryLevels))
+	}
+
+	totalPct := 0.0
+	for _, level := range cmd.EntryLevels {
+		if !(level.Price > 0 && level.Percentage > 0) {
+			return // Reported by CheckNumericFields
+		}
+		totalPct += level.Percentage
+	}
+	if policy.exceeds(totalPct, 100) || policy.exceeds(100, totalPct) {
+		reject(cmd, intent.IssueCodeOutOfRange, "entry_levels", fmt.Sprintf("entry level percentages sum to %.1f%%, must be 100%%", totalPct))
+	}
+}
+
 func validateClosePosition(cmd *intent.NormalizedCommand) {
-	// Symbol is required
+	// Symbol is required; a partial close's close_percent is checked by
+	// validateRanges
 	if cmd.Symbol == "" {
-		cmd.Missing = append(cmd.Missing, "symbol")
-		cmd.Valid = false
+		missing(cmd, "symbol")
 	}
 }
 
 func validateTrailingStop(cmd *intent.NormalizedCommand) {
 	// Required: symbol, trigger price, callback rate or distance
 	if cmd.Symbol == "" {
-		cmd.Missing = append(cmd.Missing, "symbol")
-		cmd.Valid = false
+		missing(cmd, "symbol")
 	}
 	if cmd.TriggerPrice == nil {
-		cmd.Missing = append(cmd.Missing, "trigger_price")
-		cmd.Valid = false
+		missing(cmd, "trigger_price")
 	}
 	if cmd.CallbackRate == nil && cmd.Distance == nil {
-		cmd.Missing = append(cmd.Missing, "callback_rate or distance")
-		cmd.Valid = false
+		missing(cmd, "callback_rate or distance")
 	}
 }
 
 func validateBreakEven(cmd *intent.NormalizedCommand) {
 	// Symbol is required
 	if cmd.Symbol == "" {
-		cmd.Missing = append(cmd.Missing, "symbol")
-		cmd.Valid = false
+		missing(cmd, "symbol")
+	}
+}
+
+func validateModifyPosition(cmd *intent.NormalizedCommand, policy Policy) {
+	// Required: symbol plus at least one new protective level
+	if cmd.Symbol == "" {
+		missing(cmd, "symbol")
+	}
+	if cmd.StopLoss == nil && cmd.TakeProfit == nil {
+		missing(cmd, "stop_loss or take_profit")
+	}
+
+	// The new levels must sit on the right side of the entry
+	validateStopLossSide(cmd, policy)
+	validateTakeProfitSide(cmd, policy)
+}
+
+func validateSetStopLoss(cmd *intent.NormalizedCommand, policy Policy) {
+	// Required: the position and the stop to attach to it
+	if cmd.Symbol == "" {
+		missing(cmd, "symbol")
+	}
+	if cmd.StopLoss == nil {
+		missing(cmd, "stop_loss")
+	}
+
+	validateStopLossSide(cmd, policy)
+}
+
+func validateMoveStopLoss(cmd *intent.NormalizedCommand, policy Policy) {
+	// Required: symbol and either the new stop or an offset from the current one
+	if cmd.Symbol == "" {
+		missing(cmd, "symbol")
+	}
+	if cmd.StopLoss == nil && cmd.StopLossOffset == nil {
+		missing(cmd, "stop_loss or stop_loss_offset")
+	}
+
+	if cmd.StopLoss != nil && cmd.StopLossOffset != nil {
+		reject(cmd, intent.IssueCodeConflict, "stop_loss_offset", "stop_loss and stop_loss_offset are mutually exclusive")
+	}
+	if cmd.StopLossOffset != nil && *cmd.StopLossOffset == 0 {
+		reject(cmd, intent.IssueCodeInvalid, "stop_loss_offset", "stop_loss_offset must not be 0")
+	}
+
+	// The new stop must stay on the losing side of the entry
+	validateStopLossSide(cmd, policy)
+}
+
+func validateCopyTrade(cmd *intent.NormalizedCommand) {
+	// Required: target account. Symbol is optional (copy everything when empty)
+	if cmd.TargetAccount == "" {
+		missing(cmd, "target_account")
+	}
+
+	if cmd.SourceAccount != "" && cmd.SourceAccount == cmd.TargetAccount {
+		reject(cmd, intent.IssueCodeConflict, "target_account", "target_account must differ from source_account")
+	}
+}
+
+func validateCancelAlert(cmd *intent.NormalizedCommand) {
+	// Either a specific alert or every alert on a symbol must be selected
+	if cmd.AlertID == "" && cmd.Symbol == "" {
+		missing(cmd, "alert_id or symbol")
+	}
+}
+
+func validateCancelOrders(cmd *intent.NormalizedCommand) {
+	// Required: a specific order or an explicit "all", so a vague
+	// "cancel orders" never wipes the book
+	if cmd.OrderID == "" && !cmd.CancelAll {
+		missing(cmd, "order_id")
+	}
+	if cmd.OrderID != "" && cmd.CancelAll {
+		reject(cmd, intent.IssueCodeConflict, "order_id", "order_id and cancel_all are mutually exclusive")
+	}
+}
+
+func validateCheckOrderStatus(cmd *intent.NormalizedCommand) {
+	// Required: a specific order, the latest one, or the latest one on a
+	// symbol, so the status comes from the order the user means
+	if cmd.OrderID == "" && !cmd.LatestOrder && cmd.Symbol == "" {
+		missing(cmd, "order_id or symbol")
+	}
+	if cmd.OrderID != "" && cmd.LatestOrder {
+		reject(cmd, intent.IssueCodeConflict, "order_id", "order_id and latest_order are mutually exclusive")
+	}
+}
+
+func validateSetAlert(cmd *intent.NormalizedCommand) {
+	// Required: symbol and the alert price
+	if cmd.Symbol == "" {
+		missing(cmd, "symbol")
+	}
+	if cmd.AlertPrice == nil {
+		missing(cmd, "alert_price")
+	}
+
+	if dir := cmd.AlertDirection; dir != "" && dir != intent.ConditionAbove && dir != intent.ConditionBelow {
+		reject(cmd, intent.IssueCodeUnsupported, "alert_direction", fmt.Sprintf("unsupported alert direction: %s", dir))
+	}
+}
+
+func validateViewPnL(cmd *intent.NormalizedCommand) {
+	// Symbol and period are optional filters
+	if cmd.Period != "" && !cmd.Period.Known() {
+		reject(cmd, intent.IssueCodeUnsupported, "period", fmt.Sprintf("unsupported period: %s", cmd.Period))
+	}
+}
+
+func validateViewHistory(cmd *intent.NormalizedCommand) {
+	// Symbol and time range are optional filters
+	if cmd.From != nil && cmd.To != nil && !cmd.From.Before(*cmd.To) {
+		reject(cmd, intent.IssueCodeOutOfRange, "from", "from must be before to")
+	}
+}
+
+func validateViewPrice(cmd *intent.NormalizedCommand) {
+	// Required: the symbol to quote
+	if cmd.Symbol == "" {
+		missing(cmd, "symbol")
+	}
+}
+
+func validateViewPosition(cmd *intent.NormalizedCommand) {
+	// Required: the position to detail; without one it's view_positions
+	if cmd.Symbol == "" {
+		missing(cmd, "symbol")
+	}
+}
+
+func validateHedgePosition(cmd *intent.NormalizedCommand) {
+	// Required: the position to hedge
+	if cmd.Symbol == "" {
+		missing(cmd, "symbol")
+	}
+}
+
+func validatePanicClose(cmd *intent.NormalizedCommand) {
+	// Flattening every position is irreversible and typed under stress,
+	// always confirm
+	cmd.ConfirmationRequired = true
+
+	// The scope is Exchange and Account, empty meaning all of them; closing
+	// a single symbol is close_position
+	if cmd.Symbol != "" {
+		reject(cmd, intent.IssueCodeConflict, "symbol", fmt.Sprintf("panic_close closes every position, use close_position for %s", cmd.Symbol))
+	}
+}
+
+func validateCloseAll(cmd *intent.NormalizedCommand) {
+	// Closing the whole book is irreversible, always confirm
+	cmd.ConfirmationRequired = true
+
+	// Symbols are only ever excluded; closing a single symbol is
+	// close_position
+	if cmd.Symbol != "" {
+		reject(cmd, intent.IssueCodeConflict, "symbol", fmt.Sprintf("close_all closes every position, use close_position for %s", cmd.Symbol))
+	}
+	seen := make(map[string]bool, len(cmd.ExcludeSymbols))
+	for _, symbol := range cmd.ExcludeSymbols {
+		if seen[symbol] {
+			reject(cmd, intent.IssueCodeInvalid, "exclude_symbols", fmt.Sprintf("%s is excluded twice", symbol))
+		}
+		seen[symbol] = true
+	}
+}
+
+func validateSetRiskDefaults(cmd *intent.NormalizedCommand) {
+	// Required: the new default risk
+	if cmd.RiskPercent == nil {
+		missing(cmd, "risk_percent")
+	}
+
+	// Changing defaults affects every future order, always confirm
+	cmd.ConfirmationRequired = true
+}
+
+// Grid level bounds accepted by validateSetupGrid
+const (
+	minGridLevels = 2
+	maxGridLevels = 200
+)
+
+func validateSetupGrid(cmd *intent.NormalizedCommand, policy Policy) {
+	// Required: symbol, range bounds, level count
+	if cmd.Symbol == "" {
+		missing(cmd, "symbol")
+	}
+	if cmd.GridLower == nil {
+		missing(cmd, "grid_lower")
+	}
+	if cmd.GridUpper == nil {
+		missing(cmd, "grid_upper")
+	}
+	if cmd.GridLevels == nil {
+		missing(cmd, "grid_levels")
+	}
+
+	// Validate range
+	if cmd.GridLower != nil && cmd.GridUpper != nil && !policy.exceeds(*cmd.GridUpper, *cmd.GridLower) {
+		reject(cmd, intent.IssueCodeOutOfRange, "grid_lower", "grid_lower must be below grid_upper")
+	}
+	if cmd.GridLevels != nil && (*cmd.GridLevels < minGridLevels || *cmd.GridLevels > maxGridLevels) {
+		reject(cmd, intent.IssueCodeOutOfRange, "grid_levels", fmt.Sprintf("grid_levels must be between %d and %d", minGridLevels, maxGridLevels))
+	}
+}
+
+func validateWithdraw(cmd *intent.NormalizedCommand, policy Policy) {
+	// Moving funds out of the exchange always requires explicit confirmation
+	cmd.ConfirmationRequired = true
+
+	if !policy.AllowWithdrawals {
+		reject(cmd, intent.IssueCodeNotAllowed, "", "withdrawals are disabled by policy")
+	}
+
+	// Required: asset, amount, whitelisted address reference
+	if cmd.Asset == "" {
+		missing(cmd, "asset")
+	}
+	if cmd.Amount == nil {
+		missing(cmd, "amount")
+	}
+	if cmd.AddressRef == "" {
+		missing(cmd, "address_ref")
+	}
+
+	switch {
+	case cmd.AddressRef == "" || !policy.AllowWithdrawals:
+	case looksLikeRawAddress(cmd.AddressRef):
+		reject(cmd, intent.IssueCodeNotAllowed, "address_ref", "address_ref must name a whitelisted address, not a raw address")
+	case policy.AddressWhitelisted == nil || !policy.AddressWhitelisted(cmd.AddressRef):
+		reject(cmd, intent.IssueCodeNotAllowed, "address_ref", fmt.Sprintf("address %s is not whitelisted", cmd.AddressRef))
+	}
+}
+
+// looksLikeRawAddress reports whether ref resembles an on-chain address
+// (0x-prefixed hex, or a long run of base58/bech32 characters) rather than
+// a whitelist label such as "ledger" or "cold wallet"
+func looksLikeRawAddress(ref string) bool {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(strings.ToLower(ref), "0x") && len(ref) >= 40 {
+		return true
+	}
+	if len(ref) < 26 {
+		return false
+	}
+	for _, r := range ref {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
 	}
+	return true
 }
@@ -1,7 +1,10 @@
 package validators
 
 import (
+	"math"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/agatticelli/intent-go"
 	"github.com/agatticelli/trading-common-types"
@@ -15,6 +18,22 @@ func sidePtr(s types.Side) *types.Side {
 	return &s
 }
 
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func equalStrings(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func TestValidateCommand_OpenPosition(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -95,7 +114,182 @@ func TestValidateCommand_OpenPosition(t *testing.T) {
 				StopLoss:   float64Ptr(44500.0),
 			},
 			wantValid:   false,
-			wantMissing: []string{"risk_percent"},
+			wantMissing: []string{"risk_percent or quantity or notional_usd"},
+		},
+		{
+			name: "Quantity instead of risk percent",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentOpenPosition,
+				Symbol:     "BTC-USDT",
+				Side:       sidePtr(types.SideLong),
+				EntryPrice: float64Ptr(45000.0),
+				StopLoss:   float64Ptr(44500.0),
+				Quantity:   float64Ptr(0.25),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Post only limit",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				OrderType:   intent.OrderTypeLimit,
+				TimeInForce: intent.TimeInForcePostOnly,
+				EntryPrice:  float64Ptr(45000.0),
+				StopLoss:    float64Ptr(44500.0),
+				RiskPercent: float64Ptr(2.0),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Post only market",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				OrderType:   intent.OrderTypeMarket,
+				TimeInForce: intent.TimeInForcePostOnly,
+				StopLoss:    float64Ptr(44500.0),
+				RiskPercent: float64Ptr(2.0),
+			},
+			wantValid:  false,
+			wantErrors: []string{"time in force post_only requires a limit order, got market"},
+		},
+		{
+			name: "GTC market",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				OrderType:   intent.OrderTypeMarket,
+				TimeInForce: intent.TimeInForceGTC,
+				StopLoss:    float64Ptr(44500.0),
+				RiskPercent: float64Ptr(2.0),
+			},
+			wantValid:  false,
+			wantErrors: []string{"time in force gtc is not valid for market orders"},
+		},
+		{
+			name: "IOC market",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				OrderType:   intent.OrderTypeMarket,
+				TimeInForce: intent.TimeInForceIOC,
+				StopLoss:    float64Ptr(44500.0),
+				RiskPercent: float64Ptr(2.0),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Reduce only",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				ReduceOnly:  boolPtr(true),
+				EntryPrice:  float64Ptr(45000.0),
+				StopLoss:    float64Ptr(44500.0),
+				RiskPercent: float64Ptr(2.0),
+			},
+			wantValid:  false,
+			wantErrors: []string{"reduce_only is not allowed for open_position"},
+		},
+		{
+			name: "Explicitly not reduce only",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				ReduceOnly:  boolPtr(false),
+				EntryPrice:  float64Ptr(45000.0),
+				StopLoss:    float64Ptr(44500.0),
+				RiskPercent: float64Ptr(2.0),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Isolated margin",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				MarginMode:  intent.MarginModeIsolated,
+				EntryPrice:  float64Ptr(45000.0),
+				StopLoss:    float64Ptr(44500.0),
+				RiskPercent: float64Ptr(2.0),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Unsupported margin mode",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				MarginMode:  "portfolio",
+				EntryPrice:  float64Ptr(45000.0),
+				StopLoss:    float64Ptr(44500.0),
+				RiskPercent: float64Ptr(2.0),
+			},
+			wantValid:  false,
+			wantErrors: []string{"unsupported margin mode: portfolio"},
+		},
+		{
+			name: "Quantity and risk percent",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				EntryPrice:  float64Ptr(45000.0),
+				StopLoss:    float64Ptr(44500.0),
+				RiskPercent: float64Ptr(2.0),
+				Quantity:    float64Ptr(0.25),
+			},
+			wantValid:  false,
+			wantErrors: []string{"risk_percent and quantity are mutually exclusive"},
+		},
+		{
+			name: "Notional size",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				EntryPrice:  float64Ptr(45000.0),
+				StopLoss:    float64Ptr(44500.0),
+				NotionalUSD: float64Ptr(500),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Every sizing method",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				EntryPrice:  float64Ptr(45000.0),
+				StopLoss:    float64Ptr(44500.0),
+				RiskPercent: float64Ptr(2.0),
+				Quantity:    float64Ptr(0.25),
+				NotionalUSD: float64Ptr(500),
+			},
+			wantValid:  false,
+			wantErrors: []string{"risk_percent, quantity and notional_usd are mutually exclusive"},
+		},
+		{
+			name: "Non-positive quantity",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentOpenPosition,
+				Symbol:     "BTC-USDT",
+				Side:       sidePtr(types.SideLong),
+				EntryPrice: float64Ptr(45000.0),
+				StopLoss:   float64Ptr(44500.0),
+				Quantity:   float64Ptr(0),
+			},
+			wantValid:  false,
+			wantErrors: []string{"quantity must be greater than 0"},
 		},
 		{
 			name: "Invalid risk percent - too high",
@@ -223,6 +417,7 @@ func TestValidateCommand_ClosePosition(t *testing.T) {
 		cmd         *intent.NormalizedCommand
 		wantValid   bool
 		wantMissing []string
+		wantErrors  []string
 	}{
 		{
 			name: "Valid close position",
@@ -233,6 +428,17 @@ func TestValidateCommand_ClosePosition(t *testing.T) {
 			wantValid:   true,
 			wantMissing: []string{},
 		},
+		{
+			name: "Negative distance",
+			cmd: &intent.NormalizedCommand{
+				Intent:       intent.IntentTrailingStop,
+				Symbol:       "BTC-USDT",
+				TriggerPrice: float64Ptr(46000.0),
+				Distance:     float64Ptr(-500.0),
+			},
+			wantValid:   false,
+			wantMissing: []string{},
+		},
 		{
 			name: "Missing symbol",
 			cmd: &intent.NormalizedCommand{
@@ -241,6 +447,44 @@ func TestValidateCommand_ClosePosition(t *testing.T) {
 			wantValid:   false,
 			wantMissing: []string{"symbol"},
 		},
+		{
+			name: "Partial close",
+			cmd: &intent.NormalizedCommand{
+				Intent:       intent.IntentClosePosition,
+				Symbol:       "ETH-USDT",
+				ClosePercent: float64Ptr(50),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Close percent of 100 closes everything",
+			cmd: &intent.NormalizedCommand{
+				Intent:       intent.IntentClosePosition,
+				Symbol:       "ETH-USDT",
+				ClosePercent: float64Ptr(100),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Zero close percent",
+			cmd: &intent.NormalizedCommand{
+				Intent:       intent.IntentClosePosition,
+				Symbol:       "ETH-USDT",
+				ClosePercent: float64Ptr(0),
+			},
+			wantValid:  false,
+			wantErrors: []string{"close_percent must be greater than 0 and at most 100"},
+		},
+		{
+			name: "Close percent above 100",
+			cmd: &intent.NormalizedCommand{
+				Intent:       intent.IntentClosePosition,
+				Symbol:       "ETH-USDT",
+				ClosePercent: float64Ptr(150),
+			},
+			wantValid:  false,
+			wantErrors: []string{"close_percent must be greater than 0 and at most 100"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -254,6 +498,10 @@ func TestValidateCommand_ClosePosition(t *testing.T) {
 			if len(tt.wantMissing) > 0 && len(tt.cmd.Missing) != len(tt.wantMissing) {
 				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
 			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
 		})
 	}
 }
@@ -375,41 +623,2209 @@ func TestValidateCommand_BreakEven(t *testing.T) {
 	}
 }
 
-func TestValidateCommand_ViewIntents(t *testing.T) {
-	// View intents don't require validation
-	intents := []intent.Intent{
-		intent.IntentViewPositions,
-		intent.IntentViewOrders,
-		intent.IntentCheckBalance,
-		intent.IntentCancelOrders,
+func TestValidateCommand_CopyTrade(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name: "Valid copy trade",
+			cmd: &intent.NormalizedCommand{
+				Intent:        intent.IntentCopyTrade,
+				Symbol:        "BTC-USDT",
+				SourceAccount: "main",
+				TargetAccount: "secondary",
+				SizeFactor:    float64Ptr(0.5),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Valid copy trade without symbol or factor",
+			cmd: &intent.NormalizedCommand{
+				Intent:        intent.IntentCopyTrade,
+				TargetAccount: "secondary",
+			},
+			wantValid: true,
+		},
+		{
+			name: "Missing target account",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentCopyTrade,
+				Symbol:     "BTC-USDT",
+				SizeFactor: float64Ptr(0.5),
+			},
+			wantValid:   false,
+			wantMissing: []string{"target_account"},
+		},
+		{
+			name: "Same source and target",
+			cmd: &intent.NormalizedCommand{
+				Intent:        intent.IntentCopyTrade,
+				SourceAccount: "main",
+				TargetAccount: "main",
+			},
+			wantValid:  false,
+			wantErrors: []string{"target_account must differ from source_account"},
+		},
+		{
+			name: "Invalid size factor",
+			cmd: &intent.NormalizedCommand{
+				Intent:        intent.IntentCopyTrade,
+				TargetAccount: "secondary",
+				SizeFactor:    float64Ptr(0),
+			},
+			wantValid:  false,
+			wantErrors: []string{"size_factor must be greater than 0"},
+		},
 	}
 
-	for _, intentType := range intents {
-		t.Run(string(intentType), func(t *testing.T) {
-			cmd := &intent.NormalizedCommand{
-				Intent: intentType,
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
 			}
 
-			ValidateCommand(cmd)
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
 
-			if !cmd.Valid {
-				t.Errorf("Valid = false, want true for %s", intentType)
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
 			}
 		})
 	}
 }
 
-func TestValidateCommand_UnknownIntent(t *testing.T) {
-	cmd := &intent.NormalizedCommand{
-		Intent: intent.IntentUnknown,
-	}
-
-	ValidateCommand(cmd)
+func intPtr(v int) *int {
+	return &v
+}
 
-	if cmd.Valid {
-		t.Error("Valid = true, want false for unknown intent")
-	}
-	if len(cmd.Errors) == 0 {
-		t.Error("Expected error for unknown intent")
+func TestValidateCommand_SetupGrid(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name: "Valid grid",
+			cmd: &intent.NormalizedCommand{
+				Intent:        intent.IntentSetupGrid,
+				Symbol:        "BTC-USDT",
+				GridLower:     float64Ptr(42000),
+				GridUpper:     float64Ptr(46000),
+				GridLevels:    intPtr(10),
+				GridLevelSize: float64Ptr(0.01),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Missing bounds and levels",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentSetupGrid,
+				Symbol: "BTC-USDT",
+			},
+			wantValid:   false,
+			wantMissing: []string{"grid_lower", "grid_upper", "grid_levels"},
+		},
+		{
+			name: "Inverted range",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentSetupGrid,
+				Symbol:     "BTC-USDT",
+				GridLower:  float64Ptr(46000),
+				GridUpper:  float64Ptr(42000),
+				GridLevels: intPtr(10),
+			},
+			wantValid:  false,
+			wantErrors: []string{"grid_lower must be below grid_upper"},
+		},
+		{
+			name: "Too many levels",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentSetupGrid,
+				Symbol:     "BTC-USDT",
+				GridLower:  float64Ptr(42000),
+				GridUpper:  float64Ptr(46000),
+				GridLevels: intPtr(1000),
+			},
+			wantValid:  false,
+			wantErrors: []string{"grid_levels must be between 2 and 200"},
+		},
+		{
+			name: "Single level",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentSetupGrid,
+				Symbol:     "BTC-USDT",
+				GridLower:  float64Ptr(42000),
+				GridUpper:  float64Ptr(46000),
+				GridLevels: intPtr(1),
+			},
+			wantValid:  false,
+			wantErrors: []string{"grid_levels must be between 2 and 200"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_CancelAlert(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+	}{
+		{
+			name: "Cancel by alert ID",
+			cmd: &intent.NormalizedCommand{
+				Intent:  intent.IntentCancelAlert,
+				AlertID: "a-123",
+			},
+			wantValid: true,
+		},
+		{
+			name: "Cancel by symbol",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentCancelAlert,
+				Symbol: "BTC-USDT",
+			},
+			wantValid: true,
+		},
+		{
+			name: "Missing selector",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentCancelAlert,
+			},
+			wantValid:   false,
+			wantMissing: []string{"alert_id or symbol"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_SetAlert(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name: "Alert on any cross",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentSetAlert,
+				Symbol:     "BTC-USDT",
+				AlertPrice: float64Ptr(50000),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Alert with direction",
+			cmd: &intent.NormalizedCommand{
+				Intent:         intent.IntentSetAlert,
+				Symbol:         "ETH-USDT",
+				AlertPrice:     float64Ptr(2800),
+				AlertDirection: intent.ConditionBelow,
+			},
+			wantValid: true,
+		},
+		{
+			name: "Missing fields",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentSetAlert,
+			},
+			wantValid:   false,
+			wantMissing: []string{"symbol", "alert_price"},
+		},
+		{
+			name: "Unsupported direction",
+			cmd: &intent.NormalizedCommand{
+				Intent:         intent.IntentSetAlert,
+				Symbol:         "ETH-USDT",
+				AlertPrice:     float64Ptr(2800),
+				AlertDirection: "sideways",
+			},
+			wantValid:  false,
+			wantErrors: []string{"unsupported alert direction: sideways"},
+		},
+		{
+			name: "Non-positive price",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentSetAlert,
+				Symbol:     "ETH-USDT",
+				AlertPrice: float64Ptr(-1),
+			},
+			wantValid:  false,
+			wantErrors: []string{"alert_price must be greater than 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_ViewPnL(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmd        *intent.NormalizedCommand
+		wantValid  bool
+		wantErrors []string
+	}{
+		{
+			name:      "No filters",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentViewPnL},
+			wantValid: true,
+		},
+		{
+			name: "Symbol and period",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentViewPnL,
+				Symbol: "BTC-USDT",
+				Period: intent.PeriodThisWeek,
+			},
+			wantValid: true,
+		},
+		{
+			name: "Unsupported period",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentViewPnL,
+				Period: "fortnight",
+			},
+			wantValid:  false,
+			wantErrors: []string{"unsupported period: fortnight"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_ViewPrice(t *testing.T) {
+	cmd := &intent.NormalizedCommand{Intent: intent.IntentViewPrice, Symbol: "ETH-USDT"}
+	ValidateCommand(cmd)
+	if !cmd.Valid {
+		t.Errorf("Valid = false, want true (errors %v)", cmd.Errors)
+	}
+
+	cmd = &intent.NormalizedCommand{Intent: intent.IntentViewPrice}
+	ValidateCommand(cmd)
+	if cmd.Valid || !equalStrings(cmd.Missing, []string{"symbol"}) {
+		t.Errorf("Valid = %v, Missing = %v, want invalid with [symbol]", cmd.Valid, cmd.Missing)
+	}
+}
+
+func TestValidateCommand_ViewFunding(t *testing.T) {
+	// Symbol is optional, without it all funding rates are listed
+	for _, symbol := range []string{"BTC-USDT", ""} {
+		cmd := &intent.NormalizedCommand{Intent: intent.IntentViewFunding, Symbol: symbol}
+		ValidateCommand(cmd)
+		if !cmd.Valid {
+			t.Errorf("symbol %q: Valid = false, want true (missing %v)", symbol, cmd.Missing)
+		}
+	}
+}
+
+func TestValidateCommand_HedgePosition(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name:      "Full hedge",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentHedgePosition, Symbol: "BTC-USDT"},
+			wantValid: true,
+		},
+		{
+			name: "Half hedge of a long",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentHedgePosition,
+				Symbol:     "BTC-USDT",
+				Side:       sidePtr(types.SideLong),
+				HedgeRatio: float64Ptr(0.5),
+			},
+			wantValid: true,
+		},
+		{
+			name:        "Missing symbol",
+			cmd:         &intent.NormalizedCommand{Intent: intent.IntentHedgePosition},
+			wantValid:   false,
+			wantMissing: []string{"symbol"},
+		},
+		{
+			name: "Ratio above 1",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentHedgePosition,
+				Symbol:     "BTC-USDT",
+				HedgeRatio: float64Ptr(1.5),
+			},
+			wantValid:  false,
+			wantErrors: []string{"hedge_ratio must be greater than 0 and at most 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_MoveStopLoss(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name: "New stop price",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentMoveStopLoss,
+				Symbol:   "BTC-USDT",
+				StopLoss: float64Ptr(44800),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Relative offset",
+			cmd: &intent.NormalizedCommand{
+				Intent:         intent.IntentMoveStopLoss,
+				Symbol:         "BTC-USDT",
+				StopLossOffset: float64Ptr(-50),
+			},
+			wantValid: true,
+		},
+		{
+			name:        "Missing stop",
+			cmd:         &intent.NormalizedCommand{Intent: intent.IntentMoveStopLoss, Symbol: "BTC-USDT"},
+			wantValid:   false,
+			wantMissing: []string{"stop_loss or stop_loss_offset"},
+		},
+		{
+			name: "Stop and offset",
+			cmd: &intent.NormalizedCommand{
+				Intent:         intent.IntentMoveStopLoss,
+				Symbol:         "BTC-USDT",
+				StopLoss:       float64Ptr(44800),
+				StopLossOffset: float64Ptr(100),
+			},
+			wantValid:  false,
+			wantErrors: []string{"stop_loss and stop_loss_offset are mutually exclusive"},
+		},
+		{
+			name: "Long stop above entry",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentMoveStopLoss,
+				Symbol:     "BTC-USDT",
+				Side:       sidePtr(types.SideLong),
+				EntryPrice: float64Ptr(45000),
+				StopLoss:   float64Ptr(45500),
+			},
+			wantValid:  false,
+			wantErrors: []string{"stop_loss must be below entry_price for LONG"},
+		},
+		{
+			name: "Short stop above entry",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentMoveStopLoss,
+				Symbol:     "BTC-USDT",
+				Side:       sidePtr(types.SideShort),
+				EntryPrice: float64Ptr(45000),
+				StopLoss:   float64Ptr(45500),
+			},
+			wantValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_ViewHistory(t *testing.T) {
+	from := time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	cmd := &intent.NormalizedCommand{Intent: intent.IntentViewHistory, Symbol: "BTC-USDT", From: &from, To: &to}
+	ValidateCommand(cmd)
+	if !cmd.Valid {
+		t.Errorf("Valid = false, want true (errors %v)", cmd.Errors)
+	}
+
+	cmd = &intent.NormalizedCommand{Intent: intent.IntentViewHistory, From: &to, To: &from}
+	ValidateCommand(cmd)
+	if cmd.Valid || !equalStrings(cmd.Errors, []string{"from must be before to"}) {
+		t.Errorf("Valid = %v, Errors = %v, want an inverted range error", cmd.Valid, cmd.Errors)
+	}
+}
+
+func TestValidateCommand_OrderType(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name: "Market order without entry price",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				OrderType:   intent.OrderTypeMarket,
+				StopLoss:    float64Ptr(44500),
+				RiskPercent: float64Ptr(2),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Limit order without entry price",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				OrderType:   intent.OrderTypeLimit,
+				StopLoss:    float64Ptr(44500),
+				RiskPercent: float64Ptr(2),
+			},
+			wantValid:   false,
+			wantMissing: []string{"entry_price"},
+		},
+		{
+			name: "Stop-limit order without trigger",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				OrderType:   intent.OrderTypeStopLimit,
+				EntryPrice:  float64Ptr(45000),
+				StopLoss:    float64Ptr(44500),
+				RiskPercent: float64Ptr(2),
+			},
+			wantValid:   false,
+			wantMissing: []string{"trigger_price"},
+		},
+		{
+			name: "Unsupported order type",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				OrderType:   "iceberg",
+				EntryPrice:  float64Ptr(45000),
+				StopLoss:    float64Ptr(44500),
+				RiskPercent: float64Ptr(2),
+			},
+			wantValid:  false,
+			wantErrors: []string{"unsupported order type: iceberg"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_ExpiresAt(t *testing.T) {
+	issued := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	later := issued.Add(24 * time.Hour)
+	earlier := issued.Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		wantValid bool
+	}{
+		{name: "No expiry", wantValid: true},
+		{name: "Future expiry", expiresAt: &later, wantValid: true},
+		{name: "Past expiry", expiresAt: &earlier, wantValid: false},
+		{name: "Expires when issued", expiresAt: &issued, wantValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				EntryPrice:  float64Ptr(45000),
+				StopLoss:    float64Ptr(44500),
+				RiskPercent: float64Ptr(1),
+				ExpiresAt:   tt.expiresAt,
+				Timestamp:   issued,
+			}
+			ValidateCommand(cmd)
+
+			if cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v (errors: %v)", cmd.Valid, tt.wantValid, cmd.Errors)
+			}
+			if !tt.wantValid && !equalStrings(cmd.Errors, []string{"expires_at must be in the future"}) {
+				t.Errorf("Errors = %v", cmd.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_ExpiresAtReferenceTime(t *testing.T) {
+	reference := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	expires := reference.Add(time.Hour)
+	newCmd := func(timestamp time.Time) *intent.NormalizedCommand {
+		return &intent.NormalizedCommand{
+			Intent:      intent.IntentOpenPosition,
+			Symbol:      "BTC-USDT",
+			Side:        sidePtr(types.SideLong),
+			EntryPrice:  float64Ptr(45000),
+			StopLoss:    float64Ptr(44500),
+			RiskPercent: float64Ptr(1),
+			ExpiresAt:   &expires,
+			Timestamp:   timestamp,
+		}
+	}
+
+	// Parsed today against a reference time in the past, e.g. a backtest
+	cmd := newCmd(reference.AddDate(1, 0, 0))
+	ValidateCommand(cmd, WithReferenceTime(reference))
+	if !cmd.Valid {
+		t.Errorf("with reference time: errors = %v, want valid", cmd.Errors)
+	}
+
+	// Without a timestamp the clock gives the current time
+	cmd = newCmd(time.Time{})
+	ValidateCommand(cmd, WithClock(intent.NewStepClock(reference, 0)))
+	if !cmd.Valid {
+		t.Errorf("with clock: errors = %v, want valid", cmd.Errors)
+	}
+	cmd = newCmd(time.Time{})
+	ValidateCommand(cmd, WithClock(intent.NewStepClock(expires, 0)))
+	if cmd.Valid {
+		t.Error("with clock at the expiry: valid, want expired")
+	}
+}
+
+func TestValidateCommand_SLLevels(t *testing.T) {
+	tests := []struct {
+		name       string
+		side       types.Side
+		stopLoss   *float64
+		levels     []intent.SLLevel
+		wantValid  bool
+		wantErrors []string
+	}{
+		{
+			name:      "Scaled stops for LONG",
+			side:      types.SideLong,
+			levels:    []intent.SLLevel{{Price: 44500, Percentage: 50}, {Price: 44000, Percentage: 50}},
+			wantValid: true,
+		},
+		{
+			name:      "Scaled stops for SHORT",
+			side:      types.SideShort,
+			levels:    []intent.SLLevel{{Price: 45500, Percentage: 50}, {Price: 46000, Percentage: 50}},
+			wantValid: true,
+		},
+		{
+			name:       "Percentages over 100",
+			side:       types.SideLong,
+			levels:     []intent.SLLevel{{Price: 44500, Percentage: 60}, {Price: 44000, Percentage: 50}},
+			wantValid:  false,
+			wantErrors: []string{"SL percentages sum to 110.0%, cannot exceed 100%"},
+		},
+		{
+			name:       "Out of order for LONG",
+			side:       types.SideLong,
+			levels:     []intent.SLLevel{{Price: 44000, Percentage: 50}, {Price: 44500, Percentage: 50}},
+			wantValid:  false,
+			wantErrors: []string{"sl_levels must be below entry_price and descending for LONG"},
+		},
+		{
+			name:       "Above entry for LONG",
+			side:       types.SideLong,
+			levels:     []intent.SLLevel{{Price: 45500, Percentage: 100}},
+			wantValid:  false,
+			wantErrors: []string{"sl_levels must be below entry_price and descending for LONG"},
+		},
+		{
+			name:       "Below entry for SHORT",
+			side:       types.SideShort,
+			levels:     []intent.SLLevel{{Price: 44500, Percentage: 100}},
+			wantValid:  false,
+			wantErrors: []string{"sl_levels must be above entry_price and ascending for SHORT"},
+		},
+		{
+			name:       "Together with a single stop",
+			side:       types.SideLong,
+			stopLoss:   float64Ptr(44500),
+			levels:     []intent.SLLevel{{Price: 44000, Percentage: 100}},
+			wantValid:  false,
+			wantErrors: []string{"stop_loss and sl_levels are mutually exclusive"},
+		},
+		{
+			name:       "Zero percentage",
+			side:       types.SideLong,
+			levels:     []intent.SLLevel{{Price: 44000, Percentage: 0}},
+			wantValid:  false,
+			wantErrors: []string{"sl_levels[0].percentage must be greater than 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(tt.side),
+				EntryPrice:  float64Ptr(45000),
+				StopLoss:    tt.stopLoss,
+				SLLevels:    tt.levels,
+				RiskPercent: float64Ptr(1),
+			}
+			ValidateCommand(cmd)
+
+			if cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v (missing: %v, errors: %v)", cmd.Valid, tt.wantValid, cmd.Missing, cmd.Errors)
+			}
+			if tt.wantErrors != nil && !equalStrings(cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_Timeframe(t *testing.T) {
+	cmd := &intent.NormalizedCommand{Intent: intent.IntentViewPrice, Symbol: "BTC-USDT", Timeframe: "4h"}
+	ValidateCommand(cmd)
+	if !cmd.Valid {
+		t.Errorf("Valid = false, errors %v", cmd.Errors)
+	}
+
+	cmd = &intent.NormalizedCommand{Intent: intent.IntentViewPrice, Symbol: "BTC-USDT", Timeframe: "7m"}
+	ValidateCommand(cmd)
+	if cmd.Valid || !equalStrings(cmd.Errors, []string{"unsupported timeframe: 7m"}) {
+		t.Errorf("Valid = %v, Errors = %v, want unsupported timeframe", cmd.Valid, cmd.Errors)
+	}
+}
+
+func TestValidateCommand_SetRiskDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name: "Valid default risk",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentSetRiskDefaults,
+				RiskPercent: float64Ptr(1.0),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Missing risk",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentSetRiskDefaults,
+			},
+			wantValid:   false,
+			wantMissing: []string{"risk_percent"},
+		},
+		{
+			name: "Risk out of range",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentSetRiskDefaults,
+				RiskPercent: float64Ptr(120.0),
+			},
+			wantValid:  false,
+			wantErrors: []string{"risk_percent must be between 0 and 100"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+			if !tt.cmd.ConfirmationRequired {
+				t.Error("ConfirmationRequired = false, want true")
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_Withdraw(t *testing.T) {
+	allow := Policy{AllowWithdrawals: true, AddressWhitelisted: whitelist("ledger", "cold wallet")}
+
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		policy      Policy
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name: "Disabled by default policy",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentWithdraw,
+				Asset:      "USDT",
+				Amount:     float64Ptr(500),
+				AddressRef: "ledger",
+			},
+			policy:     DefaultPolicy(),
+			wantValid:  false,
+			wantErrors: []string{"withdrawals are disabled by policy"},
+		},
+		{
+			name: "Valid when enabled",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentWithdraw,
+				Asset:      "USDT",
+				Amount:     float64Ptr(500),
+				AddressRef: "ledger",
+			},
+			policy:    allow,
+			wantValid: true,
+		},
+		{
+			name: "Missing fields",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentWithdraw,
+			},
+			policy:      allow,
+			wantValid:   false,
+			wantMissing: []string{"asset", "amount", "address_ref"},
+		},
+		{
+			name: "Raw EVM address rejected",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentWithdraw,
+				Asset:      "ETH",
+				Amount:     float64Ptr(1),
+				AddressRef: "0x71C7656EC7ab88b098defB751B7401B5f6d8976F",
+			},
+			policy:     allow,
+			wantValid:  false,
+			wantErrors: []string{"address_ref must name a whitelisted address, not a raw address"},
+		},
+		{
+			name: "Raw BTC address rejected",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentWithdraw,
+				Asset:      "BTC",
+				Amount:     float64Ptr(0.1),
+				AddressRef: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq",
+			},
+			policy:     allow,
+			wantValid:  false,
+			wantErrors: []string{"address_ref must name a whitelisted address, not a raw address"},
+		},
+		{
+			name: "Unlisted address rejected",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentWithdraw,
+				Asset:      "USDT",
+				Amount:     float64Ptr(500),
+				AddressRef: "my friend",
+			},
+			policy:     allow,
+			wantValid:  false,
+			wantErrors: []string{"address my friend is not whitelisted"},
+		},
+		{
+			name: "No whitelist rejects every address",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentWithdraw,
+				Asset:      "USDT",
+				Amount:     float64Ptr(500),
+				AddressRef: "ledger",
+			},
+			policy:     Policy{AllowWithdrawals: true},
+			wantValid:  false,
+			wantErrors: []string{"address ledger is not whitelisted"},
+		},
+		{
+			name: "Non-positive amount",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentWithdraw,
+				Asset:      "USDT",
+				Amount:     float64Ptr(-5),
+				AddressRef: "cold wallet",
+			},
+			policy:     allow,
+			wantValid:  false,
+			wantErrors: []string{"amount must be greater than 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommandWithPolicy(tt.cmd, tt.policy)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+			if !tt.cmd.ConfirmationRequired {
+				t.Error("ConfirmationRequired = false, want true")
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+// whitelist returns an AddressWhitelisted hook accepting refs
+func whitelist(refs ...string) func(string) bool {
+	return func(ref string) bool {
+		return slices.Contains(refs, ref)
+	}
+}
+
+func TestValidateCommand_CrossSymbolCondition(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name: "Condition on a different symbol",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentClosePosition,
+				Symbol: "ETH-USDT",
+				Condition: &intent.PriceCondition{
+					Symbol:   "ETH-BTC",
+					Operator: intent.ConditionAbove,
+					Price:    float64Ptr(0.06),
+				},
+			},
+			wantValid: true,
+		},
+		{
+			name: "Condition defaults to command symbol",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentClosePosition,
+				Symbol: "BTC-USDT",
+				Condition: &intent.PriceCondition{
+					Operator: intent.ConditionBelow,
+					Price:    float64Ptr(40000),
+				},
+			},
+			wantValid: true,
+		},
+		{
+			name: "Action symbol unresolved",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentViewPositions,
+				Condition: &intent.PriceCondition{
+					Symbol:   "ETH-BTC",
+					Operator: intent.ConditionAbove,
+					Price:    float64Ptr(0.06),
+				},
+			},
+			wantValid:   false,
+			wantMissing: []string{"symbol"},
+		},
+		{
+			name: "Neither symbol resolved",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentClosePosition,
+				Condition: &intent.PriceCondition{
+					Operator: intent.ConditionAbove,
+					Price:    float64Ptr(0.06),
+				},
+			},
+			wantValid:   false,
+			wantMissing: []string{"symbol", "condition_symbol"},
+		},
+		{
+			name: "Conditional open without operator",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				OrderType:   intent.OrderTypeMarket,
+				StopLoss:    float64Ptr(48000),
+				RiskPercent: float64Ptr(1),
+				Condition: &intent.PriceCondition{
+					Price: float64Ptr(50000),
+				},
+			},
+			wantValid:   false,
+			wantMissing: []string{"condition_operator"},
+		},
+		{
+			name: "Conditional close without price",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentClosePosition,
+				Symbol: "BTC-USDT",
+				Condition: &intent.PriceCondition{
+					Operator: intent.ConditionBelow,
+				},
+			},
+			wantValid:   false,
+			wantMissing: []string{"condition_price"},
+		},
+		{
+			name: "Invalid condition price",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentClosePosition,
+				Symbol: "ETH-USDT",
+				Condition: &intent.PriceCondition{
+					Symbol: "ETH-BTC",
+					Price:  float64Ptr(0),
+				},
+			},
+			wantValid:  false,
+			wantErrors: []string{"condition_price must be greater than 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_ModifyPosition(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name: "New stop loss",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentModifyPosition,
+				Symbol:   "BTC-USDT",
+				StopLoss: float64Ptr(44000),
+			},
+			wantValid: true,
+		},
+		{
+			name: "New take profit",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentModifyPosition,
+				Symbol:     "ETH-USDT",
+				TakeProfit: float64Ptr(3500),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Missing symbol",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentModifyPosition,
+				StopLoss: float64Ptr(44000),
+			},
+			wantValid:   false,
+			wantMissing: []string{"symbol"},
+		},
+		{
+			name: "Nothing to modify",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentModifyPosition,
+				Symbol: "BTC-USDT",
+			},
+			wantValid:   false,
+			wantMissing: []string{"stop_loss or take_profit"},
+		},
+		{
+			name: "Non-positive stop loss",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentModifyPosition,
+				Symbol:   "BTC-USDT",
+				StopLoss: float64Ptr(0),
+			},
+			wantValid:  false,
+			wantErrors: []string{"stop_loss must be greater than 0"},
+		},
+		{
+			name: "Stop loss on the wrong side",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentModifyPosition,
+				Symbol:     "BTC-USDT",
+				Side:       sidePtr(types.SideLong),
+				EntryPrice: float64Ptr(45000),
+				StopLoss:   float64Ptr(46000),
+			},
+			wantValid:  false,
+			wantErrors: []string{"stop_loss must be below entry_price for LONG"},
+		},
+		{
+			name: "Take profit on the wrong side",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentModifyPosition,
+				Symbol:     "BTC-USDT",
+				Side:       sidePtr(types.SideShort),
+				EntryPrice: float64Ptr(45000),
+				TakeProfit: float64Ptr(47000),
+			},
+			wantValid:  false,
+			wantErrors: []string{"take_profit must be below entry_price for SHORT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_SetLeverage(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name: "Valid leverage",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentSetLeverage,
+				Symbol:   "BTC-USDT",
+				Leverage: float64Ptr(10),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Bounds are inclusive",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentSetLeverage,
+				Symbol:   "BTC-USDT",
+				Leverage: float64Ptr(125),
+			},
+			wantValid: true,
+		},
+		{
+			name: "Missing fields",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentSetLeverage,
+			},
+			wantValid:   false,
+			wantMissing: []string{"symbol", "leverage"},
+		},
+		{
+			name: "Above maximum",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentSetLeverage,
+				Symbol:   "BTC-USDT",
+				Leverage: float64Ptr(200),
+			},
+			wantValid:  false,
+			wantErrors: []string{"leverage must be between 1x and 125x"},
+		},
+		{
+			name: "Below minimum",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentSetLeverage,
+				Symbol:   "BTC-USDT",
+				Leverage: float64Ptr(0.5),
+			},
+			wantValid:  false,
+			wantErrors: []string{"leverage must be between 1x and 125x"},
+		},
+		{
+			name: "Out of range on open_position",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				EntryPrice:  float64Ptr(45000),
+				StopLoss:    float64Ptr(44500),
+				RiskPercent: float64Ptr(1),
+				Leverage:    float64Ptr(500),
+			},
+			wantValid:  false,
+			wantErrors: []string{"leverage must be between 1x and 125x"},
+		},
+		{
+			name: "Out of range on any intent",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentClosePosition,
+				Symbol:   "BTC-USDT",
+				Leverage: float64Ptr(500),
+			},
+			wantValid:  false,
+			wantErrors: []string{"leverage must be between 1x and 125x"},
+		},
+		{
+			name: "NaN",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentSetLeverage,
+				Symbol:   "BTC-USDT",
+				Leverage: float64Ptr(math.NaN()),
+			},
+			wantValid:  false,
+			wantErrors: []string{"leverage must be a finite number"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_DCAOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name: "Valid ladder",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentDCAOrder,
+				Symbol: "BTC-USDT",
+				EntryLevels: []intent.EntryLevel{
+					{Price: 44000, Percentage: 33.33},
+					{Price: 43500, Percentage: 33.33},
+					{Price: 43000, Percentage: 33.34},
+				},
+			},
+			wantValid: true,
+		},
+		{
+			name: "Missing fields",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentDCAOrder,
+			},
+			wantValid:   false,
+			wantMissing: []string{"symbol", "entry_levels"},
+		},
+		{
+			name: "Single level",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentDCAOrder,
+				Symbol:      "BTC-USDT",
+				EntryLevels: []intent.EntryLevel{{Price: 44000, Percentage: 100}},
+			},
+			wantValid:  false,
+			wantErrors: []string{"a DCA ladder needs at least 2 entry levels"},
+		},
+		{
+			name: "Percentages below 100",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentDCAOrder,
+				Symbol: "BTC-USDT",
+				EntryLevels: []intent.EntryLevel{
+					{Price: 44000, Percentage: 50},
+					{Price: 43000, Percentage: 30},
+				},
+			},
+			wantValid:  false,
+			wantErrors: []string{"entry level percentages sum to 80.0%, must be 100%"},
+		},
+		{
+			name: "Non-positive price",
+			cmd: &intent.NormalizedCommand{
+				Intent: intent.IntentDCAOrder,
+				Symbol: "BTC-USDT",
+				EntryLevels: []intent.EntryLevel{
+					{Price: 0, Percentage: 50},
+					{Price: 43000, Percentage: 50},
+				},
+			},
+			wantValid:  false,
+			wantErrors: []string{"entry_levels[0].price must be greater than 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestShadow(t *testing.T) {
+	cmd := &intent.NormalizedCommand{
+		Intent:     intent.IntentWithdraw,
+		Asset:      "USDT",
+		Amount:     float64Ptr(100),
+		AddressRef: "ledger",
+	}
+	allow := Policy{AllowWithdrawals: true, AddressWhitelisted: whitelist("ledger")}
+	ValidateCommandWithPolicy(cmd, allow)
+	active := OutcomeOf(cmd)
+
+	shadow := Shadow(cmd, Policy{})
+	if active.Equal(shadow) {
+		t.Fatal("outcomes equal, want the stricter shadow policy to reject the withdrawal")
+	}
+	if shadow.Valid || !equalStrings(shadow.Errors, []string{"withdrawals are disabled by policy"}) {
+		t.Errorf("shadow = %+v, want invalid with the policy error", shadow)
+	}
+	if !cmd.Valid || len(cmd.Errors) != 0 {
+		t.Errorf("Shadow modified the command: Valid = %v, Errors = %v", cmd.Valid, cmd.Errors)
+	}
+
+	if same := Shadow(cmd, allow); !active.Equal(same) {
+		t.Errorf("same policy outcome = %+v, want %+v", same, active)
+	}
+}
+
+func TestValidateCommand_Tolerance(t *testing.T) {
+	// 16.1 + 48.7 + 35.2 == 100.00000000000001 in float64
+	tpLevels := []types.TPLevel{
+		{Price: 46000, Percentage: 16.1},
+		{Price: 47000, Percentage: 48.7},
+		{Price: 48000, Percentage: 35.2},
+	}
+
+	tests := []struct {
+		name       string
+		cmd        *intent.NormalizedCommand
+		policy     Policy
+		wantValid  bool
+		wantErrors []string
+	}{
+		{
+			name: "TP sum with float error passes default tolerance",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				EntryPrice:  float64Ptr(45000),
+				StopLoss:    float64Ptr(44500),
+				RiskPercent: float64Ptr(2),
+				TPLevels:    tpLevels,
+			},
+			policy:    DefaultPolicy(),
+			wantValid: true,
+		},
+		{
+			name: "TP sum with float error fails exact comparison",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				EntryPrice:  float64Ptr(45000),
+				StopLoss:    float64Ptr(44500),
+				RiskPercent: float64Ptr(2),
+				TPLevels:    tpLevels,
+			},
+			policy:     Policy{},
+			wantValid:  false,
+			wantErrors: []string{"TP percentages sum to 100.0%, cannot exceed 100%"},
+		},
+		{
+			name: "Stop loss within one tick of entry is rejected",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideLong),
+				EntryPrice:  float64Ptr(45000),
+				StopLoss:    float64Ptr(44999.95),
+				RiskPercent: float64Ptr(2),
+			},
+			policy:     Policy{Tolerance: 0.1},
+			wantValid:  false,
+			wantErrors: []string{"stop_loss must be below entry_price for LONG"},
+		},
+		{
+			name: "Stop loss beyond one tick of entry is accepted",
+			cmd: &intent.NormalizedCommand{
+				Intent:      intent.IntentOpenPosition,
+				Symbol:      "BTC-USDT",
+				Side:        sidePtr(types.SideShort),
+				EntryPrice:  float64Ptr(45000),
+				StopLoss:    float64Ptr(45000.2),
+				RiskPercent: float64Ptr(2),
+			},
+			policy:    Policy{Tolerance: 0.1},
+			wantValid: true,
+		},
+		{
+			name: "Grid bounds within tolerance are equal",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentSetupGrid,
+				Symbol:     "ETH-USDT",
+				GridLower:  float64Ptr(3000),
+				GridUpper:  float64Ptr(3000.005),
+				GridLevels: intPtr(10),
+			},
+			policy:     Policy{Tolerance: 0.01},
+			wantValid:  false,
+			wantErrors: []string{"grid_lower must be below grid_upper"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommandWithPolicy(tt.cmd, tt.policy)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v (errors: %v)", tt.cmd.Valid, tt.wantValid, tt.cmd.Errors)
+			}
+			if tt.wantErrors != nil && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommandWithPolicy_AllowedExchanges(t *testing.T) {
+	policy := DefaultPolicy()
+	policy.AllowedExchanges = []string{"binance", "bybit"}
+
+	tests := []struct {
+		name       string
+		exchange   string
+		policy     Policy
+		wantValid  bool
+		wantErrors []string
+	}{
+		{name: "No exchange", exchange: "", policy: policy, wantValid: true},
+		{name: "Allowed", exchange: "bybit", policy: policy, wantValid: true},
+		{name: "Any exchange without a list", exchange: "kraken", policy: DefaultPolicy(), wantValid: true},
+		{
+			name:       "Not allowed",
+			exchange:   "kraken",
+			policy:     policy,
+			wantValid:  false,
+			wantErrors: []string{"exchange kraken is not allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &intent.NormalizedCommand{
+				Intent:   intent.IntentViewPositions,
+				Exchange: tt.exchange,
+			}
+			ValidateCommandWithPolicy(cmd, tt.policy)
+
+			if cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v (errors: %v)", cmd.Valid, tt.wantValid, cmd.Errors)
+			}
+			if tt.wantErrors != nil && !equalStrings(cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommandWithPolicy_AccountExists(t *testing.T) {
+	policy := DefaultPolicy()
+	policy.AccountExists = func(account string) bool { return account == "scalping" }
+
+	tests := []struct {
+		name       string
+		account    string
+		policy     Policy
+		wantValid  bool
+		wantErrors []string
+	}{
+		{name: "Default account", account: "", policy: policy, wantValid: true},
+		{name: "Known account", account: "scalping", policy: policy, wantValid: true},
+		{name: "No hook", account: "swing", policy: DefaultPolicy(), wantValid: true},
+		{
+			name:       "Unknown account",
+			account:    "swing",
+			policy:     policy,
+			wantValid:  false,
+			wantErrors: []string{"account swing not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &intent.NormalizedCommand{
+				Intent:  intent.IntentClosePosition,
+				Symbol:  "BTC-USDT",
+				Account: tt.account,
+			}
+			ValidateCommandWithPolicy(cmd, tt.policy)
+
+			if cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v (errors: %v)", cmd.Valid, tt.wantValid, cmd.Errors)
+			}
+			if tt.wantErrors != nil && !equalStrings(cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_CancelOrders(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name:      "Single order",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentCancelOrders, OrderID: "12345"},
+			wantValid: true,
+		},
+		{
+			name:      "All orders",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentCancelOrders, CancelAll: true},
+			wantValid: true,
+		},
+		{
+			name:      "All orders on a symbol",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentCancelOrders, CancelAll: true, Symbol: "BTC-USDT"},
+			wantValid: true,
+		},
+		{
+			name:        "Neither order nor all",
+			cmd:         &intent.NormalizedCommand{Intent: intent.IntentCancelOrders},
+			wantValid:   false,
+			wantMissing: []string{"order_id"},
+		},
+		{
+			name:       "Both order and all",
+			cmd:        &intent.NormalizedCommand{Intent: intent.IntentCancelOrders, OrderID: "12345", CancelAll: true},
+			wantValid:  false,
+			wantErrors: []string{"order_id and cancel_all are mutually exclusive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_CheckOrderStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name:      "By order ID",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentCheckOrderStatus, OrderID: "12345"},
+			wantValid: true,
+		},
+		{
+			name:      "Latest order",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentCheckOrderStatus, LatestOrder: true},
+			wantValid: true,
+		},
+		{
+			name:      "Latest order on a symbol",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentCheckOrderStatus, Symbol: "BTC-USDT"},
+			wantValid: true,
+		},
+		{
+			name:        "No selector",
+			cmd:         &intent.NormalizedCommand{Intent: intent.IntentCheckOrderStatus},
+			wantValid:   false,
+			wantMissing: []string{"order_id or symbol"},
+		},
+		{
+			name:       "Order ID and latest",
+			cmd:        &intent.NormalizedCommand{Intent: intent.IntentCheckOrderStatus, OrderID: "12345", LatestOrder: true},
+			wantValid:  false,
+			wantErrors: []string{"order_id and latest_order are mutually exclusive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_ViewPosition(t *testing.T) {
+	cmd := &intent.NormalizedCommand{Intent: intent.IntentViewPosition, Symbol: "BTC-USDT"}
+	ValidateCommand(cmd)
+	if !cmd.Valid {
+		t.Errorf("Valid = false, errors %v, missing %v", cmd.Errors, cmd.Missing)
+	}
+
+	cmd = &intent.NormalizedCommand{Intent: intent.IntentViewPosition}
+	ValidateCommand(cmd)
+	if cmd.Valid || !equalStrings(cmd.Missing, []string{"symbol"}) {
+		t.Errorf("Valid = %v, Missing = %v, want invalid with symbol missing", cmd.Valid, cmd.Missing)
+	}
+}
+
+func TestValidateCommand_PanicClose(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmd        *intent.NormalizedCommand
+		wantValid  bool
+		wantErrors []string
+	}{
+		{
+			name:      "Everything",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentPanicClose},
+			wantValid: true,
+		},
+		{
+			name:      "One exchange",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentPanicClose, Exchange: "binance"},
+			wantValid: true,
+		},
+		{
+			name:       "Single symbol",
+			cmd:        &intent.NormalizedCommand{Intent: intent.IntentPanicClose, Symbol: "BTC-USDT"},
+			wantValid:  false,
+			wantErrors: []string{"panic_close closes every position, use close_position for BTC-USDT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+			if !tt.cmd.ConfirmationRequired {
+				t.Error("ConfirmationRequired = false, want true")
+			}
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_CloseAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmd        *intent.NormalizedCommand
+		wantValid  bool
+		wantErrors []string
+	}{
+		{
+			name:      "Everything",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentCloseAll},
+			wantValid: true,
+		},
+		{
+			name:      "With exclusions",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentCloseAll, ExcludeSymbols: []string{"ETH-USDT", "SOL-USDT"}},
+			wantValid: true,
+		},
+		{
+			name:       "Single symbol",
+			cmd:        &intent.NormalizedCommand{Intent: intent.IntentCloseAll, Symbol: "BTC-USDT"},
+			wantValid:  false,
+			wantErrors: []string{"close_all closes every position, use close_position for BTC-USDT"},
+		},
+		{
+			name:       "Duplicate exclusion",
+			cmd:        &intent.NormalizedCommand{Intent: intent.IntentCloseAll, ExcludeSymbols: []string{"ETH-USDT", "ETH-USDT"}},
+			wantValid:  false,
+			wantErrors: []string{"ETH-USDT is excluded twice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+			if !tt.cmd.ConfirmationRequired {
+				t.Error("ConfirmationRequired = false, want true")
+			}
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_SetTakeProfit(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name:      "Single target",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentSetTakeProfit, Symbol: "ETH-USDT", TakeProfit: float64Ptr(3200)},
+			wantValid: true,
+		},
+		{
+			name: "Ladder for a LONG",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentSetTakeProfit,
+				Symbol:   "ETH-USDT",
+				Side:     sidePtr(types.SideLong),
+				TPLevels: []intent.TPLevel{{Price: 3200, Percentage: 50}, {Price: 3400, Percentage: 50}},
+			},
+			wantValid: true,
+		},
+		{
+			name:        "Missing target",
+			cmd:         &intent.NormalizedCommand{Intent: intent.IntentSetTakeProfit},
+			wantValid:   false,
+			wantMissing: []string{"symbol", "take_profit or tp_levels"},
+		},
+		{
+			name: "Target and ladder",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentSetTakeProfit,
+				Symbol:     "ETH-USDT",
+				TakeProfit: float64Ptr(3200),
+				TPLevels:   []intent.TPLevel{{Price: 3400, Percentage: 100}},
+			},
+			wantValid:  false,
+			wantErrors: []string{"take_profit and tp_levels are mutually exclusive"},
+		},
+		{
+			name: "Target below entry for a LONG",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentSetTakeProfit,
+				Symbol:     "ETH-USDT",
+				Side:       sidePtr(types.SideLong),
+				EntryPrice: float64Ptr(3000),
+				TakeProfit: float64Ptr(2900),
+			},
+			wantValid:  false,
+			wantErrors: []string{"take_profit must be above entry_price for LONG"},
+		},
+		{
+			name: "Ascending ladder for a SHORT",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentSetTakeProfit,
+				Symbol:   "ETH-USDT",
+				Side:     sidePtr(types.SideShort),
+				TPLevels: []intent.TPLevel{{Price: 2800, Percentage: 50}, {Price: 2900, Percentage: 50}},
+			},
+			wantValid:  false,
+			wantErrors: []string{"tp_levels must be below entry_price and descending for SHORT"},
+		},
+		{
+			name: "Ladder over 100%",
+			cmd: &intent.NormalizedCommand{
+				Intent:   intent.IntentSetTakeProfit,
+				Symbol:   "ETH-USDT",
+				TPLevels: []intent.TPLevel{{Price: 3200, Percentage: 60}, {Price: 3400, Percentage: 60}},
+			},
+			wantValid:  false,
+			wantErrors: []string{"TP percentages sum to 120.0%, cannot exceed 100%"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_SetStopLoss(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         *intent.NormalizedCommand
+		wantValid   bool
+		wantMissing []string
+		wantErrors  []string
+	}{
+		{
+			name:      "Stop on a symbol",
+			cmd:       &intent.NormalizedCommand{Intent: intent.IntentSetStopLoss, Symbol: "BTC-USDT", StopLoss: float64Ptr(44000)},
+			wantValid: true,
+		},
+		{
+			name:        "Missing stop",
+			cmd:         &intent.NormalizedCommand{Intent: intent.IntentSetStopLoss, Symbol: "BTC-USDT"},
+			wantValid:   false,
+			wantMissing: []string{"stop_loss"},
+		},
+		{
+			name: "Stop above entry for a LONG",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentSetStopLoss,
+				Symbol:     "BTC-USDT",
+				Side:       sidePtr(types.SideLong),
+				EntryPrice: float64Ptr(45000),
+				StopLoss:   float64Ptr(46000),
+			},
+			wantValid:  false,
+			wantErrors: []string{"stop_loss must be below entry_price for LONG"},
+		},
+		{
+			name: "Stop above entry for a SHORT",
+			cmd: &intent.NormalizedCommand{
+				Intent:     intent.IntentSetStopLoss,
+				Symbol:     "BTC-USDT",
+				Side:       sidePtr(types.SideShort),
+				EntryPrice: float64Ptr(45000),
+				StopLoss:   float64Ptr(46000),
+			},
+			wantValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd)
+
+			if tt.cmd.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", tt.cmd.Valid, tt.wantValid)
+			}
+
+			if len(tt.wantMissing) > 0 && !equalStrings(tt.cmd.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", tt.cmd.Missing, tt.wantMissing)
+			}
+
+			if len(tt.wantErrors) > 0 && !equalStrings(tt.cmd.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", tt.cmd.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_ViewIntents(t *testing.T) {
+	// View intents don't require validation
+	intents := []intent.Intent{
+		intent.IntentViewPositions,
+		intent.IntentViewOrders,
+		intent.IntentCheckBalance,
+		intent.IntentViewAlerts,
+		intent.IntentHelp,
+	}
+
+	for _, intentType := range intents {
+		t.Run(string(intentType), func(t *testing.T) {
+			cmd := &intent.NormalizedCommand{
+				Intent: intentType,
+			}
+
+			ValidateCommand(cmd)
+
+			if !cmd.Valid {
+				t.Errorf("Valid = false, want true for %s", intentType)
+			}
+		})
+	}
+}
+
+func TestValidateCommand_UnknownIntent(t *testing.T) {
+	cmd := &intent.NormalizedCommand{
+		Intent: intent.IntentUnknown,
+	}
+
+	ValidateCommand(cmd)
+
+	if cmd.Valid {
+		t.Error("Valid = true, want false for unknown intent")
+	}
+	if len(cmd.Errors) == 0 {
+		t.Error("Expected error for unknown intent")
+	}
+}
+
+func TestValidateCommand_NamespacedIntent(t *testing.T) {
+	transfer := intent.Namespaced("validators-test", "fund_transfer")
+	intent.RegisterIntent(intent.IntentSpec{
+		Intent: transfer,
+		Validate: func(cmd *intent.NormalizedCommand) {
+			if cmd.Amount == nil {
+				cmd.Missing = append(cmd.Missing, "amount")
+				cmd.Valid = false
+			}
+		},
+	})
+
+	cmd := &intent.NormalizedCommand{Intent: transfer}
+	ValidateCommand(cmd)
+	if cmd.Valid || !equalStrings(cmd.Missing, []string{"amount"}) {
+		t.Errorf("Valid = %v, Missing = %v, want the registered validator to require amount", cmd.Valid, cmd.Missing)
+	}
+
+	cmd = &intent.NormalizedCommand{Intent: transfer, Amount: float64Ptr(100)}
+	ValidateCommand(cmd)
+	if !cmd.Valid {
+		t.Errorf("Valid = false, Errors = %v", cmd.Errors)
+	}
+
+	// Unregistered namespaced intents are as unknown as any other name
+	cmd = &intent.NormalizedCommand{Intent: intent.Namespaced("validators-test", "missing")}
+	ValidateCommand(cmd)
+	if cmd.Valid {
+		t.Error("Valid = true for an unregistered namespaced intent")
+	}
+}
+
+func TestValidateCommand_Issues(t *testing.T) {
+	cmd := &intent.NormalizedCommand{
+		Intent:     intent.IntentOpenPosition,
+		Symbol:     "BTC-USDT",
+		Side:       sidePtr(types.SideLong),
+		EntryPrice: float64Ptr(45000),
+		StopLoss:   float64Ptr(46000),
+		Leverage:   float64Ptr(500),
+		Quantity:   float64Ptr(-1),
+	}
+
+	ValidateCommand(cmd)
+
+	want := []intent.ValidationIssue{
+		{Code: intent.IssueCodeNotPositive, Field: "quantity", Message: "quantity must be greater than 0", Severity: intent.SeverityError},
+		{Code: intent.IssueCodeOutOfRange, Field: "leverage", Message: "leverage must be between 1x and 125x", Severity: intent.SeverityError},
+		{Code: intent.IssueCodePriceSide, Field: "stop_loss", Message: "stop_loss must be below entry_price for LONG", Severity: intent.SeverityError},
+	}
+	if len(cmd.Issues) != len(want) {
+		t.Fatalf("Issues = %+v, want %+v", cmd.Issues, want)
+	}
+	for i := range want {
+		if cmd.Issues[i] != want[i] {
+			t.Errorf("Issues[%d] = %+v, want %+v", i, cmd.Issues[i], want[i])
+		}
+	}
+	if len(cmd.Errors) != len(want) {
+		t.Errorf("Errors = %v, want one per issue", cmd.Errors)
+	}
+
+	cmd = &intent.NormalizedCommand{Intent: intent.IntentSetLeverage, Leverage: float64Ptr(10)}
+	ValidateCommand(cmd)
+	if len(cmd.Issues) != 1 || cmd.Issues[0].Code != intent.IssueCodeMissing || cmd.Issues[0].Field != "symbol" {
+		t.Errorf("Issues = %+v, want symbol missing", cmd.Issues)
+	}
+}
+
+func TestValidateCommandWithPolicy_Warnings(t *testing.T) {
+	policy := DefaultPolicy()
+	policy.MaxTakeProfitDistance = 0.5
+	policy.UsualRiskPercent = func(userID string) float64 {
+		if userID == "alice" {
+			return 1
+		}
+		return 0
+	}
+	open := func(userID string, risk, tp float64) *intent.NormalizedCommand {
+		return &intent.NormalizedCommand{
+			Intent:      intent.IntentOpenPosition,
+			UserID:      userID,
+			Symbol:      "BTC-USDT",
+			Side:        sidePtr(types.SideLong),
+			EntryPrice:  float64Ptr(4500),
+			StopLoss:    float64Ptr(4400),
+			RiskPercent: float64Ptr(risk),
+			TakeProfit:  float64Ptr(tp),
+		}
+	}
+
+	tests := []struct {
+		name string
+		cmd  *intent.NormalizedCommand
+		want []string
+	}{
+		{"Usual", open("alice", 1, 4700), nil},
+		{"Far take profit", open("alice", 1, 47000), []string{"take_profit is more than 50% away from entry_price"}},
+		{"Risk above usual", open("alice", 3, 4700), []string{"risk_percent 3% is above your usual 1%"}},
+		{"Unknown usual risk", open("bob", 3, 4700), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommandWithPolicy(tt.cmd, policy)
+			if !tt.cmd.Valid {
+				t.Errorf("Valid = false, Errors = %v, want warnings to keep the command valid", tt.cmd.Errors)
+			}
+			if !equalStrings(tt.cmd.Warnings, tt.want) {
+				t.Errorf("Warnings = %v, want %v", tt.cmd.Warnings, tt.want)
+			}
+			if tt.cmd.ConfirmationRequired != (len(tt.want) > 0) {
+				t.Errorf("ConfirmationRequired = %v with warnings %v", tt.cmd.ConfirmationRequired, tt.cmd.Warnings)
+			}
+		})
+	}
+
+	// Warnings are off by default, and shadowing a policy that adds them
+	// reports a different outcome
+	cmd := open("alice", 3, 47000)
+	ValidateCommand(cmd)
+	if len(cmd.Warnings) != 0 {
+		t.Errorf("Warnings = %v with DefaultPolicy, want none", cmd.Warnings)
+	}
+	if OutcomeOf(cmd).Equal(Shadow(cmd, policy)) {
+		t.Error("Shadow outcome equals the active one, want the warnings to differ")
+	}
+}
+
+func TestValidateCommand_WithRules(t *testing.T) {
+	maxNotional := func(cmd *intent.NormalizedCommand) []intent.ValidationIssue {
+		if cmd.NotionalUSD != nil && *cmd.NotionalUSD > 50000 {
+			return []intent.ValidationIssue{{Code: "max_notional", Field: "notional_usd", Message: "notional_usd exceeds the desk limit of 50000"}}
+		}
+		return nil
+	}
+	banned := func(cmd *intent.NormalizedCommand) []intent.ValidationIssue {
+		if cmd.Symbol == "LUNA-USDT" {
+			return []intent.ValidationIssue{{Code: "banned_symbol", Field: "symbol", Message: "LUNA-USDT is not tradable"}}
+		}
+		return nil
+	}
+	open := func(symbol string, notional float64) *intent.NormalizedCommand {
+		return &intent.NormalizedCommand{
+			Intent:      intent.IntentOpenPosition,
+			Symbol:      symbol,
+			Side:        sidePtr(types.SideLong),
+			OrderType:   intent.OrderTypeMarket,
+			StopLoss:    float64Ptr(1),
+			NotionalUSD: float64Ptr(notional),
+		}
+	}
+
+	tests := []struct {
+		name  string
+		cmd   *intent.NormalizedCommand
+		codes []intent.IssueCode
+	}{
+		{"Within limits", open("ETH-USDT", 10000), nil},
+		{"Over the limit", open("ETH-USDT", 80000), []intent.IssueCode{"max_notional"}},
+		{"Every rule runs", open("LUNA-USDT", 80000), []intent.IssueCode{"max_notional", "banned_symbol"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ValidateCommand(tt.cmd, WithRules(maxNotional), WithRules(banned))
+
+			var codes []intent.IssueCode
+			for _, issue := range tt.cmd.Issues {
+				codes = append(codes, issue.Code)
+			}
+			if len(codes) != len(tt.codes) {
+				t.Fatalf("Issues = %+v, want codes %v", tt.cmd.Issues, tt.codes)
+			}
+			for i := range codes {
+				if codes[i] != tt.codes[i] {
+					t.Errorf("Issues[%d].Code = %s, want %s", i, codes[i], tt.codes[i])
+				}
+			}
+			if tt.cmd.Valid != (len(tt.codes) == 0) {
+				t.Errorf("Valid = %v, Errors = %v", tt.cmd.Valid, tt.cmd.Errors)
+			}
+			if len(tt.cmd.Errors) != len(tt.codes) {
+				t.Errorf("Errors = %v, want one per rule issue", tt.cmd.Errors)
+			}
+		})
 	}
 }
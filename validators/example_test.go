@@ -0,0 +1,86 @@
+package validators_test
+
+import (
+	"fmt"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/validators"
+)
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func sidePtr(s intent.Side) *intent.Side { return &s }
+
+// Checking commands before executing them. In real use the commands come
+// from a processor, which validates them already.
+func ExampleValidateCommand() {
+	cmds := []*intent.NormalizedCommand{
+		{
+			Intent:      intent.IntentOpenPosition,
+			Symbol:      "BTC-USDT",
+			Side:        sidePtr(intent.SideLong),
+			EntryPrice:  float64Ptr(45000),
+			StopLoss:    float64Ptr(44500),
+			RiskPercent: float64Ptr(2),
+		},
+		{
+			Intent: intent.IntentOpenPosition,
+			Symbol: "ETH-USDT",
+			Side:   sidePtr(intent.SideLong),
+		},
+		{
+			Intent:      intent.IntentOpenPosition,
+			Symbol:      "BTC-USDT",
+			Side:        sidePtr(intent.SideLong),
+			EntryPrice:  float64Ptr(45000),
+			StopLoss:    float64Ptr(46000),
+			RiskPercent: float64Ptr(2),
+		},
+		{Intent: intent.IntentUnknown, RawInput: "do something with crypto"},
+	}
+
+	for _, cmd := range cmds {
+		validators.ValidateCommand(cmd)
+		if cmd.Valid {
+			fmt.Printf("%s: ready to execute\n", cmd.Intent)
+			continue
+		}
+		issue, _ := cmd.PrimaryIssue()
+		fmt.Printf("%s: %s %q\n", cmd.Intent, issue.Code, issue.Message)
+	}
+	// Output:
+	// open_position: ready to execute
+	// open_position: missing "entry_price is required"
+	// open_position: price_side "stop_loss must be below entry_price for LONG"
+	// unknown: unknown_intent "unknown intent: unknown"
+}
+
+// Adding a house rule on top of the built-in open_position checks
+func ExampleRegistry() {
+	registry := validators.NewRegistry()
+	builtin, _ := registry.Lookup(intent.IntentOpenPosition)
+	registry.Register(intent.IntentOpenPosition, func(cmd *intent.NormalizedCommand, policy validators.Policy) {
+		builtin(cmd, policy)
+		if cmd.RRRatio == nil || *cmd.RRRatio < 2 {
+			cmd.AddIssue(intent.ValidationIssue{
+				Code:    intent.IssueCodeOutOfRange,
+				Field:   "rr_ratio",
+				Message: "risk/reward must be at least 2",
+			})
+		}
+	})
+
+	cmd := &intent.NormalizedCommand{
+		Intent:      intent.IntentOpenPosition,
+		Symbol:      "BTC-USDT",
+		Side:        sidePtr(intent.SideLong),
+		EntryPrice:  float64Ptr(45000),
+		StopLoss:    float64Ptr(44500),
+		RiskPercent: float64Ptr(1),
+		RRRatio:     float64Ptr(1.5),
+	}
+	registry.Validate(cmd, validators.DefaultPolicy())
+	fmt.Println(cmd.Valid, cmd.Errors)
+	// Output:
+	// false [risk/reward must be at least 2]
+}
@@ -0,0 +1,60 @@
+package validators
+
+import (
+	"github.com/agatticelli/intent-go"
+)
+
+// Policy holds deployment-specific validation rules.
+// The zero value is the most restrictive configuration.
+type Policy struct {
+	// AllowWithdrawals enables the withdraw intent (disabled by default)
+	AllowWithdrawals bool
+
+	// AddressWhitelisted reports whether ref names an address on the user's
+	// withdrawal whitelist, e.g. "ledger". Nil rejects every withdrawal.
+	AddressWhitelisted func(ref string) bool
+
+	// Tolerance is the absolute difference under which two prices or
+	// percentages are considered equal, e.g. the instrument tick size.
+	// It keeps float representation error in parsed decimals (33.34 + 33.33
+	// + 33.33) from producing spurious errors. Zero compares exactly.
+	Tolerance float64
+
+	// AllowedExchanges lists the venues commands may target, e.g. the
+	// exchanges the user has connected. Empty allows any exchange.
+	AllowedExchanges []string
+
+	// AccountExists reports whether the named account or subaccount exists,
+	// so commands targeting an unknown one are rejected before execution.
+	// Nil accepts any account.
+	AccountExists func(account string) bool
+
+	// MaxTakeProfitDistance is the distance from the entry price, as a
+	// fraction of it, beyond which a take profit draws a warning, e.g. 0.5
+	// for targets more than 50% away. Zero disables the warning.
+	MaxTakeProfitDistance float64
+
+	// UsualRiskPercent returns the risk per trade the user usually takes,
+	// or 0 when unknown; commands risking more draw a warning. Nil
+	// disables the warning.
+	UsualRiskPercent func(userID string) float64
+}
+
+// DefaultTolerance absorbs float representation error without hiding real
+// differences at any realistic tick size
+const DefaultTolerance = 1e-9
+
+// DefaultPolicy returns the policy applied by ValidateCommand
+func DefaultPolicy() Policy {
+	return Policy{Tolerance: DefaultTolerance}
+}
+
+// ValidateCommandWithPolicy validates a NormalizedCommand applying policy
+func ValidateCommandWithPolicy(cmd *intent.NormalizedCommand, policy Policy, opts ...ValidatorOption) {
+	validate(cmd, policy, builtinValidator, opts)
+}
+
+// exceeds reports whether a is greater than b by more than the tolerance
+func (p Policy) exceeds(a, b float64) bool {
+	return a-b > p.Tolerance
+}
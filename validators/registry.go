@@ -0,0 +1,111 @@
+package validators
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// Validator checks the intent-specific fields of a command, appending to
+// cmd.Missing and cmd.Errors and clearing cmd.Valid when it rejects it.
+// Checks shared by every intent (order type, exchange, account, ...) run
+// after it.
+type Validator func(cmd *intent.NormalizedCommand, policy Policy)
+
+// withoutPolicy adapts a validator that doesn't depend on the policy
+func withoutPolicy(validate func(*intent.NormalizedCommand)) Validator {
+	return func(cmd *intent.NormalizedCommand, _ Policy) {
+		validate(cmd)
+	}
+}
+
+// acceptAll is the validator of intents with nothing to check beyond the
+// shared rules, e.g. the optional symbol filter of view_positions
+func acceptAll(*intent.NormalizedCommand, Policy) {}
+
+// builtinValidators holds the validators ValidateCommand applies
+var builtinValidators = map[intent.Intent]Validator{
+	intent.IntentOpenPosition:     validateOpenPosition,
+	intent.IntentClosePosition:    withoutPolicy(validateClosePosition),
+	intent.IntentTrailingStop:     withoutPolicy(validateTrailingStop),
+	intent.IntentBreakEven:        withoutPolicy(validateBreakEven),
+	intent.IntentCopyTrade:        withoutPolicy(validateCopyTrade),
+	intent.IntentSetupGrid:        validateSetupGrid,
+	intent.IntentCancelAlert:      withoutPolicy(validateCancelAlert),
+	intent.IntentSetRiskDefaults:  withoutPolicy(validateSetRiskDefaults),
+	intent.IntentWithdraw:         validateWithdraw,
+	intent.IntentModifyPosition:   validateModifyPosition,
+	intent.IntentSetLeverage:      withoutPolicy(validateSetLeverage),
+	intent.IntentDCAOrder:         validateDCAOrder,
+	intent.IntentSetAlert:         withoutPolicy(validateSetAlert),
+	intent.IntentViewPnL:          withoutPolicy(validateViewPnL),
+	intent.IntentViewPrice:        withoutPolicy(validateViewPrice),
+	intent.IntentHedgePosition:    withoutPolicy(validateHedgePosition),
+	intent.IntentMoveStopLoss:     validateMoveStopLoss,
+	intent.IntentViewHistory:      withoutPolicy(validateViewHistory),
+	intent.IntentCancelOrders:     withoutPolicy(validateCancelOrders),
+	intent.IntentCheckOrderStatus: withoutPolicy(validateCheckOrderStatus),
+	intent.IntentViewPosition:     withoutPolicy(validateViewPosition),
+	intent.IntentPanicClose:       withoutPolicy(validatePanicClose),
+	intent.IntentSetTakeProfit:    validateSetTakeProfit,
+	intent.IntentSetStopLoss:      validateSetStopLoss,
+	intent.IntentCloseAll:         withoutPolicy(validateCloseAll),
+
+	intent.IntentViewPositions: acceptAll,
+	intent.IntentViewOrders:    acceptAll,
+	intent.IntentCheckBalance:  acceptAll,
+	intent.IntentViewAlerts:    acceptAll,
+	intent.IntentViewFunding:   acceptAll,
+	intent.IntentHelp:          acceptAll,
+}
+
+// builtinValidator returns the built-in validator for i
+func builtinValidator(i intent.Intent) (Validator, bool) {
+	v, ok := builtinValidators[i]
+	return v, ok
+}
+
+// Registry maps intents to the validators applied to their commands, so a
+// deployment can enforce house rules without forking the package. A new
+// Registry starts with the built-in validators; Register overrides one or
+// adds a validator for a custom intent. It is safe for concurrent use.
+type Registry struct {
+	mu         sync.RWMutex
+	validators map[intent.Intent]Validator
+}
+
+// NewRegistry returns a Registry holding the built-in validators
+func NewRegistry() *Registry {
+	return &Registry{validators: maps.Clone(builtinValidators)}
+}
+
+// Register sets the validator for i, replacing any registered before.
+// Wrap the current one (see Lookup) to add a rule instead of replacing it.
+// For a custom intent it takes precedence over the IntentSpec.Validate hook.
+func (r *Registry) Register(i intent.Intent, v Validator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.validators[i] = v
+}
+
+// Lookup returns the validator registered for i
+func (r *Registry) Lookup(i intent.Intent) (Validator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, ok := r.validators[i]
+	return v, ok
+}
+
+// Validate validates cmd like ValidateCommandWithPolicy, using the
+// registered validators
+func (r *Registry) Validate(cmd *intent.NormalizedCommand, policy Policy, opts ...ValidatorOption) {
+	validate(cmd, policy, r.Lookup, opts)
+}
+
+// Shadow is Shadow using the registered validators
+func (r *Registry) Shadow(cmd *intent.NormalizedCommand, policy Policy, opts ...ValidatorOption) Outcome {
+	shadow := cmd.Clone()
+	r.Validate(shadow, policy, opts...)
+	return OutcomeOf(shadow)
+}
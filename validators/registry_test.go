@@ -0,0 +1,105 @@
+package validators
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/trading-common-types"
+)
+
+// minRR wraps the built-in open_position validator with a house rule
+// requiring a risk/reward of at least min
+func minRR(r *Registry, min float64) {
+	builtin, _ := r.Lookup(intent.IntentOpenPosition)
+	r.Register(intent.IntentOpenPosition, func(cmd *intent.NormalizedCommand, policy Policy) {
+		builtin(cmd, policy)
+		if cmd.RRRatio == nil || *cmd.RRRatio < min {
+			cmd.Errors = append(cmd.Errors, fmt.Sprintf("risk/reward must be at least %g", min))
+			cmd.Valid = false
+		}
+	})
+}
+
+func TestRegistry_Override(t *testing.T) {
+	r := NewRegistry()
+	minRR(r, 2)
+
+	open := func(rr float64) *intent.NormalizedCommand {
+		return &intent.NormalizedCommand{
+			Intent:      intent.IntentOpenPosition,
+			Symbol:      "BTC-USDT",
+			Side:        sidePtr(types.SideLong),
+			EntryPrice:  float64Ptr(45000),
+			StopLoss:    float64Ptr(44000),
+			RiskPercent: float64Ptr(1),
+			RRRatio:     float64Ptr(rr),
+		}
+	}
+
+	cmd := open(1.5)
+	r.Validate(cmd, DefaultPolicy())
+	if cmd.Valid || !equalStrings(cmd.Errors, []string{"risk/reward must be at least 2"}) {
+		t.Errorf("Valid = %v, Errors = %v, want the house rule to reject RR 1.5", cmd.Valid, cmd.Errors)
+	}
+
+	cmd = open(3)
+	r.Validate(cmd, DefaultPolicy())
+	if !cmd.Valid {
+		t.Errorf("Valid = false, Errors = %v, want RR 3 accepted", cmd.Errors)
+	}
+
+	// The built-in checks still run under the override
+	cmd = open(3)
+	cmd.StopLoss = nil
+	r.Validate(cmd, DefaultPolicy())
+	if cmd.Valid || !equalStrings(cmd.Missing, []string{"stop_loss"}) {
+		t.Errorf("Valid = %v, Missing = %v, want stop_loss missing", cmd.Valid, cmd.Missing)
+	}
+
+	// Other registries and ValidateCommand keep the built-in validator
+	cmd = open(1.5)
+	ValidateCommand(cmd)
+	if !cmd.Valid {
+		t.Errorf("ValidateCommand: Valid = false, Errors = %v, want the override scoped to its registry", cmd.Errors)
+	}
+	if outcome := r.Shadow(open(1.5), DefaultPolicy()); outcome.Valid {
+		t.Error("Shadow: Valid = true, want the registered validators applied")
+	}
+}
+
+func TestRegistry_CustomIntent(t *testing.T) {
+	transfer := intent.Namespaced("registry-test", "fund_transfer")
+	intent.RegisterIntent(intent.IntentSpec{Intent: transfer})
+
+	r := NewRegistry()
+	r.Register(transfer, func(cmd *intent.NormalizedCommand, policy Policy) {
+		if cmd.Amount == nil {
+			cmd.Missing = append(cmd.Missing, "amount")
+			cmd.Valid = false
+		}
+	})
+
+	cmd := &intent.NormalizedCommand{Intent: transfer}
+	r.Validate(cmd, DefaultPolicy())
+	if cmd.Valid || !equalStrings(cmd.Missing, []string{"amount"}) {
+		t.Errorf("Valid = %v, Missing = %v, want the registered validator to require amount", cmd.Valid, cmd.Missing)
+	}
+
+	// Without an override the IntentSpec accepts every command
+	cmd = &intent.NormalizedCommand{Intent: transfer}
+	ValidateCommand(cmd)
+	if !cmd.Valid {
+		t.Errorf("ValidateCommand: Valid = false, Errors = %v", cmd.Errors)
+	}
+
+	// A validator doesn't make an unregistered intent known to the package,
+	// but the registry accepts it
+	unlisted := intent.Namespaced("registry-test", "unlisted")
+	r.Register(unlisted, func(*intent.NormalizedCommand, Policy) {})
+	cmd = &intent.NormalizedCommand{Intent: unlisted}
+	r.Validate(cmd, DefaultPolicy())
+	if !cmd.Valid {
+		t.Errorf("Valid = false, Errors = %v, want the registered validator to accept", cmd.Errors)
+	}
+}
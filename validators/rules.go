@@ -0,0 +1,75 @@
+package validators
+
+import (
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// RuleFunc is a check run after the built-in validation, e.g. a desk's
+// maximum notional or banned symbols. It returns the problems found, if
+// any: errors make the command invalid and warnings ask for confirmation
+// (see intent.NormalizedCommand.AddIssue). Severity defaults to error.
+type RuleFunc func(cmd *intent.NormalizedCommand) []intent.ValidationIssue
+
+// ValidatorOption configures a validation call
+type ValidatorOption func(*validateOptions)
+
+type validateOptions struct {
+	rules         []RuleFunc
+	referenceTime time.Time
+	clock         intent.Clock
+}
+
+// WithRules runs rules, in order, after the built-in validators
+func WithRules(rules ...RuleFunc) ValidatorOption {
+	return func(o *validateOptions) {
+		o.rules = append(o.rules, rules...)
+	}
+}
+
+// WithReferenceTime checks times such as expires_at against t, the
+// reference time the command was parsed with (see intent.WithReferenceTime),
+// instead of the command's Timestamp
+func WithReferenceTime(t time.Time) ValidatorOption {
+	return func(o *validateOptions) {
+		o.referenceTime = t
+	}
+}
+
+// WithClock sets the clock that gives the current time for commands with
+// neither a reference time nor a Timestamp (default intent.SystemClock)
+func WithClock(clock intent.Clock) ValidatorOption {
+	return func(o *validateOptions) {
+		o.clock = clock
+	}
+}
+
+func newValidateOptions(opts []ValidatorOption) validateOptions {
+	o := validateOptions{clock: intent.SystemClock{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// issuedAt returns the time cmd is checked against: the reference time,
+// else the command's Timestamp, else the clock's current time
+func (o validateOptions) issuedAt(cmd *intent.NormalizedCommand) time.Time {
+	if !o.referenceTime.IsZero() {
+		return o.referenceTime
+	}
+	if !cmd.Timestamp.IsZero() {
+		return cmd.Timestamp
+	}
+	return o.clock.Now()
+}
+
+// applyRules records the issues reported by the rules in o
+func (o validateOptions) applyRules(cmd *intent.NormalizedCommand) {
+	for _, rule := range o.rules {
+		for _, issue := range rule(cmd) {
+			cmd.AddIssue(issue)
+		}
+	}
+}
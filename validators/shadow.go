@@ -0,0 +1,45 @@
+package validators
+
+import (
+	"slices"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// Outcome is the result of validating a command
+type Outcome struct {
+	Valid                bool
+	Missing              []string
+	Errors               []string
+	Warnings             []string
+	ConfirmationRequired bool
+}
+
+// OutcomeOf returns the validation outcome recorded on cmd
+func OutcomeOf(cmd *intent.NormalizedCommand) Outcome {
+	return Outcome{
+		Valid:                cmd.Valid,
+		Missing:              slices.Clone(cmd.Missing),
+		Errors:               slices.Clone(cmd.Errors),
+		Warnings:             slices.Clone(cmd.Warnings),
+		ConfirmationRequired: cmd.ConfirmationRequired,
+	}
+}
+
+// Equal reports whether both outcomes accept or reject a command the same way
+func (o Outcome) Equal(other Outcome) bool {
+	return o.Valid == other.Valid &&
+		o.ConfirmationRequired == other.ConfirmationRequired &&
+		slices.Equal(o.Missing, other.Missing) &&
+		slices.Equal(o.Errors, other.Errors) &&
+		slices.Equal(o.Warnings, other.Warnings)
+}
+
+// Shadow validates a copy of cmd with policy and returns the outcome,
+// leaving cmd untouched. Comparing it with the active outcome lets a
+// candidate policy run alongside the enforced one before it is rolled out.
+func Shadow(cmd *intent.NormalizedCommand, policy Policy, opts ...ValidatorOption) Outcome {
+	shadow := cmd.Clone()
+	validate(shadow, policy, builtinValidator, opts)
+	return OutcomeOf(shadow)
+}
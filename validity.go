@@ -0,0 +1,25 @@
+package intent
+
+// Validity breaks Valid down into the stages a command goes through, so a
+// UI can show progress ("understood, waiting for stop loss") instead of a
+// single boolean. Stages are cumulative: a later one is only true when the
+// earlier ones are.
+type Validity struct {
+	// ParseOK is true when the input was understood as a known intent
+	ParseOK bool `json:"parse_ok"`
+	// FieldsComplete is true when no required field is missing
+	FieldsComplete bool `json:"fields_complete"`
+	// PolicyOK is true when the values passed validation
+	PolicyOK bool `json:"policy_ok"`
+}
+
+// Validity reports the stages the command has passed. It is derived from
+// Intent, Missing and Errors, so it stays in sync with whatever validators
+// and plugins reported.
+func (c *NormalizedCommand) Validity() Validity {
+	var v Validity
+	v.ParseOK = c.Intent != "" && c.Intent != IntentUnknown
+	v.FieldsComplete = v.ParseOK && len(c.Missing) == 0
+	v.PolicyOK = v.FieldsComplete && len(c.Errors) == 0
+	return v
+}
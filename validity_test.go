@@ -0,0 +1,45 @@
+package intent
+
+import "testing"
+
+func TestNormalizedCommand_Validity(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  NormalizedCommand
+		want Validity
+	}{
+		{
+			name: "Not understood",
+			cmd:  NormalizedCommand{Intent: IntentUnknown, Errors: []string{"unknown intent: unknown"}},
+			want: Validity{},
+		},
+		{
+			name: "Empty intent",
+			cmd:  NormalizedCommand{},
+			want: Validity{},
+		},
+		{
+			name: "Waiting for fields",
+			cmd:  NormalizedCommand{Intent: IntentOpenPosition, Missing: []string{"stop_loss"}},
+			want: Validity{ParseOK: true},
+		},
+		{
+			name: "Policy rejected",
+			cmd:  NormalizedCommand{Intent: IntentSetLeverage, Errors: []string{"leverage must be between 1 and 125"}},
+			want: Validity{ParseOK: true, FieldsComplete: true},
+		},
+		{
+			name: "Valid",
+			cmd:  NormalizedCommand{Intent: IntentViewPositions, Valid: true},
+			want: Validity{ParseOK: true, FieldsComplete: true, PolicyOK: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cmd.Validity(); got != tt.want {
+				t.Errorf("Validity() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package intent
+
+import (
+	"maps"
+	"runtime/debug"
+	"slices"
+)
+
+const modulePath = "github.com/agatticelli/intent-go"
+
+// Version returns the intent-go module version recorded in the binary's
+// build info, e.g. "v1.4.0", or "(devel)" when built from a local checkout
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			return dep.Replace.Version
+		}
+		if dep.Replace != nil {
+			return "(devel)"
+		}
+		return dep.Version
+	}
+	return "(devel)"
+}
+
+// ParseMeta records what produced a command, so its provenance can be
+// traced across deployments
+type ParseMeta struct {
+	// Processor is the name of the processor that parsed the input
+	Processor string `json:"processor"`
+
+	// Versions maps each component that shaped the result to its version:
+	// "intent-go" itself, backend tables such as "witai/symbols", and
+	// plugins (e.g. language packs) as "plugin/<name>"
+	Versions map[string]string `json:"versions"`
+
+	// Sources lists each backend's result when a composite processor, such
+	// as a fallback chain or an ensemble, produced the command. Processor
+	// is then the backend whose result won and Rationale says why, e.g.
+	// "highest confidence" or "primary failed". Both are empty for a single
+	// backend.
+	Sources   []SourceResult `json:"sources,omitempty"`
+	Rationale string         `json:"rationale,omitempty"`
+}
+
+// SourceResult is what one backend of a composite processor returned
+type SourceResult struct {
+	Processor  string  `json:"processor"`
+	Intent     Intent  `json:"intent,omitempty"`
+	Confidence float64 `json:"confidence"`
+	Err        string  `json:"error,omitempty"`
+}
+
+// SourceOf summarizes the outcome of processor for ParseMeta.Sources
+func SourceOf(processor string, cmd *NormalizedCommand, err error) SourceResult {
+	src := SourceResult{Processor: processor}
+	if err != nil {
+		src.Err = err.Error()
+		return src
+	}
+	if cmd != nil {
+		src.Intent = cmd.Intent
+		src.Confidence = cmd.Confidence
+	}
+	return src
+}
+
+// NewParseMeta returns the metadata of a command parsed by processor, with
+// the intent-go version, the processor's component versions and the
+// versions of plugins that declare one
+func NewParseMeta(processor string, components map[string]string, plugins []Plugin) *ParseMeta {
+	versions := map[string]string{"intent-go": Version()}
+	maps.Copy(versions, components)
+	for _, p := range plugins {
+		if p.Version != "" {
+			versions["plugin/"+p.Name] = p.Version
+		}
+	}
+	return &ParseMeta{Processor: processor, Versions: versions}
+}
+
+// Clone returns a deep copy of m
+func (m *ParseMeta) Clone() *ParseMeta {
+	if m == nil {
+		return nil
+	}
+	return &ParseMeta{
+		Processor: m.Processor,
+		Versions:  maps.Clone(m.Versions),
+		Sources:   slices.Clone(m.Sources),
+		Rationale: m.Rationale,
+	}
+}
@@ -0,0 +1,66 @@
+package intent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	if Version() == "" {
+		t.Error("Version() is empty")
+	}
+}
+
+func TestNewParseMeta(t *testing.T) {
+	meta := NewParseMeta("witai", map[string]string{"witai/symbols": "abc123"}, []Plugin{
+		{Name: "slang-es", Version: "2024.06"},
+		{Name: "unversioned"},
+	})
+
+	want := map[string]string{
+		"intent-go":       Version(),
+		"witai/symbols":   "abc123",
+		"plugin/slang-es": "2024.06",
+	}
+	if meta.Processor != "witai" {
+		t.Errorf("Processor = %q, want witai", meta.Processor)
+	}
+	if len(meta.Versions) != len(want) {
+		t.Errorf("Versions = %v, want %v", meta.Versions, want)
+	}
+	for k, v := range want {
+		if meta.Versions[k] != v {
+			t.Errorf("Versions[%q] = %q, want %q", k, meta.Versions[k], v)
+		}
+	}
+}
+
+func TestParseMeta_CloneIsDeep(t *testing.T) {
+	meta := NewParseMeta("witai", nil, nil)
+	meta.Sources = []SourceResult{{Processor: "witai", Intent: IntentViewPrice, Confidence: 0.9}}
+	clone := meta.Clone()
+	clone.Versions["intent-go"] = "changed"
+	clone.Sources[0].Confidence = 0.1
+
+	if meta.Versions["intent-go"] == "changed" {
+		t.Error("Clone shares the Versions map")
+	}
+	if meta.Sources[0].Confidence != 0.9 {
+		t.Error("Clone shares the Sources slice")
+	}
+	if (*ParseMeta)(nil).Clone() != nil {
+		t.Error("Clone() of nil meta should be nil")
+	}
+}
+
+func TestSourceOf(t *testing.T) {
+	cmd := &NormalizedCommand{Intent: IntentClosePosition, Confidence: 0.82}
+	if got := SourceOf("witai", cmd, nil); got != (SourceResult{Processor: "witai", Intent: IntentClosePosition, Confidence: 0.82}) {
+		t.Errorf("SourceOf() = %+v, want the command's intent and confidence", got)
+	}
+
+	got := SourceOf("rules", nil, errors.New("no match"))
+	if got.Err != "no match" || got.Intent != "" {
+		t.Errorf("SourceOf() = %+v, want only the error", got)
+	}
+}
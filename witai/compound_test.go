@@ -0,0 +1,153 @@
+package witai_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/witai"
+	"github.com/agatticelli/intent-go/witai/witaitest"
+)
+
+func TestParseCommand_CompoundCommands(t *testing.T) {
+	input := "open long BTC at 45000 and set an alert at 47000"
+
+	s := witaitest.NewServer()
+	defer s.Close()
+	s.Respond(input, witai.WitAIResponse{
+		Intents: []witai.WitAIIntent{
+			{Name: "open_position", Confidence: 0.62},
+			{Name: "set_alert", Confidence: 0.55},
+		},
+	})
+	s.RespondIntent("open long BTC at 45000", "open_position", 0.95, map[string]string{
+		"symbol": "BTC", "side": "long", "entry_price": "45000",
+	})
+	s.RespondIntent("set an alert at 47000", "set_alert", 0.93, map[string]string{"alert_price": "47000"})
+
+	p, err := witai.New(witaitest.Token, witai.WithBaseURL(s.URL), witai.WithCompoundCommands(0.5))
+	if err != nil {
+		t.Fatalf("witai.New error: %v", err)
+	}
+
+	cmd, err := p.ParseCommand(context.Background(), input)
+	if err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	commands := cmd.Commands()
+	if len(commands) != 2 {
+		t.Fatalf("got %d commands, want 2", len(commands))
+	}
+	if commands[0].Intent != intent.IntentOpenPosition || commands[0].Symbol != "BTC-USDT" {
+		t.Errorf("first command = %s %s, want open_position BTC-USDT", commands[0].Intent, commands[0].Symbol)
+	}
+	if commands[1].Intent != intent.IntentSetAlert || commands[1].AlertPrice == nil || *commands[1].AlertPrice != 47000 {
+		t.Errorf("second command = %s %v, want set_alert at 47000", commands[1].Intent, commands[1].AlertPrice)
+	}
+	if commands[1].RawInput != "set an alert at 47000" {
+		t.Errorf("second RawInput = %q, want its clause", commands[1].RawInput)
+	}
+}
+
+func TestParseCommand_QuestionAndCommand(t *testing.T) {
+	input := "what's BTC at? open a long if it's under 45k"
+
+	s := witaitest.NewServer()
+	defer s.Close()
+	s.Respond(input, witai.WitAIResponse{
+		Intents: []witai.WitAIIntent{
+			{Name: "view_price", Confidence: 0.58},
+			{Name: "open_position", Confidence: 0.54},
+		},
+	})
+	s.RespondIntent("what's BTC at", "view_price", 0.96, map[string]string{"symbol": "BTC"})
+	s.RespondIntent("open a long if it's under 45k", "open_position", 0.91, map[string]string{
+		"side": "long", "condition_operator": "under", "condition_price": "45000",
+	})
+
+	p, err := witai.New(witaitest.Token, witai.WithBaseURL(s.URL), witai.WithCompoundCommands(0.5))
+	if err != nil {
+		t.Fatalf("witai.New error: %v", err)
+	}
+
+	cmd, err := p.ParseCommand(context.Background(), input)
+	if err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	commands := cmd.Commands()
+	if len(commands) != 2 {
+		t.Fatalf("got %d commands, want 2", len(commands))
+	}
+	question, order := commands[0], commands[1]
+	if question.Intent != intent.IntentViewPrice || order.Intent != intent.IntentOpenPosition {
+		t.Fatalf("intents = %s, %s, want view_price then open_position", question.Intent, order.Intent)
+	}
+	if order.Symbol != "BTC-USDT" {
+		t.Errorf("order Symbol = %q, want the question's BTC-USDT", order.Symbol)
+	}
+	if len(order.DependsOn) != 1 || order.DependsOn[0] != question.ID {
+		t.Errorf("order DependsOn = %v, want [%s]", order.DependsOn, question.ID)
+	}
+	if order.Condition == nil || order.Condition.Operator != intent.ConditionBelow {
+		t.Errorf("order Condition = %+v, want below 45000", order.Condition)
+	}
+	for _, missing := range order.Missing {
+		if missing == "symbol" {
+			t.Error("order reports symbol missing after linking")
+		}
+	}
+}
+
+func TestParseCommand_CompoundFallsBackToWholeInput(t *testing.T) {
+	input := "open long BTC with TP at 46000 and 47000"
+
+	s := witaitest.NewServer()
+	defer s.Close()
+	s.Respond(input, witai.WitAIResponse{
+		Intents: []witai.WitAIIntent{
+			{Name: "open_position", Confidence: 0.8},
+			{Name: "view_price", Confidence: 0.6},
+		},
+	})
+	s.RespondIntent("open long BTC with TP at 46000", "open_position", 0.9, nil)
+
+	p, err := witai.New(witaitest.Token, witai.WithBaseURL(s.URL), witai.WithCompoundCommands(0.5))
+	if err != nil {
+		t.Fatalf("witai.New error: %v", err)
+	}
+
+	cmd, err := p.ParseCommand(context.Background(), input)
+	if err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+	if len(cmd.SubCommands) != 0 || cmd.RawInput != input {
+		t.Errorf("SubCommands = %v, RawInput = %q, want the whole input as one command", cmd.SubCommands, cmd.RawInput)
+	}
+}
+
+func TestParseCommand_CompoundDisabledByDefault(t *testing.T) {
+	input := "open long BTC at 45000 and set an alert at 47000"
+
+	s := witaitest.NewServer()
+	defer s.Close()
+	s.Respond(input, witai.WitAIResponse{
+		Intents: []witai.WitAIIntent{
+			{Name: "open_position", Confidence: 0.62},
+			{Name: "set_alert", Confidence: 0.55},
+		},
+	})
+
+	p, err := witai.New(witaitest.Token, witai.WithBaseURL(s.URL))
+	if err != nil {
+		t.Fatalf("witai.New error: %v", err)
+	}
+
+	if _, err := p.ParseCommand(context.Background(), input); err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+	if n := len(s.Requests()); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,99 @@
+package witai
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// entityFields maps each entity the transformer reads to the
+// NormalizedCommand fields it fills, named by their JSON keys
+var entityFields = map[string][]string{
+	"symbol":                  {"symbol"},
+	"exclude_symbol":          {"exclude_symbols"},
+	"quote_currency":          {"symbol"},
+	"side":                    {"side"},
+	"order_type":              {"order_type"},
+	"exchange":                {"exchange"},
+	"account":                 {"account"},
+	"time_in_force":           {"time_in_force"},
+	"reduce_only":             {"reduce_only"},
+	"margin_mode":             {"margin_mode"},
+	"entry_price":             {"entry_price"},
+	"price:entry":             {"entry_price"},
+	"stop_loss":               {"stop_loss"},
+	"price:stop_loss":         {"stop_loss"},
+	"stop_loss_offset":        {"stop_loss_offset"},
+	"take_profit":             {"take_profit"},
+	"price:take_profit":       {"take_profit"},
+	"risk":                    {"risk_percent"},
+	"leverage":                {"leverage"},
+	"quantity":                {"quantity"},
+	"notional":                {"notional_usd"},
+	"close_percent":           {"close_percent"},
+	"trigger_price":           {"trigger_price"},
+	"callback_rate":           {"callback_rate"},
+	"source_account":          {"source_account"},
+	"target_account":          {"target_account"},
+	"size_factor":             {"size_factor"},
+	"hedge_ratio":             {"hedge_ratio"},
+	"levels":                  {"tp_levels"},
+	"sl_levels":               {"sl_levels"},
+	"entry_levels":            {"entry_levels"},
+	"condition_symbol":        {"condition"},
+	"condition_operator":      {"condition"},
+	"condition_price":         {"condition"},
+	"grid_range":              {"grid_lower", "grid_upper"},
+	"grid_lower":              {"grid_lower"},
+	"grid_upper":              {"grid_upper"},
+	"grid_levels":             {"grid_levels"},
+	"grid_level_size":         {"grid_level_size"},
+	"order_id":                {"order_id"},
+	"scope":                   {"cancel_all", "latest_order"},
+	"alert_id":                {"alert_id"},
+	"alert_price":             {"alert_price"},
+	"alert_direction":         {"alert_direction"},
+	"asset":                   {"asset"},
+	"amount":                  {"amount"},
+	"address_ref":             {"address_ref"},
+	"period":                  {"period"},
+	"timeframe":               {"timeframe"},
+	"wit$datetime:datetime":   {"from", "to"},
+	"datetime":                {"from", "to"},
+	"wit$datetime:expires_at": {"expires_at"},
+	"expires_at":              {"expires_at"},
+}
+
+// commandFields maps the JSON keys of NormalizedCommand to field indexes
+var commandFields = func() map[string]int {
+	fields := make(map[string]int)
+	typ := reflect.TypeOf(intent.NormalizedCommand{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		fields[name] = i
+	}
+	return fields
+}()
+
+// setFieldConfidence fills cmd.FieldConfidence from the entity confidences
+// in cmd.EntityMeta. Fields the entity didn't fill, e.g. a price that
+// didn't parse, are left out; a field filled by several entities gets the
+// lowest of their confidences.
+func setFieldConfidence(cmd *intent.NormalizedCommand) {
+	v := reflect.ValueOf(cmd).Elem()
+	for entity, span := range cmd.EntityMeta {
+		for _, field := range entityFields[entity] {
+			i, ok := commandFields[field]
+			if !ok || v.Field(i).IsZero() {
+				continue
+			}
+			if cmd.FieldConfidence == nil {
+				cmd.FieldConfidence = make(map[string]float64)
+			}
+			if prev, ok := cmd.FieldConfidence[field]; !ok || span.Confidence < prev {
+				cmd.FieldConfidence[field] = span.Confidence
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package witai
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestEntityFields_CoverSchema(t *testing.T) {
+	for _, spec := range witEntities {
+		keys := []string{spec.Name}
+		if len(spec.Roles) > 0 {
+			keys = nil
+			for _, role := range spec.Roles {
+				keys = append(keys, spec.Name+":"+role)
+			}
+		}
+		for _, key := range keys {
+			fields, ok := entityFields[key]
+			if !ok {
+				t.Errorf("entity %s has no entry in entityFields", key)
+			}
+			for _, field := range fields {
+				if _, ok := commandFields[field]; !ok {
+					t.Errorf("entity %s fills unknown field %q", key, field)
+				}
+			}
+		}
+	}
+}
+
+func TestTransformWitResponse_FieldConfidence(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "open_position", Confidence: 0.97}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":          {{Value: "btc", Confidence: 0.99}},
+			"side":            {{Value: "long", Confidence: 0.95}},
+			"price:entry":     {{Value: "45000", Confidence: 0.93}},
+			"price:stop_loss": {{Value: "44500", Confidence: 0.71}},
+			"stop_loss":       {{Value: "44500", Confidence: 0.88}},
+			"risk":            {{Value: "lots", Confidence: 0.9}},
+		},
+	}
+
+	got := transformWitResponse(resp, "open long BTC at 45000 with stop 44500 risking lots")
+
+	want := map[string]float64{
+		"symbol":      0.99,
+		"side":        0.95,
+		"entry_price": 0.93,
+		"stop_loss":   0.71, // Lowest of the two entities filling it
+	}
+	if !maps.Equal(got.FieldConfidence, want) {
+		t.Errorf("FieldConfidence = %v, want %v", got.FieldConfidence, want)
+	}
+	if low := got.LowConfidenceFields(0.9, "stop_loss", "entry_price"); len(low) != 1 || low[0] != "stop_loss" {
+		t.Errorf("LowConfidenceFields = %v, want [stop_loss]", low)
+	}
+}
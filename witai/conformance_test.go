@@ -0,0 +1,24 @@
+package witai_test
+
+import (
+	"testing"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/intenttest"
+	"github.com/agatticelli/intent-go/witai"
+	"github.com/agatticelli/intent-go/witai/witaitest"
+)
+
+func TestConformance(t *testing.T) {
+	intenttest.Run(t, func(t *testing.T) intent.Processor {
+		s := witaitest.NewServer()
+		t.Cleanup(s.Close)
+		s.RespondIntent("show my positions", "view_positions", 0.95, nil)
+
+		p, err := witai.New(witaitest.Token, witai.WithBaseURL(s.URL))
+		if err != nil {
+			t.Fatalf("witai.New error: %v", err)
+		}
+		return p
+	})
+}
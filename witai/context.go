@@ -0,0 +1,80 @@
+package witai
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// witContext is the JSON sent in the Wit.ai `context` query parameter
+type witContext struct {
+	ReferenceTime string              `json:"reference_time,omitempty"`
+	Timezone      string              `json:"timezone,omitempty"`
+	Locale        string              `json:"locale,omitempty"`
+	Coords        *intent.Coordinates `json:"coords,omitempty"`
+}
+
+// dynamicEntities is the JSON sent in the Wit.ai `dynamic_entities` query parameter
+type dynamicEntities struct {
+	Entities map[string][]dynamicKeyword `json:"entities"`
+}
+
+type dynamicKeyword struct {
+	Keyword  string   `json:"keyword"`
+	Synonyms []string `json:"synonyms"`
+}
+
+// addParseOptions sets the query parameters derived from per-request parse options
+func addParseOptions(q url.Values, opts intent.ParseOptions) error {
+	if err := addContextParam(q, opts); err != nil {
+		return err
+	}
+	return addDynamicEntitiesParam(q, opts.DynamicEntities)
+}
+
+// addContextParam sets the `context` query parameter from opts. Nothing is
+// sent when no option is set, so Wit.ai falls back to the app defaults.
+func addContextParam(q url.Values, opts intent.ParseOptions) error {
+	wc := witContext{
+		Timezone: opts.Timezone,
+		Locale:   opts.Locale,
+		Coords:   opts.Coords,
+	}
+	if !opts.ReferenceTime.IsZero() {
+		wc.ReferenceTime = opts.ReferenceTime.Format(time.RFC3339)
+	}
+	if wc == (witContext{}) {
+		return nil
+	}
+
+	data, err := json.Marshal(wc)
+	if err != nil {
+		return err
+	}
+	q.Set("context", string(data))
+	return nil
+}
+
+// addDynamicEntitiesParam sets the `dynamic_entities` query parameter. Each
+// keyword is its own synonym, so "PEPE" is recognized as the symbol "PEPE".
+func addDynamicEntitiesParam(q url.Values, entities map[string][]string) error {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	de := dynamicEntities{Entities: make(map[string][]dynamicKeyword, len(entities))}
+	for name, keywords := range entities {
+		for _, kw := range keywords {
+			de.Entities[name] = append(de.Entities[name], dynamicKeyword{Keyword: kw, Synonyms: []string{kw}})
+		}
+	}
+
+	data, err := json.Marshal(de)
+	if err != nil {
+		return err
+	}
+	q.Set("dynamic_entities", string(data))
+	return nil
+}
@@ -0,0 +1,87 @@
+package witai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+func TestParseCommand_SendsContext(t *testing.T) {
+	var raw string
+	var sent bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw = r.URL.Query().Get("context")
+		_, sent = r.URL.Query()["context"]
+		w.Write([]byte(positionsResponse))
+	}))
+	defer server.Close()
+
+	p, _ := New("token", WithBaseURL(server.URL))
+
+	if _, err := p.ParseCommand(context.Background(), "show my positions"); err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+	if sent {
+		t.Errorf("context param sent without parse options: %q", raw)
+	}
+
+	ref := time.Date(2024, 3, 4, 9, 30, 0, 0, time.FixedZone("ART", -3*3600))
+	ctx := intent.WithParseOptions(context.Background(),
+		intent.WithReferenceTime(ref),
+		intent.WithTimezone("America/Argentina/Buenos_Aires"),
+		intent.WithLocale("es_AR"),
+		intent.WithCoordinates(-34.6, -58.4),
+	)
+	if _, err := p.ParseCommand(ctx, "show my positions"); err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	var got witContext
+	if err := json.Unmarshal([]byte(raw), &got); err != nil {
+		t.Fatalf("context param %q is not JSON: %v", raw, err)
+	}
+	want := witContext{
+		ReferenceTime: "2024-03-04T09:30:00-03:00",
+		Timezone:      "America/Argentina/Buenos_Aires",
+		Locale:        "es_AR",
+		Coords:        &intent.Coordinates{Lat: -34.6, Long: -58.4},
+	}
+	if got.ReferenceTime != want.ReferenceTime || got.Timezone != want.Timezone ||
+		got.Locale != want.Locale || got.Coords == nil || *got.Coords != *want.Coords {
+		t.Errorf("context = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseCommand_SendsDynamicEntities(t *testing.T) {
+	var raw string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw = r.URL.Query().Get("dynamic_entities")
+		w.Write([]byte(positionsResponse))
+	}))
+	defer server.Close()
+
+	p, _ := New("token", WithBaseURL(server.URL))
+	ctx := intent.WithParseOptions(context.Background(),
+		intent.WithDynamicEntities(map[string][]string{"symbol": {"PEPE", "WIF"}}),
+	)
+	if _, err := p.ParseCommand(ctx, "show my positions"); err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	var got dynamicEntities
+	if err := json.Unmarshal([]byte(raw), &got); err != nil {
+		t.Fatalf("dynamic_entities param %q is not JSON: %v", raw, err)
+	}
+	symbols := got.Entities["symbol"]
+	if len(symbols) != 2 || symbols[0].Keyword != "PEPE" || symbols[1].Keyword != "WIF" {
+		t.Fatalf("symbol keywords = %+v, want PEPE and WIF", symbols)
+	}
+	if len(symbols[0].Synonyms) != 1 || symbols[0].Synonyms[0] != "PEPE" {
+		t.Errorf("PEPE synonyms = %v, want [PEPE]", symbols[0].Synonyms)
+	}
+}
@@ -0,0 +1,84 @@
+package witai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// DecodeMode controls how Wit.ai responses that don't match WitAIResponse
+// (e.g. unknown fields after an API change) are handled
+type DecodeMode int
+
+const (
+	// DecodeTolerant ignores unknown fields (default)
+	DecodeTolerant DecodeMode = iota
+
+	// DecodeStrictFallback reports unknown fields as decode anomalies, then
+	// decodes the response tolerantly
+	DecodeStrictFallback
+
+	// DecodeStrict rejects responses with unknown fields
+	DecodeStrict
+)
+
+// responseDecoder decodes a Wit.ai response body, rejecting unknown fields when strict
+type responseDecoder func(r io.Reader, strict bool) (*WitAIResponse, error)
+
+// decodeMessage decodes a /message response body
+func decodeMessage(r io.Reader, strict bool) (*WitAIResponse, error) {
+	dec := json.NewDecoder(r)
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+
+	var witResp WitAIResponse
+	if err := dec.Decode(&witResp); err != nil {
+		return nil, err
+	}
+	return &witResp, nil
+}
+
+// decodeResponse decodes body according to the processor's DecodeMode.
+// Anomalies are logged and reported to the metrics sink when it implements
+// intent.DecodeAnomalySink.
+func (p *Processor) decodeResponse(ctx context.Context, body io.Reader, decode responseDecoder) (*WitAIResponse, error) {
+	if p.decodeMode == DecodeTolerant {
+		return decode(body, false)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	witResp, err := decode(bytes.NewReader(data), true)
+	if err == nil {
+		return witResp, nil
+	}
+
+	// Only strict-mode failures are anomalies; a body that doesn't decode at
+	// all is a plain error
+	witResp, tolerantErr := decode(bytes.NewReader(data), false)
+	if tolerantErr != nil {
+		return nil, tolerantErr
+	}
+
+	p.reportDecodeAnomaly(ctx, err)
+	if p.decodeMode == DecodeStrict {
+		return nil, fmt.Errorf("unexpected wit.ai response schema: %w", err)
+	}
+	return witResp, nil
+}
+
+func (p *Processor) reportDecodeAnomaly(ctx context.Context, err error) {
+	p.logger.WarnContext(ctx, "wit.ai response schema anomaly", slog.Any("error", err))
+	if sink, ok := p.metrics.(intent.DecodeAnomalySink); ok {
+		sink.DecodeAnomaly(p.Name(), err)
+	}
+}
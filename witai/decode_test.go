@@ -0,0 +1,90 @@
+package witai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+const driftedResponse = `{
+	"text": "show my positions",
+	"intents": [{"id": "1", "name": "view_positions", "confidence": 0.97, "score": 0.97}],
+	"entities": {},
+	"traits": {}
+}`
+
+type anomalySink struct {
+	intent.NopMetrics
+
+	mu        sync.Mutex
+	anomalies []error
+}
+
+func (s *anomalySink) DecodeAnomaly(_ string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.anomalies = append(s.anomalies, err)
+}
+
+func TestDecodeMode(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          DecodeMode
+		body          string
+		wantErr       bool
+		wantAnomalies int
+	}{
+		{"tolerant ignores unknown fields", DecodeTolerant, driftedResponse, false, 0},
+		{"fallback reports and decodes", DecodeStrictFallback, driftedResponse, false, 1},
+		{"strict rejects unknown fields", DecodeStrict, driftedResponse, true, 1},
+		{"strict accepts known schema", DecodeStrict, positionsResponse, false, 0},
+		{"malformed body is not an anomaly", DecodeStrictFallback, `{"text":`, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			sink := &anomalySink{}
+			p, _ := New("token", WithBaseURL(server.URL), WithMetrics(sink), WithDecodeMode(tt.mode))
+
+			cmd, err := p.ParseCommand(context.Background(), "show my positions")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCommand error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cmd.Intent != intent.IntentViewPositions {
+				t.Errorf("Intent = %v, want %v", cmd.Intent, intent.IntentViewPositions)
+			}
+			if len(sink.anomalies) != tt.wantAnomalies {
+				t.Errorf("anomalies = %v, want %d", sink.anomalies, tt.wantAnomalies)
+			}
+		})
+	}
+}
+
+// Sinks without DecodeAnomaly still work in strict modes
+func TestDecodeMode_PlainSink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(driftedResponse))
+	}))
+	defer server.Close()
+
+	p, _ := New("token", WithBaseURL(server.URL), WithMetrics(plainSink{}), WithDecodeMode(DecodeStrictFallback))
+	if _, err := p.ParseCommand(context.Background(), "show my positions"); err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+}
+
+type plainSink struct{}
+
+func (plainSink) ParseStarted(string)                                          {}
+func (plainSink) ParseSucceeded(string, intent.Intent, float64, time.Duration) {}
+func (plainSink) ParseFailed(string, error, time.Duration)                     {}
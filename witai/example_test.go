@@ -0,0 +1,140 @@
+package witai_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/render"
+	"github.com/agatticelli/intent-go/session"
+	"github.com/agatticelli/intent-go/validators"
+	"github.com/agatticelli/intent-go/witai"
+	"github.com/agatticelli/intent-go/witai/witaitest"
+)
+
+// Parsing English and Spanish commands. The fake server stands in for a
+// trained Wit.ai app; point the processor at the real API by leaving out
+// WithBaseURL and passing your server access token.
+func Example() {
+	server := witaitest.NewServer()
+	defer server.Close()
+	server.RespondIntent("open long BTC at 45000 with stop loss 44500 and risk 2%", "open_position", 0.95, map[string]string{
+		"symbol":      "btc",
+		"side":        "long",
+		"entry_price": "45000",
+		"stop_loss":   "44500",
+		"risk":        "2",
+	})
+	server.RespondIntent("cerrar 50% de la posición de ETH", "close_position", 0.91, map[string]string{
+		"symbol":        "eth",
+		"close_percent": "50",
+	})
+	server.RespondIntent("show my positions", "view_positions", 0.98, nil)
+
+	processor, err := witai.New(witaitest.Token, witai.WithBaseURL(server.URL))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, input := range []string{
+		"open long BTC at 45000 with stop loss 44500 and risk 2%",
+		"cerrar 50% de la posición de ETH",
+		"show my positions",
+	} {
+		cmd, err := processor.ParseCommand(context.Background(), input)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s (%.2f) symbol=%q valid=%v\n", cmd.Intent, cmd.Confidence, cmd.Symbol, cmd.Valid)
+	}
+	// Output:
+	// open_position (0.95) symbol="BTC-USDT" valid=true
+	// close_position (0.91) symbol="ETH-USDT" valid=true
+	// view_positions (0.98) symbol="" valid=true
+}
+
+// Asking for what's missing and confirming before executing
+func Example_validation() {
+	server := witaitest.NewServer()
+	defer server.Close()
+	server.RespondIntent("long BTC at 45000", "open_position", 0.93, map[string]string{
+		"symbol":      "btc",
+		"side":        "long",
+		"entry_price": "45000",
+	})
+	server.RespondIntent("short ETH at 3000 stop 2900 risk 1", "open_position", 0.9, map[string]string{
+		"symbol":      "eth",
+		"side":        "short",
+		"entry_price": "3000",
+		"stop_loss":   "2900",
+		"risk":        "1",
+	})
+	server.RespondIntent("close BTC", "close_position", 0.96, map[string]string{"symbol": "btc"})
+
+	processor, err := witai.New(witaitest.Token, witai.WithBaseURL(server.URL))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, input := range []string{"long BTC at 45000", "short ETH at 3000 stop 2900 risk 1", "close BTC"} {
+		cmd, err := processor.ParseCommand(context.Background(), input)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !cmd.Valid {
+			issue := cmd.Issues[0]
+			fmt.Printf("%s: %s\n", issue.Code, render.Clarification(cmd, "en"))
+			continue
+		}
+		fmt.Println(render.Confirmation(cmd, "en"))
+	}
+	// Output:
+	// missing: To open a position I still need: stop loss and risk, quantity or notional.
+	// price_side: I can't open a position: stop_loss must be above entry_price for SHORT.
+	// Close the position: BTC-USDT. Confirm?
+}
+
+// Filling a symbol left out of a follow-up from the conversation history
+func Example_slotFilling() {
+	server := witaitest.NewServer()
+	defer server.Close()
+	server.RespondIntent("long SOL at 150 stop 140 risk 1", "open_position", 0.95, map[string]string{
+		"symbol":      "sol",
+		"side":        "long",
+		"entry_price": "150",
+		"stop_loss":   "140",
+		"risk":        "1",
+	})
+	server.RespondIntent("move the stop to break even", "break_even", 0.92, nil)
+
+	start := time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+	processor, err := witai.New(witaitest.Token,
+		witai.WithBaseURL(server.URL),
+		witai.WithClock(intent.NewStepClock(start, 0)),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+	s := &session.Session{UserID: "alice"}
+
+	opened, _ := processor.ParseCommand(ctx, "long SOL at 150 stop 140 risk 1")
+	s.Append(opened)
+
+	for _, later := range []time.Duration{2 * time.Minute, 40 * time.Minute} {
+		cmd, _ := processor.ParseCommand(ctx, "move the stop to break even")
+		for _, inherited := range s.Inherit(cmd, start.Add(later), session.DefaultDecay()) {
+			if inherited.Stale {
+				fmt.Printf("after %v: still %s?\n", later, inherited.Value)
+			}
+		}
+		validators.ValidateCommand(cmd)
+		fmt.Printf("after %v: %s %q valid=%v\n", later, cmd.Intent, cmd.Symbol, cmd.Valid)
+	}
+	// Output:
+	// after 2m0s: break_even "SOL-USDT" valid=true
+	// after 40m0s: still SOL-USDT?
+	// after 40m0s: break_even "" valid=false
+}
@@ -0,0 +1,32 @@
+package witai
+
+import "strings"
+
+// DefaultExchangeAliases maps spoken exchange names to the identifiers set
+// in NormalizedCommand.Exchange. Keys are lowercase with spaces, hyphens and
+// underscores removed, so "Ku Coin" and "kucoin" match the same entry.
+var DefaultExchangeAliases = map[string]string{
+	"binance":        "binance",
+	"binancefutures": "binance",
+	"bybit":          "bybit",
+	"kucoin":         "kucoin",
+	"kucoinfutures":  "kucoin",
+	"okx":            "okx",
+	"okex":           "okx",
+	"bingx":          "bingx",
+}
+
+// exchangeKey reduces an exchange name to its alias map key
+func exchangeKey(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// resolveExchange maps name through aliases. Unknown names are kept in key
+// form so validation can reject them against the allowed list.
+func resolveExchange(name string, aliases map[string]string) string {
+	key := exchangeKey(name)
+	if exchange, ok := aliases[key]; ok {
+		return exchange
+	}
+	return key
+}
@@ -0,0 +1,28 @@
+package witai
+
+import "testing"
+
+func TestResolveExchange(t *testing.T) {
+	aliases := map[string]string{"myfutures": "bybit"}
+	for k, v := range DefaultExchangeAliases {
+		aliases[k] = v
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Binance", "binance"},
+		{"binance futures", "binance"},
+		{"Ku Coin", "kucoin"},
+		{"OKEx", "okx"},
+		{"my futures", "bybit"},
+		{"Kraken", "kraken"},
+	}
+
+	for _, tt := range tests {
+		if got := resolveExchange(tt.input, aliases); got != tt.want {
+			t.Errorf("resolveExchange(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package witai
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// redactToken masks all but the last 4 characters of the bearer token
+func redactToken(token string) string {
+	if len(token) <= 4 {
+		return strings.Repeat("*", len(token))
+	}
+	return strings.Repeat("*", len(token)-4) + token[len(token)-4:]
+}
+
+// inputAttr returns the user input as a log attribute, redacted when configured
+func (p *Processor) inputAttr(input string) slog.Attr {
+	if p.redactInput {
+		return slog.String("input", fmt.Sprintf("[redacted %d chars]", len(input)))
+	}
+	return slog.String("input", input)
+}
+
+// responseAttrs summarizes a Wit.ai response for debug logging
+func responseAttrs(resp *WitAIResponse) []any {
+	intents := make([]string, 0, len(resp.Intents))
+	for _, i := range resp.Intents {
+		intents = append(intents, fmt.Sprintf("%s(%.2f)", i.Name, i.Confidence))
+	}
+
+	entities := make([]string, 0, len(resp.Entities))
+	for name, values := range resp.Entities {
+		entities = append(entities, fmt.Sprintf("%s(%d)", name, len(values)))
+	}
+	sort.Strings(entities)
+
+	return []any{
+		slog.Any("intents", intents),
+		slog.Any("entities", entities),
+	}
+}
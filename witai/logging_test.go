@@ -0,0 +1,38 @@
+package witai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactToken(t *testing.T) {
+	tests := []struct {
+		token string
+		want  string
+	}{
+		{"ABCDEFGH1234", "********1234"},
+		{"1234", "****"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := redactToken(tt.token); got != tt.want {
+			t.Errorf("redactToken(%q) = %q, want %q", tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestInputAttr(t *testing.T) {
+	input := "open long BTC at 45000"
+
+	p, _ := New("token")
+	if got := p.inputAttr(input).Value.String(); got != input {
+		t.Errorf("inputAttr() = %q, want %q", got, input)
+	}
+
+	p, _ = New("token", WithRedactedInput())
+	got := p.inputAttr(input).Value.String()
+	if strings.Contains(got, "BTC") {
+		t.Errorf("inputAttr() = %q, input should be redacted", got)
+	}
+}
@@ -0,0 +1,42 @@
+package witai_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/intent-go/witai"
+	"github.com/agatticelli/intent-go/witai/witaitest"
+)
+
+func TestParseCommand_MaxInputLength(t *testing.T) {
+	s := witaitest.NewServer()
+	defer s.Close()
+	s.RespondIntent("show my positions", "view_positions", 0.95, nil)
+
+	p, err := witai.New(witaitest.Token, witai.WithBaseURL(s.URL), witai.WithMaxInputLength(40))
+	if err != nil {
+		t.Fatalf("witai.New error: %v", err)
+	}
+
+	analysis := "Funding flipped negative overnight and open interest keeps climbing."
+	input := analysis + " show my positions"
+	cmd, err := p.ParseCommand(context.Background(), input)
+	if err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	requests := s.Requests()
+	if len(requests) != 1 || requests[0].Query != "show my positions" {
+		t.Fatalf("requests = %+v, want a single query for the command", requests)
+	}
+	if cmd.Note != analysis {
+		t.Errorf("Note = %q, want %q", cmd.Note, analysis)
+	}
+	if cmd.RawInput != input {
+		t.Errorf("RawInput = %q, want the full input", cmd.RawInput)
+	}
+	if strings.Contains(cmd.Note, "show my positions") {
+		t.Error("Note contains the parsed command")
+	}
+}
@@ -0,0 +1,269 @@
+// Package manage provisions a Wit.ai app through its training API: intents,
+// entities and utterances can be created from Go code, and Sync keeps the
+// app in line with the schema the witai transformer understands.
+package manage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultBaseURL is the Wit.ai API endpoint
+	defaultBaseURL = "https://api.wit.ai"
+
+	// apiVersion pins the Wit.ai API version sent with every request
+	apiVersion = "20240304"
+)
+
+// Client calls the Wit.ai app management API. It needs the app's server
+// access token; the client access token used for parsing is read-only.
+type Client struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient replaces the default HTTP client (30s timeout)
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// WithBaseURL points the client at a different Wit.ai compatible endpoint,
+// e.g. a fake server in tests
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// New creates a Wit.ai management client
+func New(token string, opts ...Option) (*Client, error) {
+	if token == "" {
+		return nil, fmt.Errorf("wit.ai token is required")
+	}
+
+	c := &Client{
+		token:   token,
+		baseURL: defaultBaseURL,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// APIError is returned when Wit.ai answers with a non-2xx status
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string // Wit.ai "error" field, when present
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("wit.ai %s %s returned status %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("wit.ai %s %s returned status %d", e.Method, e.Path, e.StatusCode)
+}
+
+// do sends body as JSON and decodes the response into out when non-nil
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	q := req.URL.Query()
+	q.Add("v", apiVersion)
+	req.URL.RawQuery = q.Encode()
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{Method: method, Path: path, StatusCode: resp.StatusCode}
+		var witErr struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&witErr) == nil {
+			apiErr.Message = witErr.Error
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// named is the {"id", "name"} shape Wit.ai uses for intents, entities and roles
+type named struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+func names(items []named) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = item.Name
+	}
+	return out
+}
+
+// Intents returns the names of the intents defined in the app
+func (c *Client) Intents(ctx context.Context) ([]string, error) {
+	var intents []named
+	if err := c.do(ctx, http.MethodGet, "/intents", nil, &intents); err != nil {
+		return nil, err
+	}
+	return names(intents), nil
+}
+
+// CreateIntent adds an intent to the app
+func (c *Client) CreateIntent(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPost, "/intents", named{Name: name}, nil)
+}
+
+// DeleteIntent removes an intent from the app
+func (c *Client) DeleteIntent(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, "/intents/"+url.PathEscape(name), nil, nil)
+}
+
+// Keyword is a canonical value of a keywords entity with its synonyms
+type Keyword struct {
+	Keyword  string   `json:"keyword"`
+	Synonyms []string `json:"synonyms"`
+}
+
+// Entity describes an entity to create or update
+type Entity struct {
+	Name     string    `json:"name"`
+	Roles    []string  `json:"roles,omitempty"`   // Defaults to a role named like the entity
+	Lookups  []string  `json:"lookups,omitempty"` // "free-text", "keywords" or both
+	Keywords []Keyword `json:"keywords,omitempty"`
+}
+
+// Entities returns the names of the entities defined in the app, including
+// built-in wit/ entities in use
+func (c *Client) Entities(ctx context.Context) ([]string, error) {
+	var entities []named
+	if err := c.do(ctx, http.MethodGet, "/entities", nil, &entities); err != nil {
+		return nil, err
+	}
+	return names(entities), nil
+}
+
+// CreateEntity adds an entity to the app
+func (c *Client) CreateEntity(ctx context.Context, entity Entity) error {
+	return c.do(ctx, http.MethodPost, "/entities", entity, nil)
+}
+
+// UpdateEntity replaces the definition of an existing entity
+func (c *Client) UpdateEntity(ctx context.Context, entity Entity) error {
+	return c.do(ctx, http.MethodPut, "/entities/"+url.PathEscape(entity.Name), entity, nil)
+}
+
+// DeleteEntity removes an entity from the app
+func (c *Client) DeleteEntity(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, "/entities/"+url.PathEscape(name), nil, nil)
+}
+
+// UtteranceEntity marks an entity inside an utterance's text. Start and End
+// are byte offsets into Utterance.Text.
+type UtteranceEntity struct {
+	Entity string `json:"entity"` // "name:role", e.g. "price:stop_loss"
+	Start  int    `json:"start"`
+	End    int    `json:"end"`
+	Body   string `json:"body"`
+
+	Entities []UtteranceEntity `json:"entities"`
+}
+
+// UtteranceTrait sets a trait value on an utterance
+type UtteranceTrait struct {
+	Trait string `json:"trait"`
+	Value string `json:"value"`
+}
+
+// Utterance is a labeled training example
+type Utterance struct {
+	Text     string            `json:"text"`
+	Intent   string            `json:"intent,omitempty"`
+	Entities []UtteranceEntity `json:"entities"`
+	Traits   []UtteranceTrait  `json:"traits"`
+}
+
+// Span returns an UtteranceEntity covering the first occurrence of body in
+// text, or false when body doesn't occur
+func Span(text, entity, body string) (UtteranceEntity, bool) {
+	start := strings.Index(text, body)
+	if start < 0 {
+		return UtteranceEntity{}, false
+	}
+	return UtteranceEntity{
+		Entity:   entity,
+		Start:    start,
+		End:      start + len(body),
+		Body:     body,
+		Entities: []UtteranceEntity{},
+	}, true
+}
+
+// TrainUtterances uploads labeled utterances. Wit.ai trains asynchronously.
+func (c *Client) TrainUtterances(ctx context.Context, utterances []Utterance) error {
+	for i := range utterances {
+		if utterances[i].Entities == nil {
+			utterances[i].Entities = []UtteranceEntity{}
+		}
+		if utterances[i].Traits == nil {
+			utterances[i].Traits = []UtteranceTrait{}
+		}
+	}
+	return c.do(ctx, http.MethodPost, "/utterances", utterances, nil)
+}
+
+// DeleteUtterances removes training utterances by text
+func (c *Client) DeleteUtterances(ctx context.Context, texts []string) error {
+	body := make([]struct {
+		Text string `json:"text"`
+	}, len(texts))
+	for i, text := range texts {
+		body[i].Text = text
+	}
+	return c.do(ctx, http.MethodDelete, "/utterances", body, nil)
+}
@@ -0,0 +1,173 @@
+package manage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/agatticelli/intent-go/witai"
+)
+
+// fakeApp is an in-memory Wit.ai app serving the management endpoints
+type fakeApp struct {
+	mu         sync.Mutex
+	intents    []string
+	entities   map[string]Entity
+	utterances []Utterance
+	requests   []string
+}
+
+func newFakeApp(t *testing.T) (*fakeApp, *Client) {
+	app := &fakeApp{entities: map[string]Entity{}}
+	server := httptest.NewServer(app)
+	t.Cleanup(server.Close)
+
+	c, err := New("server-token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	return app, c
+}
+
+func (a *fakeApp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.requests = append(a.requests, r.Method+" "+r.URL.Path)
+	if r.Header.Get("Authorization") != "Bearer server-token" || r.URL.Query().Get("v") == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": "Bad auth, check token/params", "code": "no-auth"}`))
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/intents":
+		var out []named
+		for _, name := range a.intents {
+			out = append(out, named{ID: "id-" + name, Name: name})
+		}
+		json.NewEncoder(w).Encode(out)
+
+	case r.Method == http.MethodPost && r.URL.Path == "/intents":
+		var in named
+		json.NewDecoder(r.Body).Decode(&in)
+		a.intents = append(a.intents, in.Name)
+		json.NewEncoder(w).Encode(named{ID: "id-" + in.Name, Name: in.Name})
+
+	case r.Method == http.MethodGet && r.URL.Path == "/entities":
+		var out []named
+		for name := range a.entities {
+			out = append(out, named{ID: "id-" + name, Name: name})
+		}
+		json.NewEncoder(w).Encode(out)
+
+	case r.Method == http.MethodPost && r.URL.Path == "/entities",
+		r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/entities/"):
+		var in Entity
+		json.NewDecoder(r.Body).Decode(&in)
+		a.entities[in.Name] = in
+		w.Write([]byte(`{}`))
+
+	case r.Method == http.MethodPost && r.URL.Path == "/utterances":
+		var in []Utterance
+		json.NewDecoder(r.Body).Decode(&in)
+		a.utterances = append(a.utterances, in...)
+		w.Write([]byte(`{"sent": true}`))
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSync(t *testing.T) {
+	app, c := newFakeApp(t)
+	app.intents = []string{"open_position", "greeting"}
+	app.entities["symbol"] = Entity{Name: "symbol"}
+
+	report, err := c.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Sync error: %v", err)
+	}
+
+	if slices.Contains(report.CreatedIntents, "open_position") {
+		t.Error("Sync recreated existing intent open_position")
+	}
+	if want := len(witai.CanonicalIntents()) - 1; len(report.CreatedIntents) != want {
+		t.Errorf("created %d intents, want %d", len(report.CreatedIntents), want)
+	}
+	if !slices.Contains(app.intents, "greeting") {
+		t.Error("Sync removed app-specific intent greeting")
+	}
+	for _, name := range witai.CanonicalIntents() {
+		if !slices.Contains(app.intents, name) {
+			t.Errorf("intent %s not provisioned", name)
+		}
+	}
+
+	if !slices.Equal(report.UpdatedEntities, []string{"symbol"}) {
+		t.Errorf("UpdatedEntities = %v, want [symbol]", report.UpdatedEntities)
+	}
+	if got := app.entities["price"].Roles; !slices.Equal(got, []string{"entry", "stop_loss", "take_profit"}) {
+		t.Errorf("price roles = %v", got)
+	}
+	if got := app.entities["symbol"].Roles; !slices.Equal(got, []string{"symbol"}) {
+		t.Errorf("symbol roles = %v, want default role", got)
+	}
+
+	// A second run has nothing to create
+	report, err = c.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("second Sync error: %v", err)
+	}
+	if len(report.CreatedIntents) != 0 || len(report.CreatedEntities) != 0 {
+		t.Errorf("second Sync created %v / %v, want nothing", report.CreatedIntents, report.CreatedEntities)
+	}
+}
+
+func TestTrainUtterances(t *testing.T) {
+	app, c := newFakeApp(t)
+
+	text := "long BTC with stop at 60000"
+	sl, ok := Span(text, "price:stop_loss", "60000")
+	if !ok {
+		t.Fatal("Span did not find 60000")
+	}
+	if sl.Start != 22 || sl.End != 27 {
+		t.Errorf("Span = [%d, %d), want [22, 27)", sl.Start, sl.End)
+	}
+
+	err := c.TrainUtterances(context.Background(), []Utterance{
+		{Text: text, Intent: "open_position", Entities: []UtteranceEntity{sl}},
+	})
+	if err != nil {
+		t.Fatalf("TrainUtterances error: %v", err)
+	}
+
+	if len(app.utterances) != 1 || app.utterances[0].Entities[0].Body != "60000" {
+		t.Errorf("utterances = %+v", app.utterances)
+	}
+	if app.utterances[0].Traits == nil {
+		t.Error("Traits sent as null, Wit.ai expects an array")
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	_, c := newFakeApp(t)
+	c.token = "wrong"
+
+	_, err := c.Intents(context.Background())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "Bad auth, check token/params" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+}
@@ -0,0 +1,64 @@
+package manage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/agatticelli/intent-go/witai"
+)
+
+// SyncReport lists what Sync changed in the app
+type SyncReport struct {
+	CreatedIntents  []string
+	CreatedEntities []string
+	UpdatedEntities []string
+}
+
+// Sync provisions the intents and entities the witai transformer
+// understands (witai.CanonicalIntents and witai.CanonicalEntities). Missing
+// ones are created and existing entities get their roles updated. Nothing
+// is deleted, so app-specific additions are kept.
+func (c *Client) Sync(ctx context.Context) (*SyncReport, error) {
+	report := &SyncReport{}
+
+	intents, err := c.Intents(ctx)
+	if err != nil {
+		return report, fmt.Errorf("listing intents: %w", err)
+	}
+	for _, name := range witai.CanonicalIntents() {
+		if slices.Contains(intents, name) {
+			continue
+		}
+		if err := c.CreateIntent(ctx, name); err != nil {
+			return report, fmt.Errorf("creating intent %s: %w", name, err)
+		}
+		report.CreatedIntents = append(report.CreatedIntents, name)
+	}
+
+	entities, err := c.Entities(ctx)
+	if err != nil {
+		return report, fmt.Errorf("listing entities: %w", err)
+	}
+	for _, spec := range witai.CanonicalEntities() {
+		entity := Entity{Name: spec.Name, Roles: spec.Roles}
+		if len(entity.Roles) == 0 {
+			entity.Roles = []string{spec.Name}
+		}
+
+		if !slices.Contains(entities, spec.Name) {
+			if err := c.CreateEntity(ctx, entity); err != nil {
+				return report, fmt.Errorf("creating entity %s: %w", spec.Name, err)
+			}
+			report.CreatedEntities = append(report.CreatedEntities, spec.Name)
+			continue
+		}
+
+		if err := c.UpdateEntity(ctx, entity); err != nil {
+			return report, fmt.Errorf("updating entity %s: %w", spec.Name, err)
+		}
+		report.UpdatedEntities = append(report.UpdatedEntities, spec.Name)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,103 @@
+package witai
+
+import (
+	"strconv"
+	"strings"
+)
+
+// decimalCommaLanguages write decimals with a comma and group thousands with
+// a dot ("45.000,5")
+var decimalCommaLanguages = map[string]bool{
+	"es": true, "pt": true, "fr": true, "de": true, "it": true, "nl": true, "tr": true, "ru": true,
+}
+
+// usesDecimalComma reports whether locale ("es_AR", "pt-BR", "en") writes
+// decimals with a comma
+func usesDecimalComma(locale string) bool {
+	lang, _, _ := strings.Cut(strings.ToLower(locale), "_")
+	lang, _, _ = strings.Cut(lang, "-")
+	return decimalCommaLanguages[lang]
+}
+
+// parseNumber parses a number as users type it: grouped ("45,000",
+// "45.000", "45 000"), with either decimal separator ("1.234,5") or in
+// scientific notation ("1e3"). When both separators appear the last one is
+// the decimal point; a lone separator followed by exactly three digits is
+// read as grouping only when it is the locale's grouping character, so
+// "45.000" is 45000 in Spanish and 45 in English. Numbers transcribed from
+// speech ("forty five thousand", "four four five zero zero") are read by
+// parseSpokenNumber.
+func parseNumber(s string, decimalComma bool) (float64, error) {
+	n, err := parseDigits(s, decimalComma)
+	if err != nil {
+		if spoken, spokenErr := parseSpokenNumber(s, decimalComma); spokenErr == nil {
+			return spoken, nil
+		}
+	}
+	return n, err
+}
+
+// parseDigits parses a number written in digits, see parseNumber
+func parseDigits(s string, decimalComma bool) (float64, error) {
+	s = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "", "_", "").Replace(strings.TrimSpace(s))
+
+	mantissa, exponent := s, ""
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa, exponent = s[:i], s[i:]
+	}
+
+	commas, dots := strings.Count(mantissa, ","), strings.Count(mantissa, ".")
+	switch {
+	case commas > 0 && dots > 0:
+		if strings.LastIndex(mantissa, ",") > strings.LastIndex(mantissa, ".") {
+			mantissa = strings.ReplaceAll(mantissa, ".", "")
+			mantissa = strings.Replace(mantissa, ",", ".", 1)
+		} else {
+			mantissa = strings.ReplaceAll(mantissa, ",", "")
+		}
+	case commas > 1:
+		mantissa = strings.ReplaceAll(mantissa, ",", "")
+	case dots > 1:
+		mantissa = strings.ReplaceAll(mantissa, ".", "")
+	case commas == 1:
+		if isGrouping(mantissa, ",") && !decimalComma {
+			mantissa = strings.Replace(mantissa, ",", "", 1)
+		} else {
+			mantissa = strings.Replace(mantissa, ",", ".", 1)
+		}
+	case dots == 1:
+		if isGrouping(mantissa, ".") && decimalComma {
+			mantissa = strings.Replace(mantissa, ".", "", 1)
+		}
+	}
+
+	return strconv.ParseFloat(mantissa+exponent, 64)
+}
+
+// dollarAffixes are the currency markers stripped by parseNotional
+var dollarAffixes = []string{"us$", "$", "usdt", "usdc", "usd", "dollars", "dollar", "dólares", "dolares", "bucks", "dls"}
+
+// parseNotional parses a dollar amount such as "$500", "500 USDT",
+// "1.5k dollars" or "2.000 dólares" (see parseNumber for separators)
+func parseNotional(s string, decimalComma bool) (float64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, affix := range dollarAffixes {
+		s = strings.TrimSpace(strings.TrimPrefix(s, affix))
+		s = strings.TrimSpace(strings.TrimSuffix(s, affix))
+	}
+
+	factor := 1.0
+	if trimmed, ok := strings.CutSuffix(s, "k"); ok {
+		s, factor = strings.TrimSpace(trimmed), 1000
+	}
+	n, err := parseNumber(s, decimalComma)
+	return n * factor, err
+}
+
+// isGrouping reports whether the single sep in s could be a thousands
+// separator: one to three digits before it, other than a lone zero, and
+// exactly three after
+func isGrouping(s, sep string) bool {
+	whole, frac, _ := strings.Cut(strings.TrimLeft(s, "+-"), sep)
+	return len(whole) >= 1 && len(whole) <= 3 && whole != "0" && len(frac) == 3
+}
@@ -0,0 +1,117 @@
+package witai
+
+import "testing"
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		input        string
+		decimalComma bool
+		want         float64
+	}{
+		{"45000", false, 45000},
+		{"45000.5", false, 45000.5},
+		{"1e3", false, 1000},
+		{"1.5E3", false, 1500},
+		{"45,000", false, 45000},
+		{"45,000.50", false, 45000.5},
+		{"1,234,567", false, 1234567},
+		{"45 000", false, 45000},
+		{"45.000", false, 45},
+		{"0,500", false, 0.5},
+		{"1,5", false, 1.5},
+		{"45.000", true, 45000},
+		{"45,000", true, 45},
+		{"1.234,5", true, 1234.5},
+		{"1.234.567", true, 1234567},
+		{"1,5", true, 1.5},
+		{"0.500", true, 0.5},
+		{"1,5e3", true, 1500},
+		{"-45,000", false, -45000},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNumber(tt.input, tt.decimalComma)
+		if err != nil {
+			t.Errorf("parseNumber(%q, %v) error: %v", tt.input, tt.decimalComma, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseNumber(%q, %v) = %v, want %v", tt.input, tt.decimalComma, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "abc", "45k"} {
+		if _, err := parseNumber(input, false); err == nil {
+			t.Errorf("parseNumber(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestUsesDecimalComma(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   bool
+	}{
+		{"", false},
+		{"en_US", false},
+		{"es", true},
+		{"es_AR", true},
+		{"pt-BR", true},
+	}
+
+	for _, tt := range tests {
+		if got := usesDecimalComma(tt.locale); got != tt.want {
+			t.Errorf("usesDecimalComma(%q) = %v, want %v", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestParseNotional(t *testing.T) {
+	tests := []struct {
+		input        string
+		decimalComma bool
+		want         float64
+	}{
+		{"$500", false, 500},
+		{"500 USDT", false, 500},
+		{"$ 1,250.50", false, 1250.5},
+		{"1.5k dollars", false, 1500},
+		{"$2k", false, 2000},
+		{"2.000 dólares", true, 2000},
+		{"US$300", false, 300},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNotional(tt.input, tt.decimalComma)
+		if err != nil || got != tt.want {
+			t.Errorf("parseNotional(%q) = (%v, %v), want %v", tt.input, got, err, tt.want)
+		}
+	}
+
+	if _, err := parseNotional("some dollars", false); err == nil {
+		t.Error("parseNotional(\"some dollars\") error = nil, want error")
+	}
+}
+
+func TestTransformWitResponseWith_GroupedNumbers(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "open_position", Confidence: 0.95}},
+		Entities: map[string][]WitAIEntity{
+			"entry_price": {{Value: "45.000"}},
+			"stop_loss":   {{Value: "44.500,5"}},
+		},
+	}
+
+	es := transformWitResponseWith(resp, "abrir largo BTC en 45.000 con stop 44.500,5", transformConfig{locale: "es_AR", quote: DefaultQuoteCurrency})
+	if es.EntryPrice == nil || *es.EntryPrice != 45000 {
+		t.Errorf("es EntryPrice = %v, want 45000", es.EntryPrice)
+	}
+	if es.StopLoss == nil || *es.StopLoss != 44500.5 {
+		t.Errorf("es StopLoss = %v, want 44500.5", es.StopLoss)
+	}
+
+	en := transformWitResponse(resp, "open long BTC at 45.000")
+	if en.EntryPrice == nil || *en.EntryPrice != 45 {
+		t.Errorf("en EntryPrice = %v, want 45", en.EntryPrice)
+	}
+}
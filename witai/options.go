@@ -0,0 +1,262 @@
+package witai
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/validators"
+)
+
+// Option configures a Processor
+type Option func(*Processor)
+
+// WithMetrics reports parse lifecycle measurements to sink
+func WithMetrics(sink intent.MetricsSink) Option {
+	return func(p *Processor) {
+		p.metrics = sink
+	}
+}
+
+// WithEvents publishes ParseStarted, BackendCalled and ValidationFailed,
+// CommandReady or ParseFailed on bus for every parse. Input is left out of ParseStarted
+// with WithRedactedInput.
+func WithEvents(bus *intent.EventBus) Option {
+	return func(p *Processor) {
+		p.events = bus
+	}
+}
+
+// WithPlugins replaces the plugins registered with intent.RegisterPlugin,
+// which are used by default. Call it without arguments to disable plugins.
+func WithPlugins(plugins ...intent.Plugin) Option {
+	return func(p *Processor) {
+		p.plugins = plugins
+	}
+}
+
+// WithLogger logs request/response summaries at debug level to h.
+// The bearer token is always masked; use WithRedactedInput to hide user input as well.
+func WithLogger(h slog.Handler) Option {
+	return func(p *Processor) {
+		p.logger = slog.New(h)
+	}
+}
+
+// WithRedactedInput replaces user input in log records with its length
+func WithRedactedInput() Option {
+	return func(p *Processor) {
+		p.redactInput = true
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client (10s timeout)
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Processor) {
+		p.client = client
+	}
+}
+
+// WithBaseURL points the processor at a different Wit.ai compatible endpoint,
+// e.g. a fake server in tests
+func WithBaseURL(baseURL string) Option {
+	return func(p *Processor) {
+		p.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithRecording persists every raw Wit.ai response under dir so it can be
+// served back later with WithReplay. It wraps the current client, so pass it
+// after WithHTTPClient.
+func WithRecording(dir string) Option {
+	return func(p *Processor) {
+		client := *p.client
+		client.Transport = NewRecordingTransport(dir, client.Transport)
+		p.client = &client
+	}
+}
+
+// WithReplay serves responses recorded with WithRecording from dir instead of
+// calling Wit.ai, so tests and demos run deterministically without a live token.
+// Pass it after WithHTTPClient.
+func WithReplay(dir string) Option {
+	return func(p *Processor) {
+		client := *p.client
+		client.Transport = NewReplayTransport(dir)
+		p.client = &client
+	}
+}
+
+// WithConcurrency bounds the number of concurrent Wit.ai calls made by
+// ParseCommands (default 4)
+func WithConcurrency(n int) Option {
+	return func(p *Processor) {
+		p.concurrency = n
+	}
+}
+
+// WithPolicy validates parsed commands with policy instead of validators.DefaultPolicy
+func WithPolicy(policy validators.Policy) Option {
+	return func(p *Processor) {
+		p.policy = policy
+	}
+}
+
+// WithValidators validates parsed commands with the validators in r
+// instead of the built-in ones, e.g. to enforce a minimum risk/reward on
+// open_position or to validate a custom intent
+func WithValidators(r *validators.Registry) Option {
+	return func(p *Processor) {
+		p.validators = r
+	}
+}
+
+// WithValidationRules runs rules after the validators on every parsed
+// command, e.g. a desk's maximum notional. Successive calls add rules.
+func WithValidationRules(rules ...validators.RuleFunc) Option {
+	return func(p *Processor) {
+		p.rules = append(p.rules, rules...)
+	}
+}
+
+// WithShadowPolicy validates every command with policy as well, without
+// enforcing it, and logs at info level where its outcome differs from the
+// enforced policy. Operators can tighten rules this way before rolling
+// them out with WithPolicy.
+func WithShadowPolicy(policy validators.Policy) Option {
+	return func(p *Processor) {
+		p.shadowPolicy = &policy
+	}
+}
+
+// WithExchangeAliases adds spoken exchange names to DefaultExchangeAliases,
+// e.g. {"my futures": "bybit"}. Keys are matched ignoring case, spaces,
+// hyphens and underscores.
+func WithExchangeAliases(aliases map[string]string) Option {
+	return func(p *Processor) {
+		merged := make(map[string]string, len(p.exchangeAliases)+len(aliases))
+		for alias, exchange := range p.exchangeAliases {
+			merged[alias] = exchange
+		}
+		for alias, exchange := range aliases {
+			merged[exchangeKey(alias)] = exchange
+		}
+		p.exchangeAliases = merged
+	}
+}
+
+// WithQuoteCurrency sets the quote currency for symbols given without one,
+// e.g. "USDC" turns "BTC" into "BTC-USDC" (default DefaultQuoteCurrency).
+// An explicit quote in the input ("BTC against USD") still wins. New fails
+// when quote is empty.
+func WithQuoteCurrency(quote string) Option {
+	return func(p *Processor) {
+		p.quoteCurrency = strings.ToUpper(strings.TrimSpace(quote))
+	}
+}
+
+// WithSymbolRules adds per-deployment symbol overrides, e.g.
+// {Match: "gold", Symbol: "PAXG-USDT"}. Rules win over the built-in aliases
+// and the quote currency; New fails if two rules for the same asset class
+// map one name to different symbols.
+func WithSymbolRules(rules ...SymbolRule) Option {
+	return func(p *Processor) {
+		p.symbolRules = append(p.symbolRules, rules...)
+	}
+}
+
+// WithAssetClass sets the deployment's asset class, e.g. "forex". Symbol
+// rules for that class take precedence over rules without one.
+func WithAssetClass(class string) Option {
+	return func(p *Processor) {
+		p.assetClass = class
+	}
+}
+
+// WithTraits sets the Wit.ai traits copied into NormalizedCommand.Traits
+// (default DefaultTraits), e.g. "urgency" and "wit$sentiment". Without
+// arguments no traits are mapped.
+func WithTraits(names ...string) Option {
+	return func(p *Processor) {
+		p.traits = append([]string(nil), names...)
+	}
+}
+
+// WithMaxInputLength parses only the actionable part of inputs longer than
+// n characters (see intent.ExtractActionable) and keeps the rest in the
+// command's Note. Wit.ai rejects messages over 280 characters, so pasted
+// analyses ending in a command otherwise fail outright.
+func WithMaxInputLength(n int) Option {
+	return func(p *Processor) {
+		p.maxInputLength = n
+	}
+}
+
+// WithCompoundCommands splits inputs that chain several commands, e.g.
+// "open long BTC at 45000 and set an alert at 47000", when Wit.ai ranks a
+// second, different intent at min confidence or above. Each clause is then
+// parsed with its own call and returned in the command's SubCommands; if any
+// clause doesn't resolve to a known intent the whole input is kept as one
+// command. Disabled by default.
+func WithCompoundCommands(min float64) Option {
+	return func(p *Processor) {
+		p.compoundMin = min
+	}
+}
+
+// WithMinConfidence rewrites results whose intent confidence is below min to
+// intent.IntentUnknown, with an error explaining the downgrade. Panic closes
+// are kept since they always require confirmation.
+func WithMinConfidence(min float64) Option {
+	return func(p *Processor) {
+		p.minConfidence = min
+	}
+}
+
+// WithDecodeMode sets how responses with unknown fields are handled
+// (default DecodeTolerant). Anomalies are counted when the metrics sink
+// implements intent.DecodeAnomalySink.
+func WithDecodeMode(mode DecodeMode) Option {
+	return func(p *Processor) {
+		p.decodeMode = mode
+	}
+}
+
+// WithProfiling runs the call, transform and validate stages of each parse
+// under pprof labels (processor, stage, intent) and runtime/trace regions, so
+// CPU profiles attribute time to specific intents
+func WithProfiling() Option {
+	return func(p *Processor) {
+		p.profiling = true
+	}
+}
+
+// WithClock sets the clock used for command timestamps, event times and
+// metric latencies (default intent.SystemClock)
+func WithClock(clock intent.Clock) Option {
+	return func(p *Processor) {
+		p.clock = clock
+	}
+}
+
+// WithIDGenerator sets the generator for command IDs (default intent.RandomIDs)
+func WithIDGenerator(ids intent.IDGenerator) Option {
+	return func(p *Processor) {
+		p.ids = ids
+	}
+}
+
+// WithDeterministic configures a reproducible processor for replay-based
+// backtests: responses are replayed from fixtureDir (no network), the clock
+// starts at start and advances one second on every reading, so timestamps,
+// event times and latencies repeat from run to run, and IDs are sequential.
+// Pass it after WithHTTPClient.
+func WithDeterministic(fixtureDir string, start time.Time) Option {
+	return func(p *Processor) {
+		WithReplay(fixtureDir)(p)
+		p.clock = intent.NewStepClock(start, time.Second)
+		p.ids = &intent.SequentialIDs{Prefix: "cmd-"}
+	}
+}
@@ -0,0 +1,30 @@
+package witai
+
+import (
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// stage runs fn as a named parse stage. With WithProfiling, fn runs under
+// pprof labels (processor, stage and, once known, intent) and inside a
+// runtime/trace region named "witai.<stage>".
+func (p *Processor) stage(ctx context.Context, name string, cmdIntent intent.Intent, fn func(context.Context)) {
+	if !p.profiling {
+		fn(ctx)
+		return
+	}
+
+	labels := []string{"processor", p.Name(), "stage", name}
+	if cmdIntent != "" {
+		labels = append(labels, "intent", string(cmdIntent))
+	}
+
+	pprof.Do(ctx, pprof.Labels(labels...), func(ctx context.Context) {
+		trace.WithRegion(ctx, "witai."+name, func() {
+			fn(ctx)
+		})
+	})
+}
@@ -0,0 +1,39 @@
+package witai
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/agatticelli/intent-go"
+)
+
+func TestStage_Labels(t *testing.T) {
+	p, _ := New("token", WithProfiling())
+
+	var stage, cmdIntent, processor string
+	p.stage(context.Background(), "validate", intent.IntentOpenPosition, func(ctx context.Context) {
+		stage, _ = pprof.Label(ctx, "stage")
+		cmdIntent, _ = pprof.Label(ctx, "intent")
+		processor, _ = pprof.Label(ctx, "processor")
+	})
+
+	if stage != "validate" || cmdIntent != "open_position" || processor != "witai" {
+		t.Errorf("labels = stage %q, intent %q, processor %q", stage, cmdIntent, processor)
+	}
+}
+
+func TestStage_Disabled(t *testing.T) {
+	p, _ := New("token")
+
+	called := false
+	p.stage(context.Background(), "call", "", func(ctx context.Context) {
+		called = true
+		if _, ok := pprof.Label(ctx, "stage"); ok {
+			t.Error("stage label set without WithProfiling")
+		}
+	})
+	if !called {
+		t.Error("stage did not run fn")
+	}
+}
@@ -0,0 +1,41 @@
+package witai_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/witai"
+	"github.com/agatticelli/intent-go/witai/witaitest"
+)
+
+func TestParseCommand_Progress(t *testing.T) {
+	s := witaitest.NewServer()
+	defer s.Close()
+	s.RespondIntent("show my positions", "view_positions", 0.95, nil)
+	s.SetLatency(40 * time.Millisecond)
+
+	p, err := witai.New(witaitest.Token, witai.WithBaseURL(s.URL))
+	if err != nil {
+		t.Fatalf("witai.New error: %v", err)
+	}
+
+	var calls atomic.Int32
+	ctx := intent.WithParseOptions(context.Background(), intent.WithProgress(10*time.Millisecond, func(time.Duration) {
+		calls.Add(1)
+	}))
+	if _, err := p.ParseCommand(ctx, "show my positions"); err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	got := calls.Load()
+	if got == 0 {
+		t.Fatal("progress callback was never called during a slow call")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if calls.Load() != got {
+		t.Error("progress callback called after ParseCommand returned")
+	}
+}
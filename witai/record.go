@@ -0,0 +1,152 @@
+package witai
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// ErrFixtureNotFound is returned by the replay transport when no recorded
+// response matches a request
+var ErrFixtureNotFound = errors.New("wit.ai fixture not found")
+
+// fixture is the on-disk representation of a recorded Wit.ai exchange
+type fixture struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query"`
+	Status int    `json:"status"`
+	Body   string `json:"body"`
+}
+
+// fixtureKey identifies a request independently of the bearer token. The
+// body is part of the key, so /speech requests with different audio get
+// fixtures of their own.
+func fixtureKey(req *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.URL.Path + "?" + req.URL.Query().Encode()))
+	if len(body) > 0 {
+		h.Write([]byte{0})
+		h.Write(body)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// fixturePath returns the fixture file of req, buffering its body to key it
+// and restoring req.Body so the request can still be sent
+func fixturePath(dir string, req *http.Request) (string, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+	}
+	return filepath.Join(dir, fixtureKey(req, body)+".json"), nil
+}
+
+type recordingTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+// NewRecordingTransport returns a RoundTripper that forwards requests to next
+// and persists every raw response under dir, keyed by method, path, query
+// and request body
+func NewRecordingTransport(dir string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &recordingTransport{dir: dir, next: next}
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path, err := fixturePath(t.dir, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	data, err := json.MarshalIndent(fixture{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+		Status: resp.StatusCode,
+		Body:   string(body),
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create fixture dir: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return nil, fmt.Errorf("write fixture: %w", err)
+	}
+
+	return resp, nil
+}
+
+type replayTransport struct {
+	dir string
+}
+
+// NewReplayTransport returns a RoundTripper that serves responses previously
+// persisted by NewRecordingTransport, without any network access
+func NewReplayTransport(dir string) http.RoundTripper {
+	return &replayTransport{dir: dir}
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path, err := fixturePath(t.dir, req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s?%s", ErrFixtureNotFound, req.Method, req.URL.Path, req.URL.Query().Encode())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("decode fixture: %w", err)
+	}
+
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode: f.Status,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(f.Body)),
+		Request:    req,
+	}, nil
+}
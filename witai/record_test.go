@@ -0,0 +1,152 @@
+package witai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+)
+
+const positionsResponse = `{
+	"text": "show my positions",
+	"intents": [{"id": "1", "name": "view_positions", "confidence": 0.97}],
+	"entities": {},
+	"traits": {}
+}`
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(positionsResponse))
+	}))
+	defer server.Close()
+
+	recorder, err := New("live-token", WithBaseURL(server.URL), WithRecording(dir))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	recorded, err := recorder.ParseCommand(ctx, "show my positions")
+	if err != nil {
+		t.Fatalf("recording ParseCommand error: %v", err)
+	}
+
+	// Replay must not touch the network, so point it at the closed server
+	server.Close()
+
+	replayer, err := New("any-token", WithBaseURL(server.URL), WithReplay(dir))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	replayed, err := replayer.ParseCommand(ctx, "show my positions")
+	if err != nil {
+		t.Fatalf("replay ParseCommand error: %v", err)
+	}
+
+	if hits != 1 {
+		t.Errorf("server hits = %d, want 1", hits)
+	}
+	if replayed.Intent != intent.IntentViewPositions || replayed.Intent != recorded.Intent {
+		t.Errorf("replayed Intent = %v, recorded %v", replayed.Intent, recorded.Intent)
+	}
+	if replayed.Confidence != recorded.Confidence {
+		t.Errorf("replayed Confidence = %v, recorded %v", replayed.Confidence, recorded.Confidence)
+	}
+}
+
+func TestRecordAndReplay_SpeechKeyedByAudio(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	transcripts := map[string]string{"clip-close": "close BTC", "clip-balance": "check balance"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		audio, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, `{"text": %q, "is_final": true, "intents": [], "entities": {}, "traits": {}}`, transcripts[string(audio)])
+	}))
+	defer server.Close()
+
+	recorder, _ := New("live-token", WithBaseURL(server.URL), WithRecording(dir))
+	for audio := range transcripts {
+		if _, err := recorder.ParseSpeech(ctx, strings.NewReader(audio), "audio/ogg"); err != nil {
+			t.Fatalf("recording ParseSpeech(%s) error: %v", audio, err)
+		}
+	}
+	server.Close()
+
+	replayer, _ := New("any-token", WithBaseURL(server.URL), WithReplay(dir))
+	for audio, want := range transcripts {
+		cmd, err := replayer.ParseSpeech(ctx, strings.NewReader(audio), "audio/ogg")
+		if err != nil {
+			t.Fatalf("replay ParseSpeech(%s) error: %v", audio, err)
+		}
+		if cmd.RawInput != want {
+			t.Errorf("replayed %s RawInput = %q, want %q", audio, cmd.RawInput, want)
+		}
+	}
+	if _, err := replayer.ParseSpeech(ctx, strings.NewReader("clip-other"), "audio/ogg"); !errors.Is(err, ErrFixtureNotFound) {
+		t.Errorf("unrecorded clip error = %v, want ErrFixtureNotFound", err)
+	}
+}
+
+func TestReplay_MissingFixture(t *testing.T) {
+	p, _ := New("any-token", WithReplay(t.TempDir()))
+
+	_, err := p.ParseCommand(context.Background(), "close BTC")
+	if !errors.Is(err, ErrFixtureNotFound) {
+		t.Errorf("error = %v, want ErrFixtureNotFound", err)
+	}
+}
+
+func TestWithDeterministic_ReproducibleStream(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(positionsResponse))
+	}))
+	recorder, _ := New("live-token", WithBaseURL(server.URL), WithRecording(dir))
+	recorder.ParseCommand(ctx, "show my positions")
+	server.Close()
+
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	run := func() []*intent.NormalizedCommand {
+		p, _ := New("replay", WithBaseURL(server.URL), WithDeterministic(dir, start))
+		var cmds []*intent.NormalizedCommand
+		for i := 0; i < 3; i++ {
+			cmd, err := p.ParseCommand(ctx, "show my positions")
+			if err != nil {
+				t.Fatalf("ParseCommand error: %v", err)
+			}
+			cmds = append(cmds, cmd)
+		}
+		return cmds
+	}
+
+	first, second := run(), run()
+	for i := range first {
+		if first[i].ID != second[i].ID || !first[i].Timestamp.Equal(second[i].Timestamp) {
+			t.Errorf("command %d differs between runs: (%s, %v) vs (%s, %v)",
+				i, first[i].ID, first[i].Timestamp, second[i].ID, second[i].Timestamp)
+		}
+	}
+
+	if first[0].ID != "cmd-1" || first[0].Timestamp.Before(start) {
+		t.Errorf("first command = (%s, %v), want (cmd-1, from %v)", first[0].ID, first[0].Timestamp, start)
+	}
+	for i := 1; i < len(first); i++ {
+		if !first[i].Timestamp.After(first[i-1].Timestamp) {
+			t.Errorf("Timestamp %d = %v, want after %v", i, first[i].Timestamp, first[i-1].Timestamp)
+		}
+	}
+}
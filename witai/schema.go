@@ -0,0 +1,119 @@
+package witai
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// witIntents maps Wit.ai intent names to our Intent enum
+var witIntents = map[string]intent.Intent{
+	"open_position":  intent.IntentOpenPosition,
+	"close_position": intent.IntentClosePosition,
+	"view_positions": intent.IntentViewPositions,
+	"view_orders":    intent.IntentViewOrders,
+	"cancel_orders":  intent.IntentCancelOrders,
+	"check_balance":  intent.IntentCheckBalance,
+	"break_even":     intent.IntentBreakEven,
+	"trailing_stop":  intent.IntentTrailingStop,
+	"copy_trade":     intent.IntentCopyTrade,
+	"setup_grid":     intent.IntentSetupGrid,
+	"view_alerts":    intent.IntentViewAlerts,
+	"cancel_alert":   intent.IntentCancelAlert,
+
+	"set_risk_defaults": intent.IntentSetRiskDefaults,
+	"withdraw":          intent.IntentWithdraw,
+	"modify_position":   intent.IntentModifyPosition,
+	"set_leverage":      intent.IntentSetLeverage,
+	"dca_order":         intent.IntentDCAOrder,
+	"set_alert":         intent.IntentSetAlert,
+	"view_pnl":          intent.IntentViewPnL,
+	"view_price":        intent.IntentViewPrice,
+	"view_funding":      intent.IntentViewFunding,
+	"hedge_position":    intent.IntentHedgePosition,
+	"move_stop_loss":    intent.IntentMoveStopLoss,
+	"view_history":      intent.IntentViewHistory,
+
+	"check_order_status": intent.IntentCheckOrderStatus,
+	"view_position":      intent.IntentViewPosition,
+	"panic_close":        intent.IntentPanicClose,
+	"set_take_profit":    intent.IntentSetTakeProfit,
+	"set_stop_loss":      intent.IntentSetStopLoss,
+	"help":               intent.IntentHelp,
+	"close_all":          intent.IntentCloseAll,
+}
+
+// EntitySpec describes a Wit.ai entity the transformer reads
+type EntitySpec struct {
+	Name  string
+	Roles []string // Empty means the default role, named like the entity
+}
+
+// witEntities lists the entities handled by transformWitResponse. Built-in
+// entities such as wit$datetime are left out since they can't be provisioned.
+var witEntities = []EntitySpec{
+	{Name: "symbol"},
+	{Name: "exclude_symbol"},
+	{Name: "side"},
+	{Name: "quote_currency"},
+	{Name: "exchange"},
+	{Name: "account"},
+	{Name: "order_type"},
+	{Name: "time_in_force"},
+	{Name: "expires_at"},
+	{Name: "reduce_only"},
+	{Name: "margin_mode"},
+	{Name: "entry_price"},
+	{Name: "stop_loss"},
+	{Name: "take_profit"},
+	{Name: "price", Roles: []string{"entry", "stop_loss", "take_profit"}},
+	{Name: "risk"},
+	{Name: "leverage"},
+	{Name: "quantity"},
+	{Name: "notional"},
+	{Name: "close_percent"},
+	{Name: "trigger_price"},
+	{Name: "callback_rate"},
+	{Name: "source_account"},
+	{Name: "target_account"},
+	{Name: "size_factor"},
+	{Name: "hedge_ratio"},
+	{Name: "stop_loss_offset"},
+	{Name: "levels"},
+	{Name: "sl_levels"},
+	{Name: "entry_levels"},
+	{Name: "condition_symbol"},
+	{Name: "condition_operator"},
+	{Name: "condition_price"},
+	{Name: "grid_range"},
+	{Name: "grid_lower"},
+	{Name: "grid_upper"},
+	{Name: "grid_levels"},
+	{Name: "grid_level_size"},
+	{Name: "order_id"},
+	{Name: "scope"},
+	{Name: "alert_id"},
+	{Name: "alert_price"},
+	{Name: "alert_direction"},
+	{Name: "asset"},
+	{Name: "amount"},
+	{Name: "address_ref"},
+	{Name: "period"},
+	{Name: "timeframe"},
+}
+
+// CanonicalIntents returns the sorted Wit.ai intent names the transformer
+// understands, so the Wit.ai app can be provisioned to match
+func CanonicalIntents() []string {
+	return slices.Sorted(maps.Keys(witIntents))
+}
+
+// CanonicalEntities returns the Wit.ai entities the transformer reads
+func CanonicalEntities() []EntitySpec {
+	specs := make([]EntitySpec, len(witEntities))
+	for i, spec := range witEntities {
+		specs[i] = EntitySpec{Name: spec.Name, Roles: slices.Clone(spec.Roles)}
+	}
+	return specs
+}
@@ -0,0 +1,139 @@
+package witai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// ParseSpeech transcribes and parses a voice command using Wit.ai's /speech
+// endpoint. contentType is the audio MIME type, e.g. "audio/ogg" or
+// "audio/mpeg3". The returned command's RawInput is the transcription.
+func (p *Processor) ParseSpeech(ctx context.Context, audio io.Reader, contentType string) (*intent.NormalizedCommand, error) {
+	start := p.clock.Now()
+	p.metrics.ParseStarted(p.Name())
+	p.events.Publish(intent.ParseStarted{
+		Processor: p.Name(),
+		UserID:    intent.UserIDFromContext(ctx),
+		Time:      start,
+	})
+
+	callCtx, cancel := intent.ApplyTimeout(ctx)
+	defer cancel()
+
+	var witResp *WitAIResponse
+	var err error
+	callStart := p.clock.Now()
+	p.stage(callCtx, "speech", "", func(ctx context.Context) {
+		stop := intent.StartProgress(ctx)
+		defer stop()
+		witResp, err = p.callWitSpeech(ctx, audio, contentType)
+	})
+	p.events.Publish(intent.BackendCalled{
+		Processor: p.Name(),
+		UserID:    intent.UserIDFromContext(ctx),
+		Latency:   p.since(callStart),
+		Err:       err,
+		Time:      p.clock.Now(),
+	})
+	if err != nil {
+		err = fmt.Errorf("wit.ai speech call failed: %w", intent.TimeoutError(callCtx, err))
+		p.metrics.ParseFailed(p.Name(), err, p.since(start))
+		p.events.Publish(intent.ParseFailed{
+			Processor: p.Name(),
+			UserID:    intent.UserIDFromContext(ctx),
+			Err:       err,
+			Time:      p.clock.Now(),
+		})
+		return nil, err
+	}
+
+	cmd := p.buildCommand(ctx, witResp, witResp.Text)
+
+	p.metrics.ParseSucceeded(p.Name(), cmd.Intent, cmd.Confidence, p.since(start))
+	return cmd, nil
+}
+
+// callWitSpeech streams audio to Wit.ai and returns the final understanding
+func (p *Processor) callWitSpeech(ctx context.Context, audio io.Reader, contentType string) (*WitAIResponse, error) {
+	apiURL := p.baseURL + "/speech"
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, audio)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("v", apiVersion)
+	if err := addParseOptions(q, intent.ParseOptionsFromContext(ctx)); err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = q.Encode()
+
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Content-Type", contentType)
+
+	p.logger.DebugContext(ctx, "wit.ai speech request",
+		slog.String("user_id", intent.UserIDFromContext(ctx)),
+		slog.String("url", apiURL),
+		slog.String("token", redactToken(p.token)),
+		slog.String("content_type", contentType),
+	)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("wit.ai returned status %d", resp.StatusCode)
+	}
+
+	witResp, err := p.decodeResponse(ctx, resp.Body, decodeSpeechStream)
+	if err != nil {
+		return nil, err
+	}
+
+	p.logger.DebugContext(ctx, "wit.ai speech response",
+		append([]any{p.inputAttr(witResp.Text)}, responseAttrs(witResp)...)...)
+
+	return witResp, nil
+}
+
+// decodeSpeechStream reads the sequence of JSON objects returned by /speech
+// (partial transcriptions followed by the final understanding) and returns
+// the final one, or the last one when none is marked final
+func decodeSpeechStream(r io.Reader, strict bool) (*WitAIResponse, error) {
+	dec := json.NewDecoder(r)
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+
+	var last *WitAIResponse
+	for {
+		var chunk WitAIResponse
+		err := dec.Decode(&chunk)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		last = &chunk
+		if chunk.IsFinal && chunk.Intents != nil {
+			return last, nil
+		}
+	}
+
+	if last == nil {
+		return nil, errors.New("wit.ai speech response was empty")
+	}
+	return last, nil
+}
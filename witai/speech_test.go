@@ -0,0 +1,66 @@
+package witai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/intent-go"
+)
+
+const speechStream = `{"text": "close", "is_final": false}
+{"text": "close BTC", "is_final": false}
+{
+  "text": "close BTC",
+  "is_final": true,
+  "intents": [{"id": "1", "name": "close_position", "confidence": 0.94}],
+  "entities": {"symbol": [{"value": "btc", "confidence": 0.9}]},
+  "traits": {}
+}
+`
+
+func TestParseSpeech(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/speech" {
+			t.Errorf("request = %s %s, want POST /speech", r.Method, r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "audio/ogg" {
+			t.Errorf("Content-Type = %q, want audio/ogg", ct)
+		}
+		if body, _ := io.ReadAll(r.Body); string(body) != "OggS-voice-note" {
+			t.Errorf("audio body = %q, want the uploaded audio", body)
+		}
+		w.Write([]byte(speechStream))
+	}))
+	defer server.Close()
+
+	p, _ := New("token", WithBaseURL(server.URL))
+	var _ intent.SpeechProcessor = p
+
+	cmd, err := p.ParseSpeech(context.Background(), strings.NewReader("OggS-voice-note"), "audio/ogg")
+	if err != nil {
+		t.Fatalf("ParseSpeech error: %v", err)
+	}
+
+	if cmd.Intent != intent.IntentClosePosition {
+		t.Errorf("Intent = %v, want %v", cmd.Intent, intent.IntentClosePosition)
+	}
+	if cmd.Symbol != "BTC-USDT" {
+		t.Errorf("Symbol = %q, want %q", cmd.Symbol, "BTC-USDT")
+	}
+	if cmd.RawInput != "close BTC" {
+		t.Errorf("RawInput = %q, want the transcription %q", cmd.RawInput, "close BTC")
+	}
+	if !cmd.Valid {
+		t.Errorf("Valid = false, errors: %v missing: %v", cmd.Errors, cmd.Missing)
+	}
+}
+
+func TestDecodeSpeechStream_Empty(t *testing.T) {
+	if _, err := decodeSpeechStream(strings.NewReader(""), false); err == nil {
+		t.Error("expected error for empty stream")
+	}
+}
@@ -0,0 +1,36 @@
+package witai_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agatticelli/intent-go/witai"
+	"github.com/agatticelli/intent-go/witai/witaitest"
+)
+
+func TestParseCommand_SpelledTicker(t *testing.T) {
+	s := witaitest.NewServer()
+	defer s.Close()
+	s.RespondIntent("open long BTC", "open_position", 0.95, map[string]string{"side": "long", "symbol": "BTC"})
+
+	p, err := witai.New(witaitest.Token, witai.WithBaseURL(s.URL))
+	if err != nil {
+		t.Fatalf("witai.New error: %v", err)
+	}
+
+	input := "open long b t c"
+	cmd, err := p.ParseCommand(context.Background(), input)
+	if err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	if requests := s.Requests(); len(requests) != 1 || requests[0].Query != "open long BTC" {
+		t.Fatalf("requests = %+v, want the query with the ticker joined", requests)
+	}
+	if cmd.Symbol != "BTC-USDT" {
+		t.Errorf("Symbol = %q, want BTC-USDT", cmd.Symbol)
+	}
+	if cmd.RawInput != input {
+		t.Errorf("RawInput = %q, want the transcript as given", cmd.RawInput)
+	}
+}
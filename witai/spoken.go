@@ -0,0 +1,204 @@
+package witai
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// errNotSpoken is returned by parseSpokenNumber for text it can't read;
+// parseNumber then reports the error of reading it as digits
+var errNotSpoken = errors.New("not a spoken number")
+
+// wordKind says where a number word sits in a spoken number
+type wordKind int
+
+const (
+	kindUnit     wordKind = iota // 0-9
+	kindTeen                     // 10-19, and complete values like "veinticinco" or "45"
+	kindTens                     // 20, 30, ... 90
+	kindHundreds                 // Spanish "ciento", "doscientos", ... added at the hundreds place
+	kindHundred                  // English "hundred", multiplying what precedes it
+	kindScale                    // thousand, million
+	kindPoint                    // decimal point
+	kindFiller                   // "and", "y", currency words
+)
+
+type numberWord struct {
+	kind  wordKind
+	value float64
+}
+
+// numberWords are the English and Spanish words read by parseSpokenNumber
+var numberWords = func() map[string]numberWord {
+	words := map[string]numberWord{
+		"hundred": {kindHundred, 100}, "thousand": {kindScale, 1e3}, "million": {kindScale, 1e6},
+		"mil": {kindScale, 1e3}, "millón": {kindScale, 1e6}, "millon": {kindScale, 1e6},
+		"millones": {kindScale, 1e6},
+		"point":    {kindPoint, 0}, "punto": {kindPoint, 0}, "coma": {kindPoint, 0},
+		"cien": {kindHundreds, 100}, "ciento": {kindHundreds, 100},
+		"a": {kindUnit, 1}, "oh": {kindUnit, 0},
+	}
+	add := func(kind wordKind, value float64, names ...string) {
+		for _, name := range names {
+			words[name] = numberWord{kind, value}
+		}
+	}
+	units := [][]string{
+		{"zero", "cero"}, {"one", "uno", "un", "una"}, {"two", "dos"}, {"three", "tres"},
+		{"four", "cuatro"}, {"five", "cinco"}, {"six", "seis"}, {"seven", "siete"},
+		{"eight", "ocho"}, {"nine", "nueve"},
+	}
+	for i, names := range units {
+		add(kindUnit, float64(i), names...)
+	}
+	teens := [][]string{
+		{"ten", "diez"}, {"eleven", "once"}, {"twelve", "doce"}, {"thirteen", "trece"},
+		{"fourteen", "catorce"}, {"fifteen", "quince"}, {"sixteen", "dieciséis", "dieciseis"},
+		{"seventeen", "diecisiete"}, {"eighteen", "dieciocho"}, {"nineteen", "diecinueve"},
+	}
+	for i, names := range teens {
+		add(kindTeen, float64(10+i), names...)
+	}
+	tens := [][]string{
+		{"twenty", "veinte"}, {"thirty", "treinta"}, {"forty", "cuarenta"}, {"fifty", "cincuenta"},
+		{"sixty", "sesenta"}, {"seventy", "setenta"}, {"eighty", "ochenta"}, {"ninety", "noventa"},
+	}
+	for i, names := range tens {
+		add(kindTens, float64(20+10*i), names...)
+	}
+	veinti := [][]string{
+		{"veintiuno", "veintiún", "veintiun", "veintiuna"}, {"veintidós", "veintidos"}, {"veintitrés", "veintitres"},
+		{"veinticuatro"}, {"veinticinco"}, {"veintiséis", "veintiseis"}, {"veintisiete"}, {"veintiocho"}, {"veintinueve"},
+	}
+	for i, names := range veinti {
+		add(kindTeen, float64(21+i), names...)
+	}
+	hundreds := [][]string{
+		{"doscientos", "doscientas"}, {"trescientos", "trescientas"}, {"cuatrocientos", "cuatrocientas"},
+		{"quinientos", "quinientas"}, {"seiscientos", "seiscientas"}, {"setecientos", "setecientas"},
+		{"ochocientos", "ochocientas"}, {"novecientos", "novecientas"},
+	}
+	for i, names := range hundreds {
+		add(kindHundreds, float64(200+100*i), names...)
+	}
+	add(kindFiller, 0, "and", "y", "de", "dollars", "dollar", "bucks", "usd", "usdt", "dólares", "dolares")
+	return words
+}()
+
+// parseSpokenNumber reads numbers as speech transcription writes them:
+// words ("forty five thousand dollars", "cuarenta y cinco mil"), digits
+// mixed with words ("45 thousand"), and digit-by-digit or grouped readings
+// ("four four five zero zero", "forty four five hundred"), which are
+// joined as written, giving 44500 for both
+func parseSpokenNumber(s string, decimalComma bool) (float64, error) {
+	tokens := strings.Fields(strings.ReplaceAll(strings.ToLower(s), "-", " "))
+
+	var (
+		chunks   []string // Values read one after the other, joined as digits
+		total    float64  // Scaled groups of the current chunk ("45 thousand")
+		cur      float64  // Group below the next scale word
+		last     = kindFiller
+		started  bool
+		fraction string
+		inFrac   bool
+	)
+	flush := func() error {
+		if !started {
+			return nil
+		}
+		value := total + cur
+		if value != float64(int64(value)) {
+			return errNotSpoken
+		}
+		chunks = append(chunks, strconv.FormatInt(int64(value), 10))
+		total, cur, last, started = 0, 0, kindFiller, false
+		return nil
+	}
+
+	for _, token := range tokens {
+		token = strings.TrimPrefix(token, "$")
+		word, ok := numberWords[token]
+		if !ok {
+			n, err := parseDigits(token, decimalComma)
+			if err != nil {
+				return 0, errNotSpoken
+			}
+			word = numberWord{kindTeen, n}
+		}
+
+		if inFrac {
+			// Digits after the point are read one by one, "point two five"
+			switch word.kind {
+			case kindUnit, kindTeen, kindTens:
+				if word.value != float64(int64(word.value)) {
+					return 0, errNotSpoken
+				}
+				fraction += strconv.FormatInt(int64(word.value), 10)
+			case kindFiller:
+			default:
+				return 0, errNotSpoken
+			}
+			continue
+		}
+
+		switch word.kind {
+		case kindFiller:
+			continue
+		case kindPoint:
+			if err := flush(); err != nil {
+				return 0, err
+			}
+			inFrac = true
+			continue
+		case kindUnit:
+			if started && last != kindTens && last != kindHundred && last != kindHundreds && last != kindScale {
+				if err := flush(); err != nil {
+					return 0, err
+				}
+			}
+			cur += word.value
+		case kindTeen, kindTens:
+			if started && last != kindHundred && last != kindHundreds && last != kindScale {
+				if err := flush(); err != nil {
+					return 0, err
+				}
+			}
+			cur += word.value
+		case kindHundreds:
+			if started && last != kindScale {
+				if err := flush(); err != nil {
+					return 0, err
+				}
+			}
+			cur += word.value
+		case kindHundred:
+			if cur == 0 {
+				cur = 1
+			}
+			cur *= word.value
+		case kindScale:
+			if cur == 0 {
+				cur = 1
+			}
+			total += cur * word.value
+			cur = 0
+		}
+		last, started = word.kind, true
+	}
+	if err := flush(); err != nil {
+		return 0, err
+	}
+	if len(chunks) == 0 {
+		return 0, errNotSpoken
+	}
+
+	number := strings.Join(chunks, "")
+	if inFrac {
+		if fraction == "" {
+			return 0, errNotSpoken
+		}
+		number += "." + fraction
+	}
+	return strconv.ParseFloat(number, 64)
+}
@@ -0,0 +1,77 @@
+package witai
+
+import "testing"
+
+// Inputs are shaped like Whisper and Wit.ai /speech transcriptions of
+// spoken prices and sizes
+func TestParseNumber_Spoken(t *testing.T) {
+	tests := []struct {
+		input        string
+		decimalComma bool
+		want         float64
+	}{
+		{"forty five thousand dollars", false, 45000},
+		{"forty-five thousand", false, 45000},
+		{"forty four thousand five hundred", false, 44500},
+		{"forty four five hundred", false, 44500},
+		{"four four five zero zero", false, 44500},
+		{"44 500", false, 44500},
+		{"$44,500", false, 44500},
+		{"45 thousand", false, 45000},
+		{"1.5 million", false, 1500000},
+		{"a hundred", false, 100},
+		{"fifteen hundred", false, 1500},
+		{"three thousand two hundred and fifty", false, 3250},
+		{"two point five", false, 2.5},
+		{"zero point zero six", false, 0.06},
+		{"twenty twenty four", false, 2024},
+		{"cuarenta y cinco mil", true, 45000},
+		{"cuarenta y cuatro mil quinientos", true, 44500},
+		{"mil quinientos dólares", true, 1500},
+		{"cien mil", true, 100000},
+		{"veinticinco", true, 25},
+		{"dos coma cinco", true, 2.5},
+		{"1,5 millones", true, 1500000},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNumber(tt.input, tt.decimalComma)
+		if err != nil {
+			t.Errorf("parseNumber(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseNumber(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"and", "forty five apples", "point", "two point", "dollars"} {
+		if got, err := parseNumber(input, false); err == nil {
+			t.Errorf("parseNumber(%q) = %v, want error", input, got)
+		}
+	}
+}
+
+func TestTransformWitResponse_SpokenPrices(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "open_position", Confidence: 0.9}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":          {{Value: "b t c"}},
+			"side":            {{Value: "long"}},
+			"price:entry":     {{Value: "forty five thousand"}},
+			"price:stop_loss": {{Value: "four four five zero zero"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "open long b t c at forty five thousand stop four four five zero zero")
+
+	if got.Symbol != "BTC-USDT" {
+		t.Errorf("Symbol = %q, want BTC-USDT", got.Symbol)
+	}
+	if got.EntryPrice == nil || *got.EntryPrice != 45000 {
+		t.Errorf("EntryPrice = %v, want 45000", got.EntryPrice)
+	}
+	if got.StopLoss == nil || *got.StopLoss != 44500 {
+		t.Errorf("StopLoss = %v, want 44500", got.StopLoss)
+	}
+}
@@ -0,0 +1,74 @@
+package witai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agatticelli/intent-go"
+)
+
+// SymbolRule maps a spoken asset to the full symbol a deployment trades it
+// as, e.g. "gold" to "PAXG-USDT". Rules take precedence over the built-in
+// aliases and the default quote currency.
+type SymbolRule struct {
+	Match  string // Spoken name or ticker, matched ignoring case and extra spaces
+	Symbol string // Full symbol, e.g. "PAXG-USDT" or "EURUSD"
+
+	// AssetClass limits the rule to deployments in that asset class (see
+	// WithAssetClass), e.g. "forex". Empty applies to every asset class.
+	AssetClass string
+}
+
+// symbolRuleKey reduces a spoken asset to its rule key
+func symbolRuleKey(match string) string {
+	return strings.ToLower(strings.Join(strings.Fields(match), " "))
+}
+
+// resolveSymbolRules keeps the rules that apply to assetClass, keyed by
+// match. A rule for assetClass beats a rule without one, so "eur" can be
+// EUR-USDT by default and EURUSD for forex. Two rules for the same match
+// and asset class are a configuration error rather than order dependent.
+func resolveSymbolRules(rules []SymbolRule, assetClass string) (map[string]string, error) {
+	assetClass = strings.ToLower(strings.TrimSpace(assetClass))
+	symbols := make(map[string]string, len(rules))
+	specific := make(map[string]bool, len(rules))
+	seen := make(map[[2]string]string, len(rules))
+
+	for _, rule := range rules {
+		key := symbolRuleKey(rule.Match)
+		class := strings.ToLower(strings.TrimSpace(rule.AssetClass))
+		symbol := strings.ToUpper(strings.TrimSpace(rule.Symbol))
+		if key == "" || symbol == "" {
+			return nil, fmt.Errorf("symbol rule %q → %q: match and symbol are required", rule.Match, rule.Symbol)
+		}
+		if prev, ok := seen[[2]string{key, class}]; ok && prev != symbol {
+			return nil, fmt.Errorf("conflicting symbol rules for %q: %s and %s", key, prev, symbol)
+		}
+		seen[[2]string{key, class}] = symbol
+
+		switch {
+		case class == "":
+			if !specific[key] {
+				symbols[key] = symbol
+			}
+		case class == assetClass:
+			symbols[key] = symbol
+			specific[key] = true
+		}
+	}
+	return symbols, nil
+}
+
+// resolveSymbol normalizes a symbol entity, applying the deployment's rules
+// before the built-in aliases. Cross pairs ("ETH/BTC") are explicit and
+// never rewritten. Tickers spelled out in voice transcripts ("b t c") are
+// joined first.
+func resolveSymbol(symbol, quote string, rules map[string]string) string {
+	symbol = intent.JoinSpelledLetters(symbol)
+	if !strings.Contains(symbol, "/") {
+		if resolved, ok := rules[symbolRuleKey(symbol)]; ok {
+			return resolved
+		}
+	}
+	return normalizeSymbol(symbol, quote)
+}
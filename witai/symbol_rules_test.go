@@ -0,0 +1,102 @@
+package witai
+
+import (
+	"strings"
+	"testing"
+)
+
+var testSymbolRules = []SymbolRule{
+	{Match: "gold", Symbol: "PAXG-USDT"},
+	{Match: "eur", Symbol: "EUR-USDT"},
+	{Match: "eur", Symbol: "EURUSD", AssetClass: "forex"},
+	{Match: "oro", Symbol: "paxg-usdt"},
+}
+
+func TestResolveSymbol_Rules(t *testing.T) {
+	tests := []struct {
+		name       string
+		assetClass string
+		input      string
+		want       string
+	}{
+		{"override", "", "Gold", "PAXG-USDT"},
+		{"lowercase symbol", "", "oro", "PAXG-USDT"},
+		{"default class", "", "EUR", "EUR-USDT"},
+		{"forex class wins", "forex", "eur", "EURUSD"},
+		{"other class uses default rule", "crypto", "eur", "EUR-USDT"},
+		{"no rule falls back to aliases", "forex", "bitcoin", "BTC-USDT"},
+		{"cross pair is never rewritten", "", "eur/usdc", "EUR-USDC"},
+		{"spelled ticker", "", "x r p", "XRP-USDT"},
+		{"spelled ticker with rule", "forex", "e u r", "EURUSD"},
+		{"spelled cross pair", "", "e t h / b t c", "ETH-BTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := resolveSymbolRules(testSymbolRules, tt.assetClass)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := resolveSymbol(tt.input, DefaultQuoteCurrency, rules); got != tt.want {
+				t.Errorf("resolveSymbol(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSymbolRules_OrderIndependent(t *testing.T) {
+	reversed := []SymbolRule{testSymbolRules[2], testSymbolRules[1]}
+	for _, rules := range [][]SymbolRule{testSymbolRules[1:3], reversed} {
+		got, err := resolveSymbolRules(rules, "forex")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got["eur"] != "EURUSD" {
+			t.Errorf("eur = %q, want EURUSD", got["eur"])
+		}
+	}
+}
+
+func TestResolveSymbolRules_Conflict(t *testing.T) {
+	_, err := resolveSymbolRules([]SymbolRule{
+		{Match: "gold", Symbol: "PAXG-USDT"},
+		{Match: "Gold ", Symbol: "XAUT-USDT"},
+	}, "")
+	if err == nil || !strings.Contains(err.Error(), `conflicting symbol rules for "gold"`) {
+		t.Errorf("err = %v, want conflict", err)
+	}
+
+	// The same rule twice isn't a conflict, and neither is a rule per class
+	if _, err := resolveSymbolRules(append(testSymbolRules, testSymbolRules[0]), ""); err != nil {
+		t.Errorf("duplicate rule: %v", err)
+	}
+
+	if _, err := New("token", WithSymbolRules(SymbolRule{Match: "gold"})); err == nil {
+		t.Error("New accepted a rule without a symbol")
+	}
+}
+
+func TestTransform_SymbolRuleQuote(t *testing.T) {
+	rules, err := resolveSymbolRules(testSymbolRules, "forex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := transformConfig{quote: DefaultQuoteCurrency, symbols: rules}
+
+	tests := []struct {
+		symbol string
+		want   string
+	}{
+		{"gold", "PAXG-USDC"}, // Explicit quote still replaces the rule's quote
+		{"eur", "EURUSD"},     // Forex symbols have no quote to replace
+	}
+	for _, tt := range tests {
+		resp := &WitAIResponse{
+			Intents:  []WitAIIntent{{Name: "view_price", Confidence: 0.95}},
+			Entities: map[string][]WitAIEntity{"symbol": {{Value: tt.symbol}}, "quote_currency": {{Value: "usdc"}}},
+		}
+		if got := transformWitResponseWith(resp, "price", config).Symbol; got != tt.want {
+			t.Errorf("%s against usdc = %q, want %q", tt.symbol, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Code generated by gen_symbols.go; DO NOT EDIT.
+
+package witai
+
+import "strings"
+
+// symbolTableVersion identifies the alias table symbolAlias was generated from
+const symbolTableVersion = "d887595cb3ac"
+
+// symbolAlias returns the base asset for a known name or ticker, ignoring case.
+// It doesn't allocate.
+func symbolAlias(s string) (string, bool) {
+	switch len(s) {
+	case 3:
+		switch {
+		case strings.EqualFold(s, "ada"):
+			return "ADA", true
+		case strings.EqualFold(s, "bnb"):
+			return "BNB", true
+		case strings.EqualFold(s, "btc"):
+			return "BTC", true
+		case strings.EqualFold(s, "eth"):
+			return "ETH", true
+		case strings.EqualFold(s, "sol"):
+			return "SOL", true
+		case strings.EqualFold(s, "xrp"):
+			return "XRP", true
+		}
+	case 4:
+		switch {
+		case strings.EqualFold(s, "doge"):
+			return "DOGE", true
+		}
+	case 6:
+		switch {
+		case strings.EqualFold(s, "solana"):
+			return "SOL", true
+		}
+	case 7:
+		switch {
+		case strings.EqualFold(s, "bitcoin"):
+			return "BTC", true
+		case strings.EqualFold(s, "cardano"):
+			return "ADA", true
+		}
+	case 8:
+		switch {
+		case strings.EqualFold(s, "dogecoin"):
+			return "DOGE", true
+		case strings.EqualFold(s, "ethereum"):
+			return "ETH", true
+		}
+	}
+	return "", false
+}
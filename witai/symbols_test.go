@@ -0,0 +1,72 @@
+package witai
+
+import (
+	"strings"
+	"testing"
+)
+
+// symbolMapLookup is the map-based lookup symbolAlias replaced, kept as a
+// benchmark baseline
+func symbolMapLookup(s string) (string, bool) {
+	symbolMap := map[string]string{
+		"bitcoin":  "BTC",
+		"btc":      "BTC",
+		"ethereum": "ETH",
+		"eth":      "ETH",
+		"solana":   "SOL",
+		"sol":      "SOL",
+		"bnb":      "BNB",
+		"xrp":      "XRP",
+		"ada":      "ADA",
+		"cardano":  "ADA",
+		"doge":     "DOGE",
+		"dogecoin": "DOGE",
+	}
+	base, ok := symbolMap[strings.ToLower(s)]
+	return base, ok
+}
+
+var benchSymbols = []string{"BTC", "ethereum", "Solana", "doge", "PEPE", "eth/btc"}
+
+func TestSymbolAlias_MatchesMap(t *testing.T) {
+	inputs := append([]string{"", "BITCOIN", "Cardano", "dogecoins", "bt", "ſol"}, benchSymbols...)
+	for _, in := range inputs {
+		gotBase, gotOK := symbolAlias(in)
+		wantBase, wantOK := symbolMapLookup(in)
+		if gotBase != wantBase || gotOK != wantOK {
+			t.Errorf("symbolAlias(%q) = (%q, %v), want (%q, %v)", in, gotBase, gotOK, wantBase, wantOK)
+		}
+	}
+}
+
+func TestSymbolAlias_NoAllocs(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, s := range benchSymbols {
+			symbolAlias(s)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("symbolAlias allocated %v times per run, want 0", allocs)
+	}
+}
+
+func BenchmarkSymbolAlias(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		symbolAlias(benchSymbols[i%len(benchSymbols)])
+	}
+}
+
+func BenchmarkSymbolMapLookup(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		symbolMapLookup(benchSymbols[i%len(benchSymbols)])
+	}
+}
+
+func BenchmarkNormalizeSymbol(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		normalizeSymbol(benchSymbols[i%len(benchSymbols)], DefaultQuoteCurrency)
+	}
+}
@@ -0,0 +1,53 @@
+package witai_test
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/agatticelli/intent-go/witai"
+	"github.com/agatticelli/intent-go/witai/witaitest"
+)
+
+func TestParseCommand_Traits(t *testing.T) {
+	resp := witai.WitAIResponse{
+		Intents: []witai.WitAIIntent{{Name: "close_position", Confidence: 0.96}},
+		Entities: map[string][]witai.WitAIEntity{
+			"symbol": {{Value: "btc", Confidence: 0.97}},
+		},
+		Traits: map[string][]interface{}{
+			"urgency":       {map[string]interface{}{"id": "1", "value": "high", "confidence": 0.91}},
+			"wit$sentiment": {map[string]interface{}{"id": "2", "value": "negative", "confidence": 0.8}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts []witai.Option
+		want map[string]string
+	}{
+		{"Default", nil, map[string]string{"urgency": "high"}},
+		{"Configured", []witai.Option{witai.WithTraits("urgency", "wit$sentiment")}, map[string]string{"urgency": "high", "wit$sentiment": "negative"}},
+		{"Disabled", []witai.Option{witai.WithTraits()}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := witaitest.NewServer()
+			defer s.Close()
+			s.Respond("close BTC NOW", resp)
+
+			p, err := witai.New(witaitest.Token, append([]witai.Option{witai.WithBaseURL(s.URL)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("witai.New error: %v", err)
+			}
+			cmd, err := p.ParseCommand(context.Background(), "close BTC NOW")
+			if err != nil {
+				t.Fatalf("ParseCommand error: %v", err)
+			}
+			if !maps.Equal(cmd.Traits, tt.want) {
+				t.Errorf("Traits = %v, want %v", cmd.Traits, tt.want)
+			}
+		})
+	}
+}
@@ -1,18 +1,51 @@
 package witai
 
 import (
+	"math"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/agatticelli/intent-go"
 )
 
-// transformWitResponse converts Wit.ai response to NormalizedCommand
+// transformConfig holds the settings that vary per processor or request
+type transformConfig struct {
+	locale string // Selects the decimal separator (see parseNumber)
+	quote  string // Quote currency for symbols given without one
+
+	// symbols maps rule keys to full symbols (see resolveSymbolRules)
+	symbols map[string]string
+
+	// clock stamps the command (default intent.SystemClock)
+	clock intent.Clock
+
+	// traits lists the Wit.ai traits copied into NormalizedCommand.Traits
+	traits []string
+}
+
+// transformWitResponse converts Wit.ai response to NormalizedCommand,
+// reading numbers with English separators and quoting symbols in
+// DefaultQuoteCurrency
 func transformWitResponse(resp *WitAIResponse, rawInput string) *intent.NormalizedCommand {
+	return transformWitResponseWith(resp, rawInput, transformConfig{quote: DefaultQuoteCurrency, traits: DefaultTraits})
+}
+
+// transformWitResponseWith converts Wit.ai response to NormalizedCommand
+// using config
+func transformWitResponseWith(resp *WitAIResponse, rawInput string, config transformConfig) *intent.NormalizedCommand {
+	decimalComma := usesDecimalComma(config.locale)
+	explicitQuote := ""
+	clock := config.clock
+	if clock == nil {
+		clock = intent.SystemClock{}
+	}
 	cmd := &intent.NormalizedCommand{
-		RawInput:  rawInput,
-		Timestamp: time.Now(),
+		RawInput:      rawInput,
+		Timestamp:     clock.Now(),
+		SchemaVersion: intent.CurrentSchemaVersion,
 	}
 
 	// Extract intent
@@ -21,6 +54,14 @@ func transformWitResponse(resp *WitAIResponse, rawInput string) *intent.Normaliz
 		cmd.Confidence = resp.Intents[0].Confidence
 	}
 
+	// Keep the remaining ranked intents as alternatives
+	for _, alt := range resp.Intents[min(1, len(resp.Intents)):] {
+		cmd.Alternatives = append(cmd.Alternatives, intent.IntentCandidate{
+			Intent:     mapWitIntent(alt.Name),
+			Confidence: alt.Confidence,
+		})
+	}
+
 	// Extract entities
 	for entityName, entityValues := range resp.Entities {
 		if len(entityValues) == 0 {
@@ -31,81 +72,727 @@ func transformWitResponse(resp *WitAIResponse, rawInput string) *intent.Normaliz
 
 		switch entityName {
 		case "symbol":
-			cmd.Symbol = normalizeSymbol(entity.Value)
+			cmd.Symbol = resolveSymbol(entity.Value, config.quote, config.symbols)
+
+		case "exclude_symbol":
+			// "except ETH and SOL" tags every excluded symbol
+			for _, excluded := range entityValues {
+				cmd.ExcludeSymbols = append(cmd.ExcludeSymbols, resolveSymbol(excluded.Value, config.quote, config.symbols))
+			}
+
+		case "quote_currency":
+			explicitQuote = normalizeQuoteCurrency(entity.Value)
 
 		case "side":
 			side := normalizeSide(entity.Value)
 			cmd.Side = &side
 
+		case "order_type":
+			if orderType, ok := normalizeOrderType(entity.Value); ok {
+				cmd.OrderType = orderType
+			}
+
+		case "exchange":
+			cmd.Exchange = exchangeKey(entity.Value)
+
+		case "account":
+			cmd.Account = normalizeAccount(entity.Value)
+
+		case "time_in_force":
+			if tif, ok := normalizeTimeInForce(entity.Value); ok {
+				cmd.TimeInForce = tif
+			}
+
+		case "reduce_only":
+			if reduceOnly, ok := parseReduceOnly(entity.Value); ok {
+				cmd.ReduceOnly = &reduceOnly
+			}
+
+		case "margin_mode":
+			if mode, ok := normalizeMarginMode(entity.Value); ok {
+				cmd.MarginMode = mode
+			}
+
 		case "entry_price", "price:entry":
-			if price, err := strconv.ParseFloat(entity.Value, 64); err == nil {
+			if price, err := parseNumber(entity.Value, decimalComma); err == nil {
 				cmd.EntryPrice = &price
 			}
 
 		case "stop_loss", "price:stop_loss":
-			if sl, err := strconv.ParseFloat(entity.Value, 64); err == nil {
+			if sl, err := parseNumber(entity.Value, decimalComma); err == nil {
 				cmd.StopLoss = &sl
 			}
 
+		case "stop_loss_offset":
+			if offset, err := parseNumber(entity.Value, decimalComma); err == nil {
+				cmd.StopLossOffset = &offset
+			}
+
 		case "take_profit", "price:take_profit":
-			if tp, err := strconv.ParseFloat(entity.Value, 64); err == nil {
+			if tp, err := parseNumber(entity.Value, decimalComma); err == nil {
 				cmd.TakeProfit = &tp
 			}
 
 		case "risk":
-			if risk, err := strconv.ParseFloat(entity.Value, 64); err == nil {
+			if risk, err := parseNumber(entity.Value, decimalComma); err == nil {
 				cmd.RiskPercent = &risk
 			}
 
+		case "leverage":
+			if leverage, ok := parseLeverage(entity.Value); ok {
+				cmd.Leverage = &leverage
+			}
+
+		case "quantity":
+			if qty, err := parseNumber(entity.Value, decimalComma); err == nil {
+				cmd.Quantity = &qty
+			}
+
+		case "notional":
+			if notional, err := parseNotional(entity.Value, decimalComma); err == nil {
+				cmd.NotionalUSD = &notional
+			}
+
+		case "close_percent":
+			if pct, ok := parsePercent(entity.Value); ok {
+				cmd.ClosePercent = &pct
+			}
+
 		case "trigger_price":
-			if trigger, err := strconv.ParseFloat(entity.Value, 64); err == nil {
+			if trigger, err := parseNumber(entity.Value, decimalComma); err == nil {
 				cmd.TriggerPrice = &trigger
 			}
 
 		case "callback_rate":
-			if cb, err := strconv.ParseFloat(entity.Value, 64); err == nil {
+			if cb, err := parseNumber(entity.Value, decimalComma); err == nil {
 				cmd.CallbackRate = &cb
 			}
 
+		case "source_account":
+			cmd.SourceAccount = strings.TrimSpace(entity.Value)
+
+		case "target_account":
+			cmd.TargetAccount = strings.TrimSpace(entity.Value)
+
+		case "size_factor":
+			if factor, ok := parseSizeFactor(entity.Value, decimalComma); ok {
+				cmd.SizeFactor = &factor
+			}
+
+		case "hedge_ratio":
+			if ratio, ok := parseRatio(entity.Value); ok {
+				cmd.HedgeRatio = &ratio
+			}
+
 		case "levels":
 			// Parse multiple TP levels: "3000:30,3100:70"
-			cmd.TPLevels = parseTPLevels(entity.Value)
+			cmd.TPLevels = parseTPLevels(entity.Value, decimalComma)
+
+		case "sl_levels":
+			// Parse multiple stop losses: "SL 44500:50,44000:50"
+			cmd.SLLevels = parseSLLevels(entity.Value, decimalComma)
+
+		case "entry_levels":
+			// Parse a DCA ladder: "44000, 43500, 43000" or "44000:50,43500:50"
+			cmd.EntryLevels = parseEntryLevels(entity.Value, decimalComma)
+
+		case "condition_symbol":
+			condition(cmd).Symbol = resolveSymbol(entity.Value, config.quote, config.symbols)
+
+		case "condition_operator":
+			if op, ok := normalizeConditionOperator(entity.Value); ok {
+				condition(cmd).Operator = op
+			}
+
+		case "condition_price":
+			if price, err := parseNumber(entity.Value, decimalComma); err == nil {
+				condition(cmd).Price = &price
+			}
+
+		case "grid_range":
+			// Parse grid bounds: "42000-46000"
+			if lower, upper, ok := parseGridRange(entity.Value, decimalComma); ok {
+				cmd.GridLower = &lower
+				cmd.GridUpper = &upper
+			}
+
+		case "grid_lower":
+			if lower, err := parseNumber(entity.Value, decimalComma); err == nil {
+				cmd.GridLower = &lower
+			}
+
+		case "grid_upper":
+			if upper, err := parseNumber(entity.Value, decimalComma); err == nil {
+				cmd.GridUpper = &upper
+			}
+
+		case "grid_levels":
+			if count, err := strconv.Atoi(strings.TrimSpace(entity.Value)); err == nil {
+				cmd.GridLevels = &count
+			}
+
+		case "order_id":
+			cmd.OrderID = strings.TrimSpace(entity.Value)
+
+		case "scope":
+			cmd.CancelAll = isAllScope(entity.Value)
+			cmd.LatestOrder = isLatestScope(entity.Value)
+
+		case "alert_id":
+			cmd.AlertID = strings.TrimSpace(entity.Value)
+
+		case "alert_price":
+			if price, err := parseNumber(entity.Value, decimalComma); err == nil {
+				cmd.AlertPrice = &price
+			}
+
+		case "alert_direction":
+			if dir, ok := normalizeConditionOperator(entity.Value); ok {
+				cmd.AlertDirection = dir
+			}
+
+		case "asset":
+			cmd.Asset = strings.ToUpper(strings.TrimSpace(entity.Value))
+
+		case "amount":
+			if amount, err := parseNumber(entity.Value, decimalComma); err == nil {
+				cmd.Amount = &amount
+			}
+
+		case "address_ref":
+			// Only whitelist labels are accepted, validators reject raw addresses
+			cmd.AddressRef = strings.TrimSpace(entity.Value)
+
+		case "period":
+			if period, ok := normalizePeriod(entity.Value); ok {
+				cmd.Period = period
+			}
+
+		case "timeframe":
+			if tf, ok := normalizeTimeframe(entity.Value); ok {
+				cmd.Timeframe = tf
+			}
+
+		case "wit$datetime:datetime", "datetime":
+			cmd.From, cmd.To = parseDatetime(entity)
+
+		case "wit$datetime:expires_at", "expires_at":
+			// "valid until Friday" runs through the end of Friday
+			if from, to := parseDatetime(entity); to != nil {
+				cmd.ExpiresAt = to
+			} else {
+				cmd.ExpiresAt = from
+			}
+
+		case "grid_level_size":
+			if size, err := parseNumber(entity.Value, decimalComma); err == nil {
+				cmd.GridLevelSize = &size
+			}
+
+		default:
+			continue
+		}
+
+		if cmd.EntityMeta == nil {
+			cmd.EntityMeta = make(map[string]intent.EntitySpan)
+		}
+		cmd.EntityMeta[entityName] = intent.EntitySpan{
+			Start:      entity.Start,
+			End:        entity.End,
+			Body:       entity.Body,
+			Confidence: entity.Confidence,
+		}
+	}
+
+	setFieldConfidence(cmd)
+
+	for _, name := range config.traits {
+		if value, ok := traitValue(resp.Traits[name]); ok {
+			if cmd.Traits == nil {
+				cmd.Traits = make(map[string]string)
+			}
+			cmd.Traits[name] = value
+		}
+	}
+
+	// "BTC against USDC" names the quote separately from the symbols, and
+	// applies to every one of them
+	if explicitQuote != "" {
+		cmd.Symbol = withQuote(cmd.Symbol, explicitQuote)
+		if cmd.Condition != nil {
+			cmd.Condition.Symbol = withQuote(cmd.Condition.Symbol, explicitQuote)
+		}
+		for i, symbol := range cmd.ExcludeSymbols {
+			cmd.ExcludeSymbols[i] = withQuote(symbol, explicitQuote)
+		}
+	}
+
+	// view_pnl reports realized PnL; asking for the open PnL of one trade
+	// is a position detail query, and without a symbol the list view
+	if cmd.Intent == intent.IntentViewPnL && mentionsUnrealizedPnL(rawInput) {
+		cmd.Intent = intent.IntentViewPosition
+		if cmd.Symbol == "" {
+			cmd.Intent = intent.IntentViewPositions
+		}
+	}
+
+	// "at half size" is often left in the text instead of tagged as an entity
+	if cmd.Intent == intent.IntentCopyTrade && cmd.SizeFactor == nil {
+		if factor, ok := mentionedSizeFactor(rawInput); ok {
+			cmd.SizeFactor = &factor
 		}
 	}
 
+	// close_position needs a symbol; "close everything" is close_all, kept
+	// apart so an executor never reads a missing symbol as every position
+	if cmd.Intent == intent.IntentClosePosition && cmd.Symbol == "" && mentionsEveryPosition(rawInput) {
+		cmd.Intent = intent.IntentCloseAll
+	}
+
 	return cmd
 }
 
-// normalizeSymbol converts various formats to standard "BTC-USDT"
-func normalizeSymbol(symbol string) string {
-	symbolMap := map[string]string{
-		"bitcoin":  "BTC-USDT",
-		"btc":      "BTC-USDT",
-		"ethereum": "ETH-USDT",
-		"eth":      "ETH-USDT",
-		"solana":   "SOL-USDT",
-		"sol":      "SOL-USDT",
-		"bnb":      "BNB-USDT",
-		"xrp":      "XRP-USDT",
-		"ada":      "ADA-USDT",
-		"cardano":  "ADA-USDT",
-		"doge":     "DOGE-USDT",
-		"dogecoin": "DOGE-USDT",
-	}
-
-	normalized := strings.ToLower(strings.TrimSpace(symbol))
-	if mapped, ok := symbolMap[normalized]; ok {
-		return mapped
+// DefaultTraits are the Wit.ai traits copied into NormalizedCommand.Traits
+// unless WithTraits says otherwise
+var DefaultTraits = []string{"urgency"}
+
+// traitValue returns the value of the highest-confidence trait result.
+// Wit.ai reports traits as [{"id": ..., "value": "high", "confidence": 0.9}].
+func traitValue(results []interface{}) (string, bool) {
+	if len(results) == 0 {
+		return "", false
+	}
+	result, ok := results[0].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	value, ok := result["value"].(string)
+	return value, ok && value != ""
+}
+
+// everyPositionPhrases are English and Spanish ways of naming the whole
+// book, e.g. "close everything" or "cerrar todo"
+var everyPositionPhrases = [][]string{
+	{"everything"}, {"all", "positions"}, {"all", "my", "positions"}, {"all", "of", "my", "positions"},
+	{"all", "trades"}, {"all", "my", "trades"},
+	{"todo"}, {"todas", "las", "posiciones"}, {"todas", "mis", "posiciones"}, {"todos", "los", "trades"},
+}
+
+// closeVerbs introduce the object of a close command
+var closeVerbs = []string{"close", "exit", "cerrar", "cierra", "cierre", "cerrá", "cerra"}
+
+// everyPositionTail are the words that may follow the whole-book phrase
+// without narrowing it, e.g. "close everything now"
+var everyPositionTail = []string{"now", "please", "asap", "right", "ya", "ahora", "por", "favor"}
+
+// everyPositionExclusions start a list of symbols to keep open, e.g.
+// "close everything except ETH"
+var everyPositionExclusions = []string{"except", "but", "excepto", "menos", "salvo"}
+
+// mentionsEveryPosition reports whether input unambiguously targets every
+// open position: a whole-book phrase must be the object of a close verb and
+// may only be followed by filler or an exclusion list. "close the position,
+// todo bien" or "close everything on BTC later" don't qualify.
+func mentionsEveryPosition(input string) bool {
+	words := inputWords(input)
+	for i, word := range words {
+		if !slices.Contains(closeVerbs, word) {
+			continue
+		}
+		object := words[i+1:]
+		if len(object) > 0 && object[0] == "out" {
+			object = object[1:]
+		}
+		for _, phrase := range everyPositionPhrases {
+			if len(object) < len(phrase) || !slices.Equal(object[:len(phrase)], phrase) {
+				continue
+			}
+			tail := object[len(phrase):]
+			if len(tail) > 0 && slices.Contains(everyPositionExclusions, tail[0]) {
+				return true
+			}
+			if !slices.ContainsFunc(tail, func(w string) bool { return !slices.Contains(everyPositionTail, w) }) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inputWords splits input into lowercase words, dropping punctuation
+func inputWords(input string) []string {
+	return strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// sizeFactorPhrases are English and Spanish ways of scaling a copied
+// position, e.g. "at half size" or "a la mitad"
+var sizeFactorPhrases = []struct {
+	words  []string
+	factor float64
+}{
+	{[]string{"half", "size"}, 0.5},
+	{[]string{"half", "the", "size"}, 0.5},
+	{[]string{"quarter", "size"}, 0.25},
+	{[]string{"double", "size"}, 2},
+	{[]string{"twice", "the", "size"}, 2},
+	{[]string{"same", "size"}, 1},
+	{[]string{"full", "size"}, 1},
+	{[]string{"medio", "tamaño"}, 0.5},
+	{[]string{"mitad", "del", "tamaño"}, 0.5},
+	{[]string{"a", "la", "mitad"}, 0.5},
+	{[]string{"doble", "tamaño"}, 2},
+	{[]string{"el", "doble"}, 2},
+	{[]string{"mismo", "tamaño"}, 1},
+}
+
+// parseSizeFactor parses a size factor given as a number ("0.5"), a
+// percentage ("50%") or a phrase ("half size")
+func parseSizeFactor(value string, decimalComma bool) (float64, bool) {
+	trimmed := strings.TrimSpace(value)
+	if strings.HasSuffix(trimmed, "%") {
+		return parseRatio(trimmed)
+	}
+	if factor, err := parseNumber(trimmed, decimalComma); err == nil {
+		return factor, true
+	}
+	switch strings.ToLower(trimmed) {
+	case "half", "mitad":
+		return 0.5, true
+	case "double", "doble":
+		return 2, true
+	}
+	return mentionedSizeFactor(trimmed)
+}
+
+// mentionedSizeFactor returns the factor of the first size phrase in input,
+// matched on whole words
+func mentionedSizeFactor(input string) (float64, bool) {
+	words := inputWords(input)
+	for i := range words {
+		for _, phrase := range sizeFactorPhrases {
+			if len(words)-i >= len(phrase.words) && slices.Equal(words[i:i+len(phrase.words)], phrase.words) {
+				return phrase.factor, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// unrealizedPnLPhrases are English and Spanish ways of asking how an open
+// trade is doing, e.g. "how's my BTC trade doing" or "cómo va mi long de ETH"
+var unrealizedPnLPhrases = []string{
+	"unrealized", "unrealised", "floating", "open pnl", "open p&l", "paper profit", "paper loss",
+	"how's my", "how is my", "how are my",
+	"no realizad", "flotante", "latente", "cómo va", "como va", "voy ganando", "voy perdiendo",
+}
+
+// mentionsUnrealizedPnL reports whether input asks about open rather than
+// realized profit and loss
+func mentionsUnrealizedPnL(input string) bool {
+	lower := strings.ToLower(input)
+	for _, phrase := range unrealizedPnLPhrases {
+		if strings.Contains(lower, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// condition returns the command condition, creating it on first use
+func condition(cmd *intent.NormalizedCommand) *intent.PriceCondition {
+	if cmd.Condition == nil {
+		cmd.Condition = &intent.PriceCondition{}
+	}
+	return cmd.Condition
+}
+
+// normalizeConditionOperator converts comparison phrasing to above/below.
+// "Breaks" alone means breaking out upwards, as in "if BTC breaks 50000".
+// A bare "crosses" has no direction and is not mapped.
+func normalizeConditionOperator(op string) (intent.ConditionOperator, bool) {
+	switch strings.Join(strings.Fields(strings.ToLower(op)), " ") {
+	case "above", ">", ">=", "over", "breaks", "breaks above", "crosses above", "goes above", "rises above",
+		"exceeds", "encima de", "por encima de", "supera", "rompe", "rompe hacia arriba", "pasa":
+		return intent.ConditionAbove, true
+	case "below", "<", "<=", "under", "breaks below", "breaks down", "crosses below", "goes below", "drops below",
+		"falls below", "debajo de", "por debajo de", "cae debajo de", "cae por debajo de", "perfora", "pierde":
+		return intent.ConditionBelow, true
+	}
+	return "", false
+}
+
+// normalizeOrderType maps English and Spanish order type phrases
+func normalizeOrderType(orderType string) (intent.OrderType, bool) {
+	normalized := strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(orderType))
+	switch strings.Join(strings.Fields(normalized), " ") {
+	case "market", "at market", "mercado", "a mercado":
+		return intent.OrderTypeMarket, true
+	case "limit", "limite", "límite":
+		return intent.OrderTypeLimit, true
+	case "stop limit", "stop limite", "stop límite":
+		return intent.OrderTypeStopLimit, true
+	}
+	return "", false
+}
+
+// accountFillers are words around an account name that aren't part of it,
+// as in "my scalping account" or "mi cuenta de scalping"
+var accountFillers = map[string]bool{
+	"my": true, "the": true, "account": true, "subaccount": true, "sub": true,
+	"mi": true, "la": true, "cuenta": true, "subcuenta": true, "de": true,
+}
+
+// normalizeAccount reduces an account phrase to its lowercase name
+func normalizeAccount(account string) string {
+	var words []string
+	for _, w := range strings.Fields(strings.ToLower(account)) {
+		if !accountFillers[w] {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// normalizeTimeInForce maps time-in-force abbreviations and English and
+// Spanish phrases
+func normalizeTimeInForce(tif string) (intent.TimeInForce, bool) {
+	normalized := strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(tif))
+	switch strings.Join(strings.Fields(normalized), " ") {
+	case "gtc", "good till cancel", "good till cancelled", "good til canceled", "hasta cancelar":
+		return intent.TimeInForceGTC, true
+	case "ioc", "immediate or cancel", "inmediata o cancelar":
+		return intent.TimeInForceIOC, true
+	case "fok", "fill or kill", "todo o nada":
+		return intent.TimeInForceFOK, true
+	case "post only", "postonly", "maker only", "solo maker":
+		return intent.TimeInForcePostOnly, true
+	}
+	return "", false
+}
+
+// parseReduceOnly maps English and Spanish reduce-only phrases, including
+// negations like "not reduce only"
+func parseReduceOnly(phrase string) (bool, bool) {
+	normalized := strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(phrase))
+	switch strings.Join(strings.Fields(normalized), " ") {
+	case "reduce only", "reduceonly", "only reduce", "solo reducir", "sólo reducir", "reducir solamente", "true", "yes":
+		return true, true
+	case "not reduce only", "no reduce only", "no solo reducir", "no sólo reducir", "false", "no":
+		return false, true
+	}
+	return false, false
+}
+
+// normalizeMarginMode maps English and Spanish margin mode phrases
+func normalizeMarginMode(mode string) (intent.MarginMode, bool) {
+	normalized := strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(mode))
+	switch strings.Join(strings.Fields(normalized), " ") {
+	case "cross", "crossed", "cross margin", "cruzado", "cruzada", "margen cruzado":
+		return intent.MarginModeCross, true
+	case "isolated", "isolated margin", "aislado", "aislada", "margen aislado":
+		return intent.MarginModeIsolated, true
+	}
+	return "", false
+}
+
+// isAllScope reports whether scope selects everything, e.g. "all" in
+// "cancel all orders"
+func isAllScope(scope string) bool {
+	switch strings.ToLower(strings.TrimSpace(scope)) {
+	case "all", "every", "everything", "todas", "todos", "todo":
+		return true
+	}
+	return false
+}
+
+// isLatestScope reports whether scope selects the most recent order, e.g.
+// "last" in "did my last order fill"
+func isLatestScope(scope string) bool {
+	switch strings.ToLower(strings.TrimSpace(scope)) {
+	case "last", "latest", "most recent", "último", "ultimo", "última", "ultima":
+		return true
+	}
+	return false
+}
+
+// parseDatetime turns a wit/datetime entity into a [from, to) range. A
+// single value covers its grain, so "last week" spans the whole week.
+func parseDatetime(entity WitAIEntity) (from, to *time.Time) {
+	if entity.Type == "interval" {
+		if entity.From != nil {
+			from = parseTime(entity.From.Value)
+		}
+		if entity.To != nil {
+			to = parseTime(entity.To.Value)
+		}
+		return from, to
+	}
+
+	from = parseTime(entity.Value)
+	if from == nil {
+		return nil, nil
+	}
+	if end, ok := addGrain(*from, entity.Grain); ok {
+		to = &end
+	}
+	return from, to
+}
+
+func parseTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// addGrain returns the end of the grain-sized period starting at t
+func addGrain(t time.Time, grain string) (time.Time, bool) {
+	switch grain {
+	case "second":
+		return t.Add(time.Second), true
+	case "minute":
+		return t.Add(time.Minute), true
+	case "hour":
+		return t.Add(time.Hour), true
+	case "day":
+		return t.AddDate(0, 0, 1), true
+	case "week":
+		return t.AddDate(0, 0, 7), true
+	case "month":
+		return t.AddDate(0, 1, 0), true
+	case "quarter":
+		return t.AddDate(0, 3, 0), true
+	case "year":
+		return t.AddDate(1, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+// timeframeUnits maps singular English and Spanish interval units to the
+// suffix of a canonical timeframe
+var timeframeUnits = map[string]string{
+	"m": "m", "min": "m", "minute": "m", "minuto": "m",
+	"h": "h", "hr": "h", "hour": "h", "hora": "h",
+	"d": "d", "day": "d", "día": "d", "dia": "d",
+	"w": "w", "wk": "w", "week": "w", "semana": "w",
+}
+
+// normalizeTimeframe turns spoken chart intervals into their canonical
+// form, e.g. "4H", "4 hours" and "4hs" become "4h", and "daily" or
+// "diario" becomes "1d". The result may still be unsupported ("7m");
+// validators check it against intent.Timeframes.
+func normalizeTimeframe(tf string) (string, bool) {
+	s := strings.ToLower(strings.Join(strings.Fields(tf), " "))
+	switch s {
+	case "hourly", "por hora", "horario":
+		return "1h", true
+	case "daily", "diario", "diaria":
+		return "1d", true
+	case "weekly", "semanal":
+		return "1w", true
+	}
+
+	s = strings.TrimPrefix(s, "the ")
+	digits := len(s) - len(strings.TrimLeft(s, "0123456789"))
+	count, unit := s[:digits], strings.TrimSpace(s[digits:])
+	if count == "" {
+		// "hour", "una hora"
+		count = "1"
+		unit = strings.TrimPrefix(strings.TrimPrefix(unit, "una "), "un ")
+		unit = strings.TrimPrefix(unit, "an ")
+		unit = strings.TrimPrefix(unit, "a ")
+	}
+	unit = strings.TrimSuffix(unit, " chart")
+	unit = strings.TrimSuffix(unit, "s")
+	if suffix, ok := timeframeUnits[unit]; ok {
+		n, err := strconv.Atoi(count)
+		if err != nil || n <= 0 {
+			return "", false
+		}
+		return strconv.Itoa(n) + suffix, true
+	}
+	return "", false
+}
+
+// normalizePeriod maps English and Spanish reporting windows to intent.Period
+func normalizePeriod(period string) (intent.Period, bool) {
+	switch strings.ToLower(strings.Join(strings.Fields(period), " ")) {
+	case "today", "hoy":
+		return intent.PeriodToday, true
+	case "yesterday", "ayer":
+		return intent.PeriodYesterday, true
+	case "this week", "week", "esta semana", "semana":
+		return intent.PeriodThisWeek, true
+	case "last week", "la semana pasada", "semana pasada":
+		return intent.PeriodLastWeek, true
+	case "this month", "month", "este mes", "mes":
+		return intent.PeriodThisMonth, true
+	case "last month", "el mes pasado", "mes pasado":
+		return intent.PeriodLastMonth, true
+	case "this year", "year", "este año", "año":
+		return intent.PeriodThisYear, true
+	case "all time", "ever", "total", "desde siempre":
+		return intent.PeriodAllTime, true
+	}
+	return "", false
+}
+
+//go:generate go run gen_symbols.go
+
+// DefaultQuoteCurrency is appended to symbols given without a quote
+// (see WithQuoteCurrency)
+const DefaultQuoteCurrency = "USDT"
+
+// withQuote replaces the quote of symbol. Forex style symbols from a
+// SymbolRule ("EURUSD") and empty symbols have no quote to replace.
+func withQuote(symbol, quote string) string {
+	base, _, ok := strings.Cut(symbol, "-")
+	if !ok {
+		return symbol
+	}
+	return base + "-" + quote
+}
+
+// normalizeSymbol converts various formats to standard "BTC-USDT", using
+// quote for symbols given without one
+func normalizeSymbol(symbol, quote string) string {
+	trimmed := strings.TrimSpace(symbol)
+	if base, ok := symbolAlias(trimmed); ok {
+		return base + "-" + quote
+	}
+
+	// Cross pairs like "ETH/BTC" keep their own quote
+	if base, quote, ok := strings.Cut(trimmed, "/"); ok {
+		return strings.ToUpper(strings.TrimSpace(base) + "-" + strings.TrimSpace(quote))
 	}
 
 	// Assume it's already a symbol, format it
-	symbol = strings.ToUpper(symbol)
-	if !strings.HasSuffix(symbol, "-USDT") {
-		return symbol + "-USDT"
+	symbol = strings.ToUpper(trimmed)
+	if !strings.Contains(symbol, "-") {
+		return symbol + "-" + quote
 	}
 	return symbol
 }
 
+// quoteAliases maps spoken quote currencies to tickers
+var quoteAliases = map[string]string{
+	"dollar": "USD", "dollars": "USD", "dolar": "USD", "dólar": "USD", "dolares": "USD", "dólares": "USD",
+	"tether": "USDT", "usd coin": "USDC",
+}
+
+// normalizeQuoteCurrency converts a quote currency phrase ("usdc",
+// "dollars") to its ticker
+func normalizeQuoteCurrency(quote string) string {
+	quote = strings.ToLower(strings.Join(strings.Fields(quote), " "))
+	if ticker, ok := quoteAliases[quote]; ok {
+		return ticker
+	}
+	return strings.ToUpper(quote)
+}
+
 // normalizeSide converts various formats to LONG/SHORT
 // Supports Spanish and English
 func normalizeSide(side string) intent.Side {
@@ -139,45 +826,196 @@ func normalizeSide(side string) intent.Side {
 
 // mapWitIntent maps Wit.ai intent names to our Intent enum
 func mapWitIntent(witIntent string) intent.Intent {
-	intentMap := map[string]intent.Intent{
-		"open_position":  intent.IntentOpenPosition,
-		"close_position": intent.IntentClosePosition,
-		"view_positions": intent.IntentViewPositions,
-		"view_orders":    intent.IntentViewOrders,
-		"cancel_orders":  intent.IntentCancelOrders,
-		"check_balance":  intent.IntentCheckBalance,
-		"break_even":     intent.IntentBreakEven,
-		"trailing_stop":  intent.IntentTrailingStop,
-	}
-
-	if mapped, ok := intentMap[witIntent]; ok {
+	if mapped, ok := witIntents[witIntent]; ok {
 		return mapped
 	}
+	// Namespaced intents registered by the application keep their name
+	if _, ok := intent.LookupIntent(intent.Intent(witIntent)); ok {
+		return intent.Intent(witIntent)
+	}
 
 	return intent.IntentUnknown
 }
 
-// parseTPLevels parses "3000:30,3100:70" format
-func parseTPLevels(input string) []intent.TPLevel {
+// parseTPLevels parses "3000:30,3100:70" format. Every level needs its
+// percentage.
+func parseTPLevels(input string, decimalComma bool) []intent.TPLevel {
 	var levels []intent.TPLevel
+	for _, level := range parseLadder(input, decimalComma) {
+		if level.hasPercentage {
+			levels = append(levels, intent.TPLevel{Price: level.price, Percentage: level.percentage})
+		}
+	}
+	return levels
+}
+
+// parseSLLevels parses "44500:50,44000:50", optionally introduced by a
+// "SL" or "stop loss" label. Every level needs its percentage.
+func parseSLLevels(input string, decimalComma bool) []intent.SLLevel {
+	var levels []intent.SLLevel
+	for _, level := range parseLadder(trimLabel(input, "stop loss", "stop", "sl"), decimalComma) {
+		if level.hasPercentage {
+			levels = append(levels, intent.SLLevel{Price: level.price, Percentage: level.percentage})
+		}
+	}
+	return levels
+}
+
+// trimLabel removes the first of labels that input starts with
+func trimLabel(input string, labels ...string) string {
+	trimmed := strings.TrimSpace(input)
+	lower := strings.ToLower(trimmed)
+	for _, label := range labels {
+		if strings.HasPrefix(lower, label) {
+			return strings.TrimSpace(trimmed[len(label):])
+		}
+	}
+	return trimmed
+}
+
+// ladderLevel is one price of a ladder, with the percentage when given
+type ladderLevel struct {
+	price         float64
+	percentage    float64
+	hasPercentage bool
+}
 
-	parts := strings.Split(input, ",")
-	for _, part := range parts {
-		pricePct := strings.Split(strings.TrimSpace(part), ":")
-		if len(pricePct) != 2 {
+// parseLadder parses price levels separated by commas or "and"/"y", each
+// optionally followed by a percentage ("44000:50"). Every comma separates
+// levels, so "100,200,300" is three prices; within a level the price is
+// read like any other ("45k", "$46.500"). Levels that don't parse are
+// skipped.
+func parseLadder(input string, decimalComma bool) []ladderLevel {
+	input = strings.ToLower(input)
+	for _, sep := range []string{" and ", " y "} {
+		input = strings.ReplaceAll(input, sep, ",")
+	}
+
+	var levels []ladderLevel
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		priceStr, pctStr, hasPct := strings.Cut(part, ":")
+		price, err := parseNotional(priceStr, decimalComma)
+		if err != nil {
+			continue
+		}
+		if !hasPct {
+			levels = append(levels, ladderLevel{price: price})
 			continue
 		}
+		if pct, ok := parsePercent(pctStr); ok {
+			levels = append(levels, ladderLevel{price: price, percentage: pct, hasPercentage: true})
+		}
+	}
+	return levels
+}
 
-		price, err1 := strconv.ParseFloat(pricePct[0], 64)
-		pct, err2 := strconv.ParseFloat(pricePct[1], 64)
+// splitEqually returns the levels that have a percentage. When none has
+// one, every level gets an equal share, with rounding absorbed by the last.
+func splitEqually(levels []ladderLevel) []ladderLevel {
+	var explicit []ladderLevel
+	for _, level := range levels {
+		if level.hasPercentage {
+			explicit = append(explicit, level)
+		}
+	}
+	if len(explicit) > 0 || len(levels) == 0 {
+		return explicit
+	}
 
-		if err1 == nil && err2 == nil {
-			levels = append(levels, intent.TPLevel{
-				Price:      price,
-				Percentage: pct,
-			})
+	share := math.Round(100/float64(len(levels))*100) / 100
+	remaining := 100.0
+	for i := range levels {
+		pct := share
+		if i == len(levels)-1 {
+			pct = math.Round(remaining*100) / 100
 		}
+		remaining -= pct
+		levels[i].percentage = pct
+	}
+	return levels
+}
+
+// parsePercent parses "50" or "50%" into a percentage
+func parsePercent(input string) (float64, bool) {
+	input = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(input), "%"))
+
+	pct, err := parseNumber(input, false)
+	if err != nil {
+		return 0, false
+	}
+	return pct, true
+}
+
+// parseRatio parses a fraction ("0.5") or a percentage ("50%") into a
+// ratio. Only values with a percent sign are percentages, so "1.5" and "50"
+// stay as given and fail range validation like "150%" instead of being
+// guessed at.
+func parseRatio(input string) (float64, bool) {
+	trimmed := strings.TrimSpace(input)
+	if strings.HasSuffix(trimmed, "%") {
+		pct, ok := parsePercent(trimmed)
+		return pct / 100, ok
+	}
+
+	ratio, err := parseNumber(trimmed, false)
+	if err != nil {
+		return 0, false
 	}
+	return ratio, true
+}
+
+// parseLeverage parses "10", "10x" or "x10" into a leverage multiplier
+func parseLeverage(input string) (float64, bool) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	input = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(input, "x"), "x"))
+
+	leverage, err := parseNumber(input, false)
+	if err != nil {
+		return 0, false
+	}
+	return leverage, true
+}
 
+// parseEntryLevels parses a DCA ladder (see parseLadder). When no level has
+// a percentage, the size is split equally, with rounding absorbed by the
+// last level.
+func parseEntryLevels(input string, decimalComma bool) []intent.EntryLevel {
+	var levels []intent.EntryLevel
+	for _, level := range splitEqually(parseLadder(input, decimalComma)) {
+		levels = append(levels, intent.EntryLevel{Price: level.price, Percentage: level.percentage})
+	}
 	return levels
 }
+
+// parseGridRange parses "42000-46000", "42,000 to 46,000" or "42k a 46k"
+// into ordered bounds
+func parseGridRange(input string, decimalComma bool) (lower, upper float64, ok bool) {
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	var parts []string
+	for _, sep := range []string{" to ", " a ", "-"} {
+		if strings.Contains(input, sep) {
+			parts = strings.SplitN(input, sep, 2)
+			break
+		}
+	}
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	lower, err1 := parseNotional(parts[0], decimalComma)
+	upper, err2 := parseNotional(parts[1], decimalComma)
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+
+	if lower > upper {
+		lower, upper = upper, lower
+	}
+	return lower, upper, true
+}
@@ -2,9 +2,12 @@ package witai
 
 import (
 	"reflect"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/validators"
 	"github.com/agatticelli/trading-common-types"
 )
 
@@ -37,6 +40,12 @@ func TestNormalizeSymbol(t *testing.T) {
 		{"Already formatted", "BTC-USDT", "BTC-USDT"},
 		{"Lowercase formatted", "btc-usdt", "BTC-USDT"},
 
+		{"Formatted with another quote", "btc-usdc", "BTC-USDC"},
+
+		// Cross pairs
+		{"Cross pair", "eth/btc", "ETH-BTC"},
+		{"Cross pair with spaces", "ETH / BTC", "ETH-BTC"},
+
 		// Unknown symbols
 		{"Unknown symbol", "UNKNOWN", "UNKNOWN-USDT"},
 		{"Another unknown", "XYZ", "XYZ-USDT"},
@@ -48,13 +57,82 @@ func TestNormalizeSymbol(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := normalizeSymbol(tt.input); got != tt.want {
+			if got := normalizeSymbol(tt.input, DefaultQuoteCurrency); got != tt.want {
 				t.Errorf("normalizeSymbol(%q) = %q, want %q", tt.input, got, tt.want)
 			}
 		})
 	}
 }
 
+func TestTransformWitResponseWith_QuoteCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		quote    string
+		entities map[string][]WitAIEntity
+		want     string
+	}{
+		{
+			name:     "Configured quote",
+			quote:    "USDC",
+			entities: map[string][]WitAIEntity{"symbol": {{Value: "btc"}}},
+			want:     "BTC-USDC",
+		},
+		{
+			name:     "Explicit quote wins",
+			quote:    "USDC",
+			entities: map[string][]WitAIEntity{"symbol": {{Value: "btc"}}, "quote_currency": {{Value: "dollars"}}},
+			want:     "BTC-USD",
+		},
+		{
+			name:     "Explicit quote over default",
+			quote:    DefaultQuoteCurrency,
+			entities: map[string][]WitAIEntity{"symbol": {{Value: "ethereum"}}, "quote_currency": {{Value: "usdc"}}},
+			want:     "ETH-USDC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &WitAIResponse{
+				Intents:  []WitAIIntent{{Name: "view_price", Confidence: 0.95}},
+				Entities: tt.entities,
+			}
+			got := transformWitResponseWith(resp, "price", transformConfig{quote: tt.quote})
+			if got.Symbol != tt.want {
+				t.Errorf("Symbol = %q, want %q", got.Symbol, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformWitResponseWith_QuoteCurrencyEverySymbol(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "close_all", Confidence: 0.95}},
+		Entities: map[string][]WitAIEntity{
+			"exclude_symbol":   {{Value: "eth"}, {Value: "sol"}},
+			"condition_symbol": {{Value: "btc"}},
+			"quote_currency":   {{Value: "usdc"}},
+		},
+	}
+	got := transformWitResponseWith(resp, "close all except ETH and SOL in USDC if BTC drops", transformConfig{quote: DefaultQuoteCurrency})
+
+	if !reflect.DeepEqual(got.ExcludeSymbols, []string{"ETH-USDC", "SOL-USDC"}) {
+		t.Errorf("ExcludeSymbols = %v, want [ETH-USDC SOL-USDC]", got.ExcludeSymbols)
+	}
+	if got.Condition == nil || got.Condition.Symbol != "BTC-USDC" {
+		t.Errorf("Condition = %+v, want symbol BTC-USDC", got.Condition)
+	}
+	if got.Symbol != "" {
+		t.Errorf("Symbol = %q, want empty", got.Symbol)
+	}
+}
+
+func TestNew_EmptyQuoteCurrency(t *testing.T) {
+	if _, err := New("token", WithQuoteCurrency(" ")); err == nil {
+		t.Error("New() with an empty quote currency succeeded, want error")
+	}
+}
+
 func TestNormalizeSide(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -111,6 +189,177 @@ func TestNormalizeSide(t *testing.T) {
 	}
 }
 
+func TestNormalizeConditionOperator(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   intent.ConditionOperator
+		wantOK bool
+	}{
+		{"above", intent.ConditionAbove, true},
+		{">=", intent.ConditionAbove, true},
+		{"breaks", intent.ConditionAbove, true},
+		{"Crosses  above", intent.ConditionAbove, true},
+		{"rompe", intent.ConditionAbove, true},
+		{"supera", intent.ConditionAbove, true},
+		{"below", intent.ConditionBelow, true},
+		{"drops below", intent.ConditionBelow, true},
+		{"breaks down", intent.ConditionBelow, true},
+		{"cae por debajo de", intent.ConditionBelow, true},
+		{"perfora", intent.ConditionBelow, true},
+		{"crosses", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := normalizeConditionOperator(tt.input)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("normalizeConditionOperator(%q) = (%q, %v), want (%q, %v)", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNormalizeOrderType(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   intent.OrderType
+		wantOK bool
+	}{
+		{"market", intent.OrderTypeMarket, true},
+		{"a mercado", intent.OrderTypeMarket, true},
+		{"Limit", intent.OrderTypeLimit, true},
+		{"límite", intent.OrderTypeLimit, true},
+		{"stop-limit", intent.OrderTypeStopLimit, true},
+		{"Stop Limit", intent.OrderTypeStopLimit, true},
+		{"iceberg", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := normalizeOrderType(tt.input)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("normalizeOrderType(%q) = (%q, %v), want (%q, %v)", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNormalizeAccount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"scalping", "scalping"},
+		{"my Scalping account", "scalping"},
+		{"mi cuenta de scalping", "scalping"},
+		{"the long term subaccount", "long term"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeAccount(tt.input); got != tt.want {
+			t.Errorf("normalizeAccount(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeTimeInForce(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   intent.TimeInForce
+		wantOK bool
+	}{
+		{"GTC", intent.TimeInForceGTC, true},
+		{"good till cancelled", intent.TimeInForceGTC, true},
+		{"IOC", intent.TimeInForceIOC, true},
+		{"fill-or-kill", intent.TimeInForceFOK, true},
+		{"todo o nada", intent.TimeInForceFOK, true},
+		{"post only", intent.TimeInForcePostOnly, true},
+		{"Post-Only", intent.TimeInForcePostOnly, true},
+		{"gtd", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := normalizeTimeInForce(tt.input)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("normalizeTimeInForce(%q) = (%q, %v), want (%q, %v)", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseReduceOnly(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   bool
+		wantOK bool
+	}{
+		{"reduce only", true, true},
+		{"Reduce-Only", true, true},
+		{"solo reducir", true, true},
+		{"sólo reducir", true, true},
+		{"not reduce only", false, true},
+		{"no solo reducir", false, true},
+		{"maybe", false, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseReduceOnly(tt.input)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseReduceOnly(%q) = (%v, %v), want (%v, %v)", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNormalizeMarginMode(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   intent.MarginMode
+		wantOK bool
+	}{
+		{"cross", intent.MarginModeCross, true},
+		{"Cross-Margin", intent.MarginModeCross, true},
+		{"cruzado", intent.MarginModeCross, true},
+		{"isolated", intent.MarginModeIsolated, true},
+		{"Aislado", intent.MarginModeIsolated, true},
+		{"margen aislado", intent.MarginModeIsolated, true},
+		{"portfolio", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := normalizeMarginMode(tt.input)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("normalizeMarginMode(%q) = (%q, %v), want (%q, %v)", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNormalizeTimeframe(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{"4h", "4h", true},
+		{"4H", "4h", true},
+		{"4 hours", "4h", true},
+		{"4hs", "4h", true},
+		{"15 min", "15m", true},
+		{"15 minutos", "15m", true},
+		{"the 1 hour chart", "1h", true},
+		{"hour", "1h", true},
+		{"una hora", "1h", true},
+		{"daily", "1d", true},
+		{"diario", "1d", true},
+		{"1 día", "1d", true},
+		{"semanal", "1w", true},
+		{"7m", "7m", true},
+		{"0h", "", false},
+		{"fortnight", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := normalizeTimeframe(tt.input)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("normalizeTimeframe(%q) = (%q, %v), want (%q, %v)", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
 func TestMapWitIntent(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -125,6 +374,29 @@ func TestMapWitIntent(t *testing.T) {
 		{"check_balance", "check_balance", intent.IntentCheckBalance},
 		{"break_even", "break_even", intent.IntentBreakEven},
 		{"trailing_stop", "trailing_stop", intent.IntentTrailingStop},
+		{"copy_trade", "copy_trade", intent.IntentCopyTrade},
+		{"setup_grid", "setup_grid", intent.IntentSetupGrid},
+		{"view_alerts", "view_alerts", intent.IntentViewAlerts},
+		{"cancel_alert", "cancel_alert", intent.IntentCancelAlert},
+		{"set_risk_defaults", "set_risk_defaults", intent.IntentSetRiskDefaults},
+		{"modify_position", "modify_position", intent.IntentModifyPosition},
+		{"set_leverage", "set_leverage", intent.IntentSetLeverage},
+		{"dca_order", "dca_order", intent.IntentDCAOrder},
+		{"set_alert", "set_alert", intent.IntentSetAlert},
+		{"view_pnl", "view_pnl", intent.IntentViewPnL},
+		{"view_price", "view_price", intent.IntentViewPrice},
+		{"view_funding", "view_funding", intent.IntentViewFunding},
+		{"hedge_position", "hedge_position", intent.IntentHedgePosition},
+		{"move_stop_loss", "move_stop_loss", intent.IntentMoveStopLoss},
+		{"view_history", "view_history", intent.IntentViewHistory},
+		{"check_order_status", "check_order_status", intent.IntentCheckOrderStatus},
+		{"view_position", "view_position", intent.IntentViewPosition},
+		{"panic_close", "panic_close", intent.IntentPanicClose},
+		{"set_take_profit", "set_take_profit", intent.IntentSetTakeProfit},
+		{"set_stop_loss", "set_stop_loss", intent.IntentSetStopLoss},
+		{"help", "help", intent.IntentHelp},
+		{"close_all", "close_all", intent.IntentCloseAll},
+		{"withdraw", "withdraw", intent.IntentWithdraw},
 		{"unknown", "unknown_intent", intent.IntentUnknown},
 		{"empty", "", intent.IntentUnknown},
 	}
@@ -190,6 +462,14 @@ func TestParseTPLevels(t *testing.T) {
 			input: "46000",
 			want:  []types.TPLevel{},
 		},
+		{
+			name:  "Shorthand prices",
+			input: "45k:30, 47.5k:70",
+			want: []types.TPLevel{
+				{Price: 45000.0, Percentage: 30.0},
+				{Price: 47500.0, Percentage: 70.0},
+			},
+		},
 		{
 			name:  "Invalid format - non-numeric",
 			input: "abc:def",
@@ -212,7 +492,7 @@ func TestParseTPLevels(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := parseTPLevels(tt.input)
+			got := parseTPLevels(tt.input, false)
 			if len(got) != len(tt.want) {
 				t.Fatalf("parseTPLevels(%q) returned %d levels, want %d", tt.input, len(got), len(tt.want))
 			}
@@ -413,3 +693,928 @@ func TestTransformWitResponse_ExtractEntities(t *testing.T) {
 		t.Errorf("TriggerPrice = %v, want 3050", got.TriggerPrice)
 	}
 }
+
+func TestTransformWitResponse_EntityMeta(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "open_position", Confidence: 0.95}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":          {{Value: "BTC", Body: "btc", Start: 10, End: 13, Confidence: 0.99}},
+			"price:stop_loss": {{Value: "44500", Body: "44500", Start: 29, End: 34, Confidence: 0.52}},
+			"sentiment":       {{Value: "positive", Body: "please", Start: 0, End: 6, Confidence: 0.9}},
+		},
+	}
+
+	got := transformWitResponse(resp, "open long btc at 45000 stop 44500")
+
+	want := map[string]intent.EntitySpan{
+		"symbol":          {Start: 10, End: 13, Body: "btc", Confidence: 0.99},
+		"price:stop_loss": {Start: 29, End: 34, Body: "44500", Confidence: 0.52},
+	}
+	if !reflect.DeepEqual(got.EntityMeta, want) {
+		t.Errorf("EntityMeta = %+v, want %+v", got.EntityMeta, want)
+	}
+}
+
+func TestTransformWitResponse_CopyTrade(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{
+			{Name: "copy_trade", Confidence: 0.91},
+		},
+		Entities: map[string][]WitAIEntity{
+			"symbol":         {{Value: "btc"}},
+			"source_account": {{Value: "main"}},
+			"target_account": {{Value: " second "}},
+			"size_factor":    {{Value: "0.5"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "copy whatever I do on BTC into my second account at half size")
+
+	if got.Intent != intent.IntentCopyTrade {
+		t.Errorf("Intent = %v, want %v", got.Intent, intent.IntentCopyTrade)
+	}
+	if got.Symbol != "BTC-USDT" {
+		t.Errorf("Symbol = %q, want %q", got.Symbol, "BTC-USDT")
+	}
+	if got.SourceAccount != "main" {
+		t.Errorf("SourceAccount = %q, want %q", got.SourceAccount, "main")
+	}
+	if got.TargetAccount != "second" {
+		t.Errorf("TargetAccount = %q, want %q", got.TargetAccount, "second")
+	}
+	if got.SizeFactor == nil || *got.SizeFactor != 0.5 {
+		t.Errorf("SizeFactor = %v, want 0.5", got.SizeFactor)
+	}
+}
+
+func TestTransformWitResponse_CopyTradeSizePhrases(t *testing.T) {
+	tests := []struct {
+		name   string
+		entity string
+		input  string
+		want   float64
+	}{
+		{"Phrase in the text", "", "copy whatever I do on BTC into my second account at half size", 0.5},
+		{"Phrase as the entity", "half size", "mirror BTC into second at half size", 0.5},
+		{"Bare word entity", "half", "mirror BTC into second, half", 0.5},
+		{"Percentage entity", "25%", "mirror BTC into second at 25%", 0.25},
+		{"Spanish", "", "copiá mis trades de BTC en la segunda cuenta a la mitad", 0.5},
+		{"Double", "", "copy BTC into second at double size", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &WitAIResponse{
+				Intents: []WitAIIntent{{Name: "copy_trade", Confidence: 0.9}},
+				Entities: map[string][]WitAIEntity{
+					"symbol":         {{Value: "btc"}},
+					"target_account": {{Value: "second"}},
+				},
+			}
+			if tt.entity != "" {
+				resp.Entities["size_factor"] = []WitAIEntity{{Value: tt.entity}}
+			}
+
+			got := transformWitResponse(resp, tt.input)
+			if got.SizeFactor == nil || *got.SizeFactor != tt.want {
+				t.Errorf("SizeFactor = %v, want %v", got.SizeFactor, tt.want)
+			}
+		})
+	}
+
+	// "half" alone in the text is not a size, e.g. "close half"
+	resp := &WitAIResponse{Intents: []WitAIIntent{{Name: "copy_trade", Confidence: 0.9}}, Entities: map[string][]WitAIEntity{}}
+	if got := transformWitResponse(resp, "copy half of my BTC trades into second"); got.SizeFactor != nil {
+		t.Errorf("SizeFactor = %v, want nil", *got.SizeFactor)
+	}
+}
+
+func TestParseSLLevels(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []intent.SLLevel
+	}{
+		{"44500:50,44000:50", []intent.SLLevel{{Price: 44500, Percentage: 50}, {Price: 44000, Percentage: 50}}},
+		{"SL 44500:50, 44000:50", []intent.SLLevel{{Price: 44500, Percentage: 50}, {Price: 44000, Percentage: 50}}},
+		{"stop loss 2900:100", []intent.SLLevel{{Price: 2900, Percentage: 100}}},
+		{"44500", nil},
+	}
+
+	for _, tt := range tests {
+		if got := parseSLLevels(tt.input, false); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSLLevels(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseEntryLevels(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []intent.EntryLevel
+	}{
+		{
+			name:  "Shorthand prices",
+			input: "44k, 43.5k and $43000",
+			want: []intent.EntryLevel{
+				{Price: 44000, Percentage: 33.33},
+				{Price: 43500, Percentage: 33.33},
+				{Price: 43000, Percentage: 33.34},
+			},
+		},
+		{
+			name:  "Every comma separates levels",
+			input: "100,200,300",
+			want: []intent.EntryLevel{
+				{Price: 100, Percentage: 33.33},
+				{Price: 200, Percentage: 33.33},
+				{Price: 300, Percentage: 33.34},
+			},
+		},
+		{
+			name:  "Equal split",
+			input: "44000, 43500, 43000",
+			want: []intent.EntryLevel{
+				{Price: 44000, Percentage: 33.33},
+				{Price: 43500, Percentage: 33.33},
+				{Price: 43000, Percentage: 33.34},
+			},
+		},
+		{
+			name:  "Equal split with and",
+			input: "3000 and 2900",
+			want: []intent.EntryLevel{
+				{Price: 3000, Percentage: 50},
+				{Price: 2900, Percentage: 50},
+			},
+		},
+		{
+			name:  "Explicit percentages",
+			input: "3000:50, 2900:30 y 2800:20%",
+			want: []intent.EntryLevel{
+				{Price: 3000, Percentage: 50},
+				{Price: 2900, Percentage: 30},
+				{Price: 2800, Percentage: 20},
+			},
+		},
+		{
+			name:  "Invalid entries skipped",
+			input: "44000:60,abc,43000:40",
+			want: []intent.EntryLevel{
+				{Price: 44000, Percentage: 60},
+				{Price: 43000, Percentage: 40},
+			},
+		},
+		{
+			name:  "Empty string",
+			input: "",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseEntryLevels(tt.input, false)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEntryLevels(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGridRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantLower float64
+		wantUpper float64
+		wantOK    bool
+	}{
+		{"Dash", "42000-46000", 42000, 46000, true},
+		{"Grouped", "42,000-46,000", 42000, 46000, true},
+		{"Shorthand", "42k to 46.5k", 42000, 46500, true},
+		{"Dash with spaces", "42000 - 46000", 42000, 46000, true},
+		{"English to", "42000 to 46000", 42000, 46000, true},
+		{"Spanish a", "42000 a 46000", 42000, 46000, true},
+		{"Reversed", "46000-42000", 42000, 46000, true},
+		{"Decimals", "0.5-0.75", 0.5, 0.75, true},
+		{"Single value", "42000", 0, 0, false},
+		{"Non-numeric", "low-high", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lower, upper, ok := parseGridRange(tt.input, false)
+			if ok != tt.wantOK {
+				t.Fatalf("parseGridRange(%q) ok = %v, want %v", tt.input, ok, tt.wantOK)
+			}
+			if lower != tt.wantLower || upper != tt.wantUpper {
+				t.Errorf("parseGridRange(%q) = (%v, %v), want (%v, %v)", tt.input, lower, upper, tt.wantLower, tt.wantUpper)
+			}
+		})
+	}
+}
+
+func TestLadders_DecimalCommaLocale(t *testing.T) {
+	if got := parseEntryLevels("44.000 y 43.000", true); !reflect.DeepEqual(got, []intent.EntryLevel{
+		{Price: 44000, Percentage: 50},
+		{Price: 43000, Percentage: 50},
+	}) {
+		t.Errorf("parseEntryLevels = %v, want 44000 and 43000 at 50%% each", got)
+	}
+	if got := parseSLLevels("SL 44.500:50, 44.000:50", true); !reflect.DeepEqual(got, []intent.SLLevel{
+		{Price: 44500, Percentage: 50},
+		{Price: 44000, Percentage: 50},
+	}) {
+		t.Errorf("parseSLLevels = %v, want 44500 and 44000 at 50%% each", got)
+	}
+	if lower, upper, ok := parseGridRange("42.000 a 46.000", true); !ok || lower != 42000 || upper != 46000 {
+		t.Errorf("parseGridRange = (%v, %v, %v), want (42000, 46000, true)", lower, upper, ok)
+	}
+}
+
+func TestTransformWitResponse_SetupGrid(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{
+			{Name: "setup_grid", Confidence: 0.93},
+		},
+		Entities: map[string][]WitAIEntity{
+			"symbol":      {{Value: "btc"}},
+			"grid_range":  {{Value: "42000-46000"}},
+			"grid_levels": {{Value: "10"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "grid BTC 42000-46000, 10 levels")
+
+	if got.Intent != intent.IntentSetupGrid {
+		t.Errorf("Intent = %v, want %v", got.Intent, intent.IntentSetupGrid)
+	}
+	if got.GridLower == nil || *got.GridLower != 42000 {
+		t.Errorf("GridLower = %v, want 42000", got.GridLower)
+	}
+	if got.GridUpper == nil || *got.GridUpper != 46000 {
+		t.Errorf("GridUpper = %v, want 46000", got.GridUpper)
+	}
+	if got.GridLevels == nil || *got.GridLevels != 10 {
+		t.Errorf("GridLevels = %v, want 10", got.GridLevels)
+	}
+}
+
+func TestTransformWitResponse_Withdraw(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{
+			{Name: "withdraw", Confidence: 0.9},
+		},
+		Entities: map[string][]WitAIEntity{
+			"asset":       {{Value: "usdt"}},
+			"amount":      {{Value: "250"}},
+			"address_ref": {{Value: "ledger"}},
+			"address":     {{Value: "0x71C7656EC7ab88b098defB751B7401B5f6d8976F"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "withdraw 250 usdt to my ledger")
+
+	if got.Asset != "USDT" {
+		t.Errorf("Asset = %q, want %q", got.Asset, "USDT")
+	}
+	if got.Amount == nil || *got.Amount != 250 {
+		t.Errorf("Amount = %v, want 250", got.Amount)
+	}
+	if got.AddressRef != "ledger" {
+		t.Errorf("AddressRef = %q, want %q (raw address entities must be ignored)", got.AddressRef, "ledger")
+	}
+}
+
+func TestTransformWitResponse_ClosePercent(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "close_position", Confidence: 0.95}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":        {{Value: "eth"}},
+			"close_percent": {{Value: "50%"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "close 50% of ETH")
+
+	if got.Symbol != "ETH-USDT" {
+		t.Errorf("Symbol = %q, want %q", got.Symbol, "ETH-USDT")
+	}
+	if got.ClosePercent == nil || *got.ClosePercent != 50 {
+		t.Errorf("ClosePercent = %v, want 50", got.ClosePercent)
+	}
+}
+
+func TestTransformWitResponse_Quantity(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "open_position", Confidence: 0.95}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":   {{Value: "btc"}},
+			"quantity": {{Value: "0.5"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "buy 0.5 BTC at 45000 with stop 44000")
+
+	if got.Quantity == nil || *got.Quantity != 0.5 {
+		t.Errorf("Quantity = %v, want 0.5", got.Quantity)
+	}
+	if got.RiskPercent != nil {
+		t.Errorf("RiskPercent = %v, want nil", *got.RiskPercent)
+	}
+}
+
+func TestTransformWitResponse_Notional(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "open_position", Confidence: 0.95}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":   {{Value: "btc"}},
+			"notional": {{Value: "$500"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "buy $500 of BTC")
+	if got.NotionalUSD == nil || *got.NotionalUSD != 500 {
+		t.Errorf("NotionalUSD = %v, want 500", got.NotionalUSD)
+	}
+}
+
+func TestTransformWitResponse_PostOnlyLimit(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "open_position", Confidence: 0.95}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":        {{Value: "btc"}},
+			"order_type":    {{Value: "limit"}},
+			"time_in_force": {{Value: "post only"}},
+			"entry_price":   {{Value: "45000"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "post only limit long BTC at 45000")
+
+	if got.OrderType != intent.OrderTypeLimit {
+		t.Errorf("OrderType = %q, want %q", got.OrderType, intent.OrderTypeLimit)
+	}
+	if got.TimeInForce != intent.TimeInForcePostOnly {
+		t.Errorf("TimeInForce = %q, want %q", got.TimeInForce, intent.TimeInForcePostOnly)
+	}
+}
+
+func TestTransformWitResponse_SetLeverage(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		ok    bool
+	}{
+		{"10x", 10, true},
+		{"10", 10, true},
+		{"X25", 25, true},
+		{" 2.5 x ", 2.5, true},
+		{"max", 0, false},
+	}
+
+	for _, tt := range tests {
+		resp := &WitAIResponse{
+			Intents: []WitAIIntent{{Name: "set_leverage", Confidence: 0.93}},
+			Entities: map[string][]WitAIEntity{
+				"symbol":   {{Value: "btc"}},
+				"leverage": {{Value: tt.value}},
+			},
+		}
+
+		got := transformWitResponse(resp, "set BTC leverage to "+tt.value)
+
+		if got.Intent != intent.IntentSetLeverage {
+			t.Errorf("Intent = %v, want %v", got.Intent, intent.IntentSetLeverage)
+		}
+		if !tt.ok {
+			if got.Leverage != nil {
+				t.Errorf("Leverage(%q) = %v, want nil", tt.value, *got.Leverage)
+			}
+			continue
+		}
+		if got.Leverage == nil || *got.Leverage != tt.want {
+			t.Errorf("Leverage(%q) = %v, want %v", tt.value, got.Leverage, tt.want)
+		}
+	}
+}
+
+func TestTransformWitResponse_SetAlert(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "set_alert", Confidence: 0.91}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":          {{Value: "btc"}},
+			"alert_price":     {{Value: "50000"}},
+			"alert_direction": {{Value: "above"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "alert me when BTC goes above 50000")
+
+	if got.Intent != intent.IntentSetAlert {
+		t.Errorf("Intent = %v, want %v", got.Intent, intent.IntentSetAlert)
+	}
+	if got.AlertPrice == nil || *got.AlertPrice != 50000 {
+		t.Errorf("AlertPrice = %v, want 50000", got.AlertPrice)
+	}
+	if got.AlertDirection != intent.ConditionAbove {
+		t.Errorf("AlertDirection = %q, want %q", got.AlertDirection, intent.ConditionAbove)
+	}
+}
+
+func TestTransformWitResponse_ViewPnL(t *testing.T) {
+	tests := []struct {
+		value string
+		want  intent.Period
+	}{
+		{"this week", intent.PeriodThisWeek},
+		{"Today", intent.PeriodToday},
+		{"el mes pasado", intent.PeriodLastMonth},
+		{"next decade", ""},
+	}
+
+	for _, tt := range tests {
+		resp := &WitAIResponse{
+			Intents: []WitAIIntent{{Name: "view_pnl", Confidence: 0.93}},
+			Entities: map[string][]WitAIEntity{
+				"symbol": {{Value: "eth"}},
+				"period": {{Value: tt.value}},
+			},
+		}
+
+		got := transformWitResponse(resp, "how much did I make on ETH "+tt.value)
+
+		if got.Intent != intent.IntentViewPnL {
+			t.Errorf("Intent = %v, want %v", got.Intent, intent.IntentViewPnL)
+		}
+		if got.Symbol != "ETH-USDT" {
+			t.Errorf("Symbol = %q, want ETH-USDT", got.Symbol)
+		}
+		if got.Period != tt.want {
+			t.Errorf("Period(%q) = %q, want %q", tt.value, got.Period, tt.want)
+		}
+	}
+}
+
+func TestTransformWitResponse_ViewPrice(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "view_price", Confidence: 0.95}},
+		Entities: map[string][]WitAIEntity{
+			"symbol": {{Value: "ETH"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "what's the price of ETH")
+
+	if got.Intent != intent.IntentViewPrice {
+		t.Errorf("Intent = %v, want %v", got.Intent, intent.IntentViewPrice)
+	}
+	if got.Symbol != "ETH-USDT" {
+		t.Errorf("Symbol = %q, want ETH-USDT", got.Symbol)
+	}
+}
+
+func TestTransformWitResponse_ViewFunding(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "view_funding", Confidence: 0.9}},
+		Entities: map[string][]WitAIEntity{
+			"symbol": {{Value: "btc"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "what's the funding on BTC")
+
+	if got.Intent != intent.IntentViewFunding {
+		t.Errorf("Intent = %v, want %v", got.Intent, intent.IntentViewFunding)
+	}
+	if got.Symbol != "BTC-USDT" {
+		t.Errorf("Symbol = %q, want BTC-USDT", got.Symbol)
+	}
+}
+
+func TestTransformWitResponse_CancelOrders(t *testing.T) {
+	one := transformWitResponse(&WitAIResponse{
+		Intents:  []WitAIIntent{{Name: "cancel_orders", Confidence: 0.94}},
+		Entities: map[string][]WitAIEntity{"order_id": {{Value: " 12345 "}}},
+	}, "cancel order 12345")
+	if one.OrderID != "12345" || one.CancelAll {
+		t.Errorf("OrderID = %q, CancelAll = %v, want 12345 and false", one.OrderID, one.CancelAll)
+	}
+
+	all := transformWitResponse(&WitAIResponse{
+		Intents:  []WitAIIntent{{Name: "cancel_orders", Confidence: 0.94}},
+		Entities: map[string][]WitAIEntity{"scope": {{Value: "todas"}}},
+	}, "cancelar todas las órdenes")
+	if all.OrderID != "" || !all.CancelAll {
+		t.Errorf("OrderID = %q, CancelAll = %v, want empty and true", all.OrderID, all.CancelAll)
+	}
+}
+
+func TestTransformWitResponse_CheckOrderStatus(t *testing.T) {
+	latest := transformWitResponse(&WitAIResponse{
+		Intents:  []WitAIIntent{{Name: "check_order_status", Confidence: 0.92}},
+		Entities: map[string][]WitAIEntity{"scope": {{Value: "última"}}, "symbol": {{Value: "btc"}}},
+	}, "se ejecutó mi última orden de BTC?")
+	if latest.Intent != intent.IntentCheckOrderStatus {
+		t.Errorf("Intent = %v, want %v", latest.Intent, intent.IntentCheckOrderStatus)
+	}
+	if !latest.LatestOrder || latest.CancelAll || latest.Symbol != "BTC-USDT" {
+		t.Errorf("LatestOrder = %v, CancelAll = %v, Symbol = %q, want true, false and BTC-USDT",
+			latest.LatestOrder, latest.CancelAll, latest.Symbol)
+	}
+
+	byID := transformWitResponse(&WitAIResponse{
+		Intents:  []WitAIIntent{{Name: "check_order_status", Confidence: 0.92}},
+		Entities: map[string][]WitAIEntity{"order_id": {{Value: "12345"}}},
+	}, "did order 12345 fill?")
+	if byID.OrderID != "12345" || byID.LatestOrder {
+		t.Errorf("OrderID = %q, LatestOrder = %v, want 12345 and false", byID.OrderID, byID.LatestOrder)
+	}
+}
+
+func TestTransformWitResponse_ViewPosition(t *testing.T) {
+	tests := []struct {
+		name   string
+		intent string
+		symbol string
+		input  string
+		want   intent.Intent
+	}{
+		{"Detail intent", "view_position", "btc", "how's my BTC trade doing", intent.IntentViewPosition},
+		{"Unrealized PnL", "view_pnl", "eth", "unrealized PnL on ETH", intent.IntentViewPosition},
+		{"Spanish phrasing", "view_pnl", "btc", "cómo va mi long de BTC", intent.IntentViewPosition},
+		{"Unrealized without symbol", "view_pnl", "", "what's my floating PnL", intent.IntentViewPositions},
+		{"Realized PnL", "view_pnl", "eth", "PnL on ETH today", intent.IntentViewPnL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &WitAIResponse{
+				Intents:  []WitAIIntent{{Name: tt.intent, Confidence: 0.9}},
+				Entities: map[string][]WitAIEntity{},
+			}
+			if tt.symbol != "" {
+				resp.Entities["symbol"] = []WitAIEntity{{Value: tt.symbol}}
+			}
+
+			if got := transformWitResponse(resp, tt.input); got.Intent != tt.want {
+				t.Errorf("Intent = %v, want %v", got.Intent, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformWitResponse_SetTakeProfit(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "set_take_profit", Confidence: 0.93}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":      {{Value: "eth"}},
+			"take_profit": {{Value: "3200"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "set TP for my ETH at 3200")
+
+	if got.Intent != intent.IntentSetTakeProfit {
+		t.Errorf("Intent = %v, want %v", got.Intent, intent.IntentSetTakeProfit)
+	}
+	if got.Symbol != "ETH-USDT" || got.TakeProfit == nil || *got.TakeProfit != 3200 {
+		t.Errorf("Symbol = %q, TakeProfit = %v, want ETH-USDT at 3200", got.Symbol, got.TakeProfit)
+	}
+}
+
+func TestTransformWitResponse_SetStopLoss(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "set_stop_loss", Confidence: 0.94}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":          {{Value: "btc"}},
+			"price:stop_loss": {{Value: "44000"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "put a stop on my BTC at 44000")
+
+	if got.Intent != intent.IntentSetStopLoss {
+		t.Errorf("Intent = %v, want %v", got.Intent, intent.IntentSetStopLoss)
+	}
+	if got.Symbol != "BTC-USDT" || got.StopLoss == nil || *got.StopLoss != 44000 {
+		t.Errorf("Symbol = %q, StopLoss = %v, want BTC-USDT at 44000", got.Symbol, got.StopLoss)
+	}
+}
+
+func TestMapWitIntent_Namespaced(t *testing.T) {
+	transfer := intent.Namespaced("witai-test", "fund_transfer")
+	if got := mapWitIntent(string(transfer)); got != intent.IntentUnknown {
+		t.Errorf("unregistered %s mapped to %v, want unknown", transfer, got)
+	}
+
+	intent.RegisterIntent(intent.IntentSpec{Intent: transfer})
+	if got := mapWitIntent(string(transfer)); got != transfer {
+		t.Errorf("mapWitIntent(%q) = %v, want %v", transfer, got, transfer)
+	}
+}
+
+func TestTransformWitResponse_CloseAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		intent   string
+		symbol   string
+		excluded []string
+		input    string
+		want     intent.Intent
+	}{
+		{"Close all intent", "close_all", "", []string{"eth", "sol"}, "close everything except ETH and SOL", intent.IntentCloseAll},
+		{"Close everything", "close_position", "", nil, "close everything", intent.IntentCloseAll},
+		{"Spanish phrasing", "close_position", "", nil, "cerrar todo", intent.IntentCloseAll},
+		{"Close without symbol", "close_position", "", nil, "close my position", intent.IntentClosePosition},
+		{"Close one symbol", "close_position", "btc", nil, "close all my BTC", intent.IntentClosePosition},
+		{"Close everything now", "close_position", "", nil, "please close everything now", intent.IntentCloseAll},
+		{"Close all positions", "close_position", "", nil, "close all of my positions", intent.IntentCloseAll},
+		{"Todo as a word in passing", "close_position", "", nil, "close the position, todo bien", intent.IntentClosePosition},
+		{"Everything narrowed later", "close_position", "", nil, "close everything on BTC later", intent.IntentClosePosition},
+		{"Substring of another word", "close_position", "", nil, "close my todolist trade", intent.IntentClosePosition},
+		{"Phrase not the object", "close_position", "", nil, "everything is red, close it", intent.IntentClosePosition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &WitAIResponse{
+				Intents:  []WitAIIntent{{Name: tt.intent, Confidence: 0.9}},
+				Entities: map[string][]WitAIEntity{},
+			}
+			if tt.symbol != "" {
+				resp.Entities["symbol"] = []WitAIEntity{{Value: tt.symbol}}
+			}
+			for _, excluded := range tt.excluded {
+				resp.Entities["exclude_symbol"] = append(resp.Entities["exclude_symbol"], WitAIEntity{Value: excluded})
+			}
+
+			got := transformWitResponse(resp, tt.input)
+			if got.Intent != tt.want {
+				t.Errorf("Intent = %v, want %v", got.Intent, tt.want)
+			}
+			if tt.want == intent.IntentClosePosition && tt.symbol == "" {
+				validators.ValidateCommand(got)
+				if !slices.Contains(got.Missing, "symbol") {
+					t.Errorf("Missing = %v, want symbol flagged", got.Missing)
+				}
+			}
+			if len(tt.excluded) > 0 && !reflect.DeepEqual(got.ExcludeSymbols, []string{"ETH-USDT", "SOL-USDT"}) {
+				t.Errorf("ExcludeSymbols = %v, want [ETH-USDT SOL-USDT]", got.ExcludeSymbols)
+			}
+		})
+	}
+}
+
+func TestTransformWitResponse_HedgePosition(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+	}{
+		{"0.5", 0.5},
+		{"50%", 0.5},
+		{"25%", 0.25},
+		{"1", 1},
+	}
+
+	for _, tt := range tests {
+		resp := &WitAIResponse{
+			Intents: []WitAIIntent{{Name: "hedge_position", Confidence: 0.9}},
+			Entities: map[string][]WitAIEntity{
+				"symbol":      {{Value: "btc"}},
+				"side":        {{Value: "long"}},
+				"hedge_ratio": {{Value: tt.value}},
+			},
+		}
+
+		got := transformWitResponse(resp, "hedge "+tt.value+" of my BTC long")
+
+		if got.Intent != intent.IntentHedgePosition {
+			t.Errorf("Intent = %v, want %v", got.Intent, intent.IntentHedgePosition)
+		}
+		if got.Symbol != "BTC-USDT" {
+			t.Errorf("Symbol = %q, want BTC-USDT", got.Symbol)
+		}
+		if got.HedgeRatio == nil || *got.HedgeRatio != tt.want {
+			t.Errorf("HedgeRatio(%q) = %v, want %v", tt.value, got.HedgeRatio, tt.want)
+		}
+	}
+}
+
+func TestParseRatio(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   float64
+		wantOK bool
+	}{
+		{"0.5", 0.5, true},
+		{"1.5", 1.5, true},
+		{"50%", 0.5, true},
+		{"50 %", 0.5, true},
+		{"150%", 1.5, true},
+		{"50", 50, true},
+		{"half", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRatio(tt.input)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseRatio(%q) = (%v, %v), want (%v, %v)", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestTransformWitResponse_MoveStopLoss(t *testing.T) {
+	absolute := transformWitResponse(&WitAIResponse{
+		Intents: []WitAIIntent{{Name: "move_stop_loss", Confidence: 0.92}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":    {{Value: "btc"}},
+			"stop_loss": {{Value: "44800"}},
+		},
+	}, "move my BTC stop to 44800")
+	if absolute.Intent != intent.IntentMoveStopLoss {
+		t.Errorf("Intent = %v, want %v", absolute.Intent, intent.IntentMoveStopLoss)
+	}
+	if absolute.StopLoss == nil || *absolute.StopLoss != 44800 {
+		t.Errorf("StopLoss = %v, want 44800", absolute.StopLoss)
+	}
+
+	relative := transformWitResponse(&WitAIResponse{
+		Intents: []WitAIIntent{{Name: "move_stop_loss", Confidence: 0.92}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":           {{Value: "eth"}},
+			"stop_loss_offset": {{Value: "-50"}},
+		},
+	}, "lower my ETH stop by 50")
+	if relative.StopLossOffset == nil || *relative.StopLossOffset != -50 {
+		t.Errorf("StopLossOffset = %v, want -50", relative.StopLossOffset)
+	}
+}
+
+func TestTransformWitResponse_ViewHistory(t *testing.T) {
+	tests := []struct {
+		name     string
+		entity   WitAIEntity
+		from, to string
+	}{
+		{
+			name:   "Interval",
+			entity: WitAIEntity{Type: "interval", From: &WitAITimeBound{Value: "2024-02-26T00:00:00.000-03:00", Grain: "day"}, To: &WitAITimeBound{Value: "2024-03-01T00:00:00.000-03:00", Grain: "day"}},
+			from:   "2024-02-26T00:00:00-03:00",
+			to:     "2024-03-01T00:00:00-03:00",
+		},
+		{
+			name:   "Week grain",
+			entity: WitAIEntity{Type: "value", Value: "2024-02-26T00:00:00.000-03:00", Grain: "week"},
+			from:   "2024-02-26T00:00:00-03:00",
+			to:     "2024-03-04T00:00:00-03:00",
+		},
+		{
+			name:   "Open interval",
+			entity: WitAIEntity{Type: "interval", From: &WitAITimeBound{Value: "2024-03-01T00:00:00.000-03:00", Grain: "day"}},
+			from:   "2024-03-01T00:00:00-03:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &WitAIResponse{
+				Intents:  []WitAIIntent{{Name: "view_history", Confidence: 0.9}},
+				Entities: map[string][]WitAIEntity{"wit$datetime:datetime": {tt.entity}},
+			}
+
+			got := transformWitResponse(resp, "show my trades from last week")
+
+			if got.Intent != intent.IntentViewHistory {
+				t.Errorf("Intent = %v, want %v", got.Intent, intent.IntentViewHistory)
+			}
+			if formatTime(got.From) != tt.from || formatTime(got.To) != tt.to {
+				t.Errorf("range = [%s, %s), want [%s, %s)", formatTime(got.From), formatTime(got.To), tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestTransformWitResponse_ExpiresAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		entity WitAIEntity
+		want   string
+	}{
+		{
+			name:   "Until a day",
+			entity: WitAIEntity{Type: "value", Value: "2024-03-01T00:00:00.000-03:00", Grain: "day"},
+			want:   "2024-03-02T00:00:00-03:00",
+		},
+		{
+			name:   "Exact time",
+			entity: WitAIEntity{Type: "value", Value: "2024-03-01T18:00:00.000-03:00", Grain: "second"},
+			want:   "2024-03-01T18:00:01-03:00",
+		},
+		{
+			name:   "Open interval end",
+			entity: WitAIEntity{Type: "interval", To: &WitAITimeBound{Value: "2024-03-01T00:00:00.000-03:00", Grain: "day"}},
+			want:   "2024-03-01T00:00:00-03:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &WitAIResponse{
+				Intents:  []WitAIIntent{{Name: "open_position", Confidence: 0.9}},
+				Entities: map[string][]WitAIEntity{"wit$datetime:expires_at": {tt.entity}},
+			}
+
+			got := transformWitResponse(resp, "long BTC at 45000 valid until Friday")
+
+			if formatTime(got.ExpiresAt) != tt.want {
+				t.Errorf("ExpiresAt = %s, want %s", formatTime(got.ExpiresAt), tt.want)
+			}
+			if got.From != nil || got.To != nil {
+				t.Errorf("range = [%s, %s), want none", formatTime(got.From), formatTime(got.To))
+			}
+		})
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func TestTransformWitResponse_CrossSymbolCondition(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{
+			{Name: "close_position", Confidence: 0.88},
+		},
+		Entities: map[string][]WitAIEntity{
+			"symbol":             {{Value: "eth"}},
+			"condition_symbol":   {{Value: "ETH/BTC"}},
+			"condition_operator": {{Value: "above"}},
+			"condition_price":    {{Value: "0.06"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "if ETH/BTC breaks 0.06, close my ETH long")
+
+	if got.Symbol != "ETH-USDT" {
+		t.Errorf("Symbol = %q, want %q", got.Symbol, "ETH-USDT")
+	}
+	if got.Condition == nil {
+		t.Fatal("Condition = nil, want condition")
+	}
+	if got.Condition.Symbol != "ETH-BTC" {
+		t.Errorf("Condition.Symbol = %q, want %q", got.Condition.Symbol, "ETH-BTC")
+	}
+	if got.Condition.Operator != intent.ConditionAbove {
+		t.Errorf("Condition.Operator = %q, want %q", got.Condition.Operator, intent.ConditionAbove)
+	}
+	if got.Condition.Price == nil || *got.Condition.Price != 0.06 {
+		t.Errorf("Condition.Price = %v, want 0.06", got.Condition.Price)
+	}
+}
+
+func TestTransformWitResponse_Alternatives(t *testing.T) {
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{
+			{Name: "break_even", Confidence: 0.51},
+			{Name: "close_position", Confidence: 0.44},
+			{Name: "trailing_stop", Confidence: 0.05},
+		},
+		Entities: map[string][]WitAIEntity{
+			"symbol": {{Value: "btc"}},
+		},
+	}
+
+	got := transformWitResponse(resp, "get me out of BTC at entry")
+
+	if got.Intent != intent.IntentBreakEven {
+		t.Errorf("Intent = %v, want %v", got.Intent, intent.IntentBreakEven)
+	}
+
+	want := []intent.IntentCandidate{
+		{Intent: intent.IntentClosePosition, Confidence: 0.44},
+		{Intent: intent.IntentTrailingStop, Confidence: 0.05},
+	}
+	if !reflect.DeepEqual(got.Alternatives, want) {
+		t.Errorf("Alternatives = %v, want %v", got.Alternatives, want)
+	}
+
+	single := transformWitResponse(&WitAIResponse{
+		Intents: []WitAIIntent{{Name: "check_balance", Confidence: 0.99}},
+	}, "balance")
+	if len(single.Alternatives) != 0 {
+		t.Errorf("Alternatives = %v, want none", single.Alternatives)
+	}
+}
+
+func TestCanonicalIntents_AllMapped(t *testing.T) {
+	for _, name := range CanonicalIntents() {
+		if got := mapWitIntent(name); got == intent.IntentUnknown {
+			t.Errorf("canonical intent %q maps to %v", name, got)
+		}
+	}
+}
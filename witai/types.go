@@ -6,6 +6,9 @@ type WitAIResponse struct {
 	Intents  []WitAIIntent            `json:"intents"`
 	Entities map[string][]WitAIEntity `json:"entities"`
 	Traits   map[string][]interface{} `json:"traits"`
+
+	// IsFinal marks the final object of a streamed /speech response
+	IsFinal bool `json:"is_final,omitempty"`
 }
 
 // WitAIIntent represents an intent from Wit.ai
@@ -26,4 +29,16 @@ type WitAIEntity struct {
 	Value      string  `json:"value"`
 	Confidence float64 `json:"confidence"`
 	Type       string  `json:"type"`
+
+	// Built-in wit/datetime entities carry a grain ("day", "week", ...)
+	// for a single value, or From/To bounds when Type is "interval"
+	Grain string          `json:"grain,omitempty"`
+	From  *WitAITimeBound `json:"from,omitempty"`
+	To    *WitAITimeBound `json:"to,omitempty"`
+}
+
+// WitAITimeBound is one end of a wit/datetime interval
+type WitAITimeBound struct {
+	Value string `json:"value"`
+	Grain string `json:"grain,omitempty"`
 }
@@ -2,31 +2,111 @@ package witai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/agatticelli/intent-go"
 	"github.com/agatticelli/intent-go/validators"
 )
 
+const (
+	// defaultBaseURL is the Wit.ai API endpoint
+	defaultBaseURL = "https://api.wit.ai"
+
+	// apiVersion pins the Wit.ai API version sent with every request
+	apiVersion = "20240304"
+)
+
 // Processor implements intent.Processor for Wit.ai
 type Processor struct {
-	token  string
-	client *http.Client
+	token   string
+	baseURL string
+	client  *http.Client
+	metrics intent.MetricsSink
+	events  *intent.EventBus
+
+	logger      *slog.Logger
+	redactInput bool
+
+	concurrency    int
+	policy         validators.Policy
+	shadowPolicy   *validators.Policy
+	validators     *validators.Registry
+	rules          []validators.RuleFunc
+	minConfidence  float64
+	maxInputLength int
+	compoundMin    float64
+	decodeMode     DecodeMode
+	profiling      bool
+	plugins        []intent.Plugin
+
+	exchangeAliases map[string]string
+	meta            *intent.ParseMeta
+	quoteCurrency   string
+	symbolRules     []SymbolRule
+	assetClass      string
+	symbols         map[string]string
+	traits          []string
+
+	clock intent.Clock
+	ids   intent.IDGenerator
 }
 
 // New creates a new Wit.ai NLP processor
-func New(token string) (*Processor, error) {
+func New(token string, opts ...Option) (*Processor, error) {
 	if token == "" {
 		return nil, fmt.Errorf("wit.ai token is required")
 	}
 
-	return &Processor{
-		token:  token,
-		client: &http.Client{Timeout: 10 * time.Second},
-	}, nil
+	p := &Processor{
+		token:   token,
+		baseURL: defaultBaseURL,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		metrics: intent.NopMetrics{},
+		logger:  slog.New(slog.DiscardHandler),
+
+		concurrency: 4,
+		policy:      validators.DefaultPolicy(),
+		validators:  validators.NewRegistry(),
+		plugins:     intent.Plugins(),
+
+		exchangeAliases: DefaultExchangeAliases,
+		quoteCurrency:   DefaultQuoteCurrency,
+		traits:          DefaultTraits,
+
+		clock: intent.SystemClock{},
+		ids:   intent.RandomIDs{},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.quoteCurrency == "" {
+		return nil, fmt.Errorf("quote currency must not be empty")
+	}
+	symbols, err := resolveSymbolRules(p.symbolRules, p.assetClass)
+	if err != nil {
+		return nil, err
+	}
+	p.symbols = symbols
+
+	// Plugin emitters need a bus even when the caller didn't pass one
+	if p.events == nil && slices.ContainsFunc(p.plugins, func(pl intent.Plugin) bool { return pl.Emit != nil }) {
+		p.events = intent.NewEventBus()
+	}
+	intent.SubscribePlugins(p.events, p.plugins)
+
+	p.meta = intent.NewParseMeta(p.Name(), map[string]string{
+		"witai/api":     apiVersion,
+		"witai/symbols": symbolTableVersion,
+	}, p.plugins)
+
+	return p, nil
 }
 
 // Name returns the processor name
@@ -41,50 +121,342 @@ func (p *Processor) SupportedLanguages() []string {
 
 // ParseCommand processes natural language input and returns normalized command
 func (p *Processor) ParseCommand(ctx context.Context, input string) (*intent.NormalizedCommand, error) {
+	start := p.clock.Now()
+	p.metrics.ParseStarted(p.Name())
+	p.events.Publish(intent.ParseStarted{
+		Processor: p.Name(),
+		UserID:    intent.UserIDFromContext(ctx),
+		Input:     p.eventInput(input),
+		Time:      start,
+	})
+
+	callCtx, cancel := intent.ApplyTimeout(ctx)
+	defer cancel()
+
+	message, omitted := intent.ExtractActionable(input, p.maxInputLength)
+	message = joinSpelledTickers(message)
+
 	// Call Wit.ai API
-	witResp, err := p.callWitAI(ctx, input)
+	var witResp *WitAIResponse
+	var err error
+	callStart := p.clock.Now()
+	p.stage(callCtx, "call", "", func(ctx context.Context) {
+		stop := intent.StartProgress(ctx)
+		defer stop()
+		witResp, err = p.callWitAI(ctx, intent.PreProcessInput(ctx, p.plugins, message))
+	})
+	p.events.Publish(intent.BackendCalled{
+		Processor: p.Name(),
+		UserID:    intent.UserIDFromContext(ctx),
+		Latency:   p.since(callStart),
+		Err:       err,
+		Time:      p.clock.Now(),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("wit.ai call failed: %w", err)
+		err = fmt.Errorf("wit.ai call failed: %w", intent.TimeoutError(callCtx, err))
+		p.metrics.ParseFailed(p.Name(), err, p.since(start))
+		p.events.Publish(intent.ParseFailed{
+			Processor: p.Name(),
+			UserID:    intent.UserIDFromContext(ctx),
+			Err:       err,
+			Time:      p.clock.Now(),
+		})
+		return nil, err
+	}
+
+	var cmd *intent.NormalizedCommand
+	if p.isCompound(witResp) {
+		cmd = p.parseCompound(callCtx, ctx, message)
+	}
+	if cmd == nil {
+		cmd = p.buildCommand(ctx, witResp, input)
 	}
+	cmd.Note = omitted
+
+	p.metrics.ParseSucceeded(p.Name(), cmd.Intent, cmd.Confidence, p.since(start))
+	return cmd, nil
+}
+
+// joinSpelledTickers joins tickers spelled out in transcripts ("b t c") so
+// Wit.ai sees them; input without one is sent unchanged
+func joinSpelledTickers(input string) string {
+	joined := intent.JoinSpelledLetters(input)
+	if joined == strings.Join(strings.Fields(input), " ") {
+		return input
+	}
+	return joined
+}
+
+// isCompound reports whether resp ranks a second, different intent at the
+// confidence set with WithCompoundCommands, hinting that the input chains
+// several commands
+func (p *Processor) isCompound(resp *WitAIResponse) bool {
+	return p.compoundMin > 0 && len(resp.Intents) > 1 &&
+		resp.Intents[1].Name != resp.Intents[0].Name &&
+		resp.Intents[1].Confidence >= p.compoundMin
+}
+
+// parseCompound parses each clause of message (see intent.SplitClauses) on
+// its own and returns the first command with the others as SubCommands. It
+// returns nil when message has a single clause, a call fails or a clause
+// doesn't resolve to a known intent, so the caller keeps the whole-input
+// command instead of a lossy split.
+func (p *Processor) parseCompound(callCtx, ctx context.Context, message string) *intent.NormalizedCommand {
+	clauses := intent.SplitClauses(message)
+	if len(clauses) < 2 {
+		return nil
+	}
+
+	responses := make([]*WitAIResponse, len(clauses))
+	for i, clause := range clauses {
+		resp, err := p.callWitAI(callCtx, intent.PreProcessInput(ctx, p.plugins, clause))
+		if err != nil {
+			p.logger.DebugContext(ctx, "wit.ai compound clause failed", slog.Int("clause", i), slog.Any("error", err))
+			return nil
+		}
+		if len(resp.Intents) == 0 || mapWitIntent(resp.Intents[0].Name) == intent.IntentUnknown {
+			return nil
+		}
+		responses[i] = resp
+	}
+
+	// Questions are linked before validation, since a command that refers
+	// to the question's symbol ("open a long if it's under 45k") only
+	// becomes complete once linked
+	commands := make([]*intent.NormalizedCommand, len(responses))
+	for i, resp := range responses {
+		commands[i] = p.transformCommand(ctx, resp, clauses[i])
+	}
+	intent.LinkDependencies(commands)
+	for i, cmd := range commands {
+		p.validateCommand(ctx, cmd, clauses[i])
+	}
+
+	commands[0].SubCommands = commands[1:]
+	return commands[0]
+}
 
+// buildCommand turns a Wit.ai response into a validated NormalizedCommand
+func (p *Processor) buildCommand(ctx context.Context, witResp *WitAIResponse, input string) *intent.NormalizedCommand {
+	cmd := p.transformCommand(ctx, witResp, input)
+	p.validateCommand(ctx, cmd, input)
+	return cmd
+}
+
+// transformCommand turns a Wit.ai response into a NormalizedCommand with
+// its metadata set, ready for validateCommand
+func (p *Processor) transformCommand(ctx context.Context, witResp *WitAIResponse, input string) *intent.NormalizedCommand {
 	// Transform Wit.ai response to NormalizedCommand
-	cmd := transformWitResponse(witResp, input)
+	var cmd *intent.NormalizedCommand
+	p.stage(ctx, "transform", "", func(context.Context) {
+		cmd = transformWitResponseWith(witResp, input, transformConfig{
+			locale:  intent.ParseOptionsFromContext(ctx).Locale,
+			quote:   p.quoteCurrency,
+			symbols: p.symbols,
+			traits:  p.traits,
+			clock:   p.clock,
+		})
+		if cmd.Exchange != "" {
+			cmd.Exchange = resolveExchange(cmd.Exchange, p.exchangeAliases)
+		}
+		intent.PostProcessCommand(ctx, p.plugins, cmd)
+	})
+	cmd.ID = p.ids.NewID()
+	cmd.Meta = p.meta.Clone()
+	cmd.UserID = intent.UserIDFromContext(ctx)
+	return cmd
+}
 
+// since returns the time elapsed since t on the processor's clock
+func (p *Processor) since(t time.Time) time.Duration {
+	return p.clock.Now().Sub(t)
+}
+
+// validateCommand validates cmd, logs it and publishes it as parsed
+func (p *Processor) validateCommand(ctx context.Context, cmd *intent.NormalizedCommand, input string) {
 	// Validate the command
-	validators.ValidateCommand(cmd)
+	p.stage(ctx, "validate", cmd.Intent, func(context.Context) {
+		p.validators.Validate(cmd, p.policy, p.validatorOptions(ctx)...)
+		p.compareShadowPolicy(ctx, cmd)
+		intent.ValidateWithPlugins(p.plugins, cmd)
+		applyMinConfidence(cmd, p.minConfidence)
+	})
 
-	return cmd, nil
+	p.logger.LogAttrs(ctx, slog.LevelDebug, "wit.ai command parsed",
+		slog.String("user_id", cmd.UserID),
+		slog.String("intent", string(cmd.Intent)),
+		slog.Float64("confidence", cmd.Confidence),
+		slog.String("symbol", cmd.Symbol),
+		slog.Bool("valid", cmd.Valid),
+		slog.Any("missing", cmd.Missing),
+		slog.Any("errors", cmd.Errors),
+		slog.Any("versions", cmd.Meta.Versions),
+	)
+	if cmd.Intent == intent.IntentPanicClose {
+		p.logger.LogAttrs(ctx, slog.LevelWarn, "wit.ai panic close parsed",
+			slog.String("id", cmd.ID),
+			slog.String("user_id", cmd.UserID),
+			slog.String("exchange", cmd.Exchange),
+			slog.String("account", cmd.Account),
+			slog.Float64("confidence", cmd.Confidence),
+			slog.Bool("valid", cmd.Valid),
+			p.inputAttr(input),
+		)
+	}
+	intent.PublishParsed(p.events, p.Name(), cmd, p.clock.Now())
+}
+
+// validatorOptions returns the options cmd is validated with: the custom
+// rules, and the reference time and clock relative expressions were
+// resolved with
+func (p *Processor) validatorOptions(ctx context.Context) []validators.ValidatorOption {
+	return []validators.ValidatorOption{
+		validators.WithRules(p.rules...),
+		validators.WithReferenceTime(intent.ParseOptionsFromContext(ctx).ReferenceTime),
+		validators.WithClock(p.clock),
+	}
+}
+
+// compareShadowPolicy validates a copy of cmd with the shadow policy, if
+// any, and logs when its outcome differs from the enforced one
+func (p *Processor) compareShadowPolicy(ctx context.Context, cmd *intent.NormalizedCommand) {
+	if p.shadowPolicy == nil {
+		return
+	}
+
+	active := validators.OutcomeOf(cmd)
+	shadow := p.validators.Shadow(cmd, *p.shadowPolicy, p.validatorOptions(ctx)...)
+	if active.Equal(shadow) {
+		return
+	}
+
+	p.logger.LogAttrs(ctx, slog.LevelInfo, "shadow policy outcome differs",
+		slog.String("id", cmd.ID),
+		slog.String("user_id", cmd.UserID),
+		slog.String("intent", string(cmd.Intent)),
+		slog.Bool("active_valid", active.Valid),
+		slog.Bool("shadow_valid", shadow.Valid),
+		slog.Any("active_errors", active.Errors),
+		slog.Any("shadow_errors", shadow.Errors),
+		slog.Any("active_missing", active.Missing),
+		slog.Any("shadow_missing", shadow.Missing),
+		slog.Any("active_warnings", active.Warnings),
+		slog.Any("shadow_warnings", shadow.Warnings),
+	)
+}
+
+// eventInput returns input for ParseStarted events, empty when input is redacted
+func (p *Processor) eventInput(input string) string {
+	if p.redactInput {
+		return ""
+	}
+	return input
+}
+
+// applyMinConfidence downgrades a validated command to IntentUnknown when its
+// confidence is below min. Missing fields are cleared since they belonged to
+// the discarded intent.
+func applyMinConfidence(cmd *intent.NormalizedCommand, min float64) {
+	// A panic close is kept: it is always confirmed, so a false positive
+	// costs a prompt while dropping a real one leaves the user exposed
+	if cmd.Intent == intent.IntentUnknown || cmd.Intent == intent.IntentPanicClose || cmd.Confidence >= min {
+		return
+	}
+
+	original := cmd.Intent
+	cmd.Intent = intent.IntentUnknown
+	cmd.Valid = false
+	cmd.ConfirmationRequired = false
+	cmd.Missing = []string{}
+	cmd.Errors = []string{}
+	cmd.Warnings = nil
+	cmd.Issues = nil
+	cmd.AddIssue(intent.ValidationIssue{
+		Code:    intent.IssueCodeLowConfidence,
+		Field:   "confidence",
+		Message: fmt.Sprintf("confidence %.2f for %s is below threshold %.2f", cmd.Confidence, original, min),
+	})
+}
+
+// ParseCommands parses inputs concurrently (see WithConcurrency) and returns
+// the commands in input order
+func (p *Processor) ParseCommands(ctx context.Context, inputs []string) ([]*intent.NormalizedCommand, error) {
+	return intent.ParseConcurrently(ctx, p, inputs, p.concurrency)
+}
+
+// Ping checks that Wit.ai is reachable and the token is valid by listing
+// the app intents, which doesn't consume a parse
+func (p *Processor) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/intents", nil)
+	if err != nil {
+		return err
+	}
+
+	q := req.URL.Query()
+	q.Add("v", apiVersion)
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Authorization", "Bearer "+p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("wit.ai ping failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("wit.ai ping returned status %d", resp.StatusCode)
+	}
+	return nil
 }
 
 // callWitAI makes HTTP request to Wit.ai API
 func (p *Processor) callWitAI(ctx context.Context, input string) (*WitAIResponse, error) {
-	apiURL := "https://api.wit.ai/message"
+	apiURL := p.baseURL + "/message"
 	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	q := req.URL.Query()
-	q.Add("v", "20240304")
+	q.Add("v", apiVersion)
 	q.Add("q", input)
+	if err := addParseOptions(q, intent.ParseOptionsFromContext(ctx)); err != nil {
+		return nil, err
+	}
 	req.URL.RawQuery = q.Encode()
 
 	req.Header.Set("Authorization", "Bearer "+p.token)
 
+	p.logger.DebugContext(ctx, "wit.ai request",
+		slog.String("user_id", intent.UserIDFromContext(ctx)),
+		slog.String("url", apiURL),
+		slog.String("token", redactToken(p.token)),
+		p.inputAttr(input),
+	)
+
+	start := p.clock.Now()
 	resp, err := p.client.Do(req)
 	if err != nil {
+		p.logger.DebugContext(ctx, "wit.ai request failed", slog.Any("error", err))
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		p.logger.DebugContext(ctx, "wit.ai unexpected status", slog.Int("status", resp.StatusCode))
 		return nil, fmt.Errorf("wit.ai returned status %d", resp.StatusCode)
 	}
 
-	var witResp WitAIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&witResp); err != nil {
+	witResp, err := p.decodeResponse(ctx, resp.Body, decodeMessage)
+	if err != nil {
 		return nil, err
 	}
 
-	return &witResp, nil
+	attrs := append([]any{
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("latency", p.since(start)),
+	}, responseAttrs(witResp)...)
+	p.logger.DebugContext(ctx, "wit.ai response", attrs...)
+
+	return witResp, nil
 }
@@ -0,0 +1,417 @@
+package witai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/validators"
+)
+
+func TestPing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/intents" {
+			t.Errorf("path = %q, want /intents", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	healthy, _ := New("good", WithBaseURL(server.URL))
+	if err := healthy.Ping(ctx); err != nil {
+		t.Errorf("Ping() error = %v, want nil", err)
+	}
+
+	unauthorized, _ := New("bad", WithBaseURL(server.URL))
+	if err := unauthorized.Ping(ctx); err == nil {
+		t.Error("Ping() error = nil, want error for unauthorized token")
+	}
+
+	// Decorated processors are unwrapped by intent.Ping
+	decorated := intent.Chain(unauthorized, intent.CacheMiddleware(0, 10))
+	if err := intent.Ping(ctx, decorated); err == nil {
+		t.Error("intent.Ping() error = nil, want error from wrapped witai processor")
+	}
+}
+
+func TestApplyMinConfidence(t *testing.T) {
+	tests := []struct {
+		name       string
+		intent     intent.Intent
+		confidence float64
+		min        float64
+		want       intent.Intent
+		wantError  string
+	}{
+		{"Above threshold", intent.IntentClosePosition, 0.9, 0.7, intent.IntentClosePosition, ""},
+		{"Equal to threshold", intent.IntentClosePosition, 0.7, 0.7, intent.IntentClosePosition, ""},
+		{"Below threshold", intent.IntentClosePosition, 0.42, 0.7, intent.IntentUnknown,
+			"confidence 0.42 for close_position is below threshold 0.70"},
+		{"No threshold", intent.IntentClosePosition, 0.1, 0, intent.IntentClosePosition, ""},
+		{"Already unknown", intent.IntentUnknown, 0.1, 0.7, intent.IntentUnknown, "unknown intent: unknown"},
+		{"Panic close kept", intent.IntentPanicClose, 0.42, 0.7, intent.IntentPanicClose, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &intent.NormalizedCommand{Intent: tt.intent, Confidence: tt.confidence}
+			validators.ValidateCommand(cmd)
+			applyMinConfidence(cmd, tt.min)
+
+			if cmd.Intent != tt.want {
+				t.Errorf("Intent = %v, want %v", cmd.Intent, tt.want)
+			}
+			if tt.wantError != "" {
+				if cmd.Valid {
+					t.Error("Valid = true, want false")
+				}
+				if len(cmd.Errors) != 1 || cmd.Errors[0] != tt.wantError {
+					t.Errorf("Errors = %v, want [%q]", cmd.Errors, tt.wantError)
+				}
+				if len(cmd.Missing) != 0 {
+					t.Errorf("Missing = %v, want empty", cmd.Missing)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCommand_StampsUserID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(positionsResponse))
+	}))
+	defer server.Close()
+
+	p, _ := New("token", WithBaseURL(server.URL))
+	ctx := intent.WithUser(context.Background(), intent.User{ID: "u-42"})
+
+	cmd, err := p.ParseCommand(ctx, "show my positions")
+	if err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+	if cmd.UserID != "u-42" {
+		t.Errorf("UserID = %q, want %q", cmd.UserID, "u-42")
+	}
+}
+
+func TestParseCommand_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	p, _ := New("token", WithBaseURL(server.URL))
+	ctx := intent.WithParseOptions(context.Background(), intent.WithTimeout(10*time.Millisecond))
+
+	_, err := p.ParseCommand(ctx, "show my positions")
+	if !errors.Is(err, intent.ErrTimeout) {
+		t.Fatalf("ParseCommand error = %v, want intent.ErrTimeout", err)
+	}
+}
+
+func TestParseCommand_PublishesEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(positionsResponse))
+	}))
+	defer server.Close()
+
+	bus := intent.NewEventBus()
+	var names []string
+	bus.Subscribe(func(e intent.Event) { names = append(names, e.EventName()) })
+
+	p, _ := New("token", WithBaseURL(server.URL), WithEvents(bus))
+	if _, err := p.ParseCommand(context.Background(), "show my positions"); err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	want := []string{"parse_started", "backend_called", "command_ready"}
+	if len(names) != len(want) {
+		t.Fatalf("events = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("events[%d] = %q, want %q", i, names[i], want[i])
+		}
+	}
+}
+
+func TestParseCommand_PublishesParseFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	bus := intent.NewEventBus()
+	var names []string
+	bus.Subscribe(func(e intent.Event) { names = append(names, e.EventName()) })
+
+	p, _ := New("token", WithBaseURL(server.URL), WithEvents(bus))
+	if _, err := p.ParseCommand(context.Background(), "show my positions"); err == nil {
+		t.Fatal("ParseCommand succeeded, want error")
+	}
+
+	want := []string{"parse_started", "backend_called", "parse_failed"}
+	if !slices.Equal(names, want) {
+		t.Errorf("events = %v, want %v", names, want)
+	}
+}
+
+func TestParseCommand_Plugins(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("q")
+		w.Write([]byte(positionsResponse))
+	}))
+	defer server.Close()
+
+	var events int
+	p, _ := New("token", WithBaseURL(server.URL), WithPlugins(intent.Plugin{
+		Name:       "test",
+		PreProcess: func(_ context.Context, s string) string { return s + " please" },
+		Validate: func(cmd *intent.NormalizedCommand) {
+			cmd.Errors = append(cmd.Errors, "blocked by plugin")
+			cmd.Valid = false
+		},
+		Emit: func(intent.Event) { events++ },
+	}))
+
+	cmd, err := p.ParseCommand(context.Background(), "show my positions")
+	if err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+	if query != "show my positions please" {
+		t.Errorf("sent q = %q, want the pre-processed input", query)
+	}
+	if cmd.RawInput != "show my positions" {
+		t.Errorf("RawInput = %q, want the original input", cmd.RawInput)
+	}
+	if cmd.Valid {
+		t.Error("Valid = true, want plugin validation to fail the command")
+	}
+	if events != 3 {
+		t.Errorf("plugin received %d events, want 3", events)
+	}
+}
+
+func TestBuildCommand_ShadowPolicy(t *testing.T) {
+	var logs bytes.Buffer
+	p, _ := New("token",
+		WithLogger(slog.NewTextHandler(&logs, nil)),
+		WithPolicy(validators.Policy{
+			AllowWithdrawals:   true,
+			AddressWhitelisted: func(ref string) bool { return ref == "ledger" },
+		}),
+		WithShadowPolicy(validators.Policy{}),
+	)
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "withdraw", Confidence: 0.95}},
+		Entities: map[string][]WitAIEntity{
+			"asset":       {{Value: "usdt"}},
+			"amount":      {{Value: "100"}},
+			"address_ref": {{Value: "ledger"}},
+		},
+	}
+
+	cmd := p.buildCommand(context.Background(), resp, "withdraw 100 USDT to ledger")
+
+	if !cmd.Valid {
+		t.Errorf("Valid = false, want the enforced policy to accept (errors %v)", cmd.Errors)
+	}
+	if !strings.Contains(logs.String(), "shadow policy outcome differs") {
+		t.Errorf("logs = %q, want a shadow policy difference", logs.String())
+	}
+}
+
+func TestBuildCommand_Validators(t *testing.T) {
+	r := validators.NewRegistry()
+	r.Register(intent.IntentSetLeverage, func(cmd *intent.NormalizedCommand, _ validators.Policy) {
+		if cmd.Leverage != nil && *cmd.Leverage > 20 {
+			cmd.Errors = append(cmd.Errors, "leverage above the desk limit of 20")
+			cmd.Valid = false
+		}
+	})
+	p, _ := New("token", WithValidators(r))
+	resp := &WitAIResponse{
+		Intents: []WitAIIntent{{Name: "set_leverage", Confidence: 0.95}},
+		Entities: map[string][]WitAIEntity{
+			"symbol":   {{Value: "BTC"}},
+			"leverage": {{Value: "50"}},
+		},
+	}
+
+	cmd := p.buildCommand(context.Background(), resp, "set BTC leverage to 50x")
+
+	if cmd.Valid || len(cmd.Errors) != 1 || cmd.Errors[0] != "leverage above the desk limit of 20" {
+		t.Errorf("Valid = %v, Errors = %v, want the registered validator applied", cmd.Valid, cmd.Errors)
+	}
+}
+
+func TestBuildCommand_ValidationRules(t *testing.T) {
+	banned := func(cmd *intent.NormalizedCommand) []intent.ValidationIssue {
+		if cmd.Symbol == "LUNA-USDT" {
+			return []intent.ValidationIssue{{Code: "banned_symbol", Field: "symbol", Message: "LUNA-USDT is not tradable"}}
+		}
+		return nil
+	}
+	p, _ := New("token", WithValidationRules(banned))
+	resp := &WitAIResponse{
+		Intents:  []WitAIIntent{{Name: "view_price", Confidence: 0.95}},
+		Entities: map[string][]WitAIEntity{"symbol": {{Value: "luna"}}},
+	}
+
+	cmd := p.buildCommand(context.Background(), resp, "price of luna")
+
+	if cmd.Valid || len(cmd.Issues) != 1 || cmd.Issues[0].Code != "banned_symbol" {
+		t.Errorf("Valid = %v, Issues = %+v, want the rule applied", cmd.Valid, cmd.Issues)
+	}
+}
+
+func TestBuildCommand_PanicClose(t *testing.T) {
+	var logs bytes.Buffer
+	p, _ := New("token", WithLogger(slog.NewTextHandler(&logs, nil)))
+	resp := &WitAIResponse{
+		Intents:  []WitAIIntent{{Name: "panic_close", Confidence: 0.88}},
+		Entities: map[string][]WitAIEntity{"exchange": {{Value: "binance"}}},
+	}
+
+	cmd := p.buildCommand(context.Background(), resp, "flatten everything on binance now")
+
+	if cmd.Intent != intent.IntentPanicClose || cmd.Exchange != "binance" {
+		t.Errorf("Intent = %v, Exchange = %q, want panic_close on binance", cmd.Intent, cmd.Exchange)
+	}
+	if !cmd.Valid || !cmd.ConfirmationRequired {
+		t.Errorf("Valid = %v, ConfirmationRequired = %v, want both true", cmd.Valid, cmd.ConfirmationRequired)
+	}
+	if !strings.Contains(logs.String(), "level=WARN") || !strings.Contains(logs.String(), "panic close parsed") {
+		t.Errorf("logs = %q, want a warning for the panic close", logs.String())
+	}
+}
+
+func TestBuildCommand_Exchange(t *testing.T) {
+	p, _ := New("token",
+		WithExchangeAliases(map[string]string{"scalping exchange": "bybit"}),
+		WithPolicy(validators.Policy{AllowedExchanges: []string{"binance", "bybit"}}),
+	)
+
+	tests := []struct {
+		spoken    string
+		want      string
+		wantValid bool
+	}{
+		{"Binance Futures", "binance", true},
+		{"scalping exchange", "bybit", true},
+		{"kraken", "kraken", false},
+	}
+
+	for _, tt := range tests {
+		resp := &WitAIResponse{
+			Intents:  []WitAIIntent{{Name: "view_positions", Confidence: 0.95}},
+			Entities: map[string][]WitAIEntity{"exchange": {{Value: tt.spoken}}},
+		}
+
+		cmd := p.buildCommand(context.Background(), resp, "show my positions on "+tt.spoken)
+
+		if cmd.Exchange != tt.want {
+			t.Errorf("%s: Exchange = %q, want %q", tt.spoken, cmd.Exchange, tt.want)
+		}
+		if cmd.Valid != tt.wantValid {
+			t.Errorf("%s: Valid = %v, want %v (errors %v)", tt.spoken, cmd.Valid, tt.wantValid, cmd.Errors)
+		}
+	}
+}
+
+func TestBuildCommand_Meta(t *testing.T) {
+	p, _ := New("token", WithPlugins(intent.Plugin{Name: "slang", Version: "1.2"}))
+	resp := &WitAIResponse{Intents: []WitAIIntent{{Name: "view_positions", Confidence: 0.95}}}
+
+	first := p.buildCommand(context.Background(), resp, "show my positions")
+	second := p.buildCommand(context.Background(), resp, "show my positions")
+
+	if first.Meta == nil || first.Meta.Processor != "witai" {
+		t.Fatalf("Meta = %+v, want processor witai", first.Meta)
+	}
+	for _, key := range []string{"intent-go", "witai/api", "witai/symbols", "plugin/slang"} {
+		if first.Meta.Versions[key] == "" {
+			t.Errorf("Versions[%q] is empty", key)
+		}
+	}
+	if first.Meta == second.Meta {
+		t.Error("commands share the same Meta")
+	}
+}
+
+// latencySink records the latencies reported to the metrics sink
+type latencySink struct {
+	intent.NopMetrics
+	latencies []time.Duration
+}
+
+func (s *latencySink) ParseSucceeded(_ string, _ intent.Intent, _ float64, latency time.Duration) {
+	s.latencies = append(s.latencies, latency)
+}
+
+func TestParseCommand_FixedClock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(positionsResponse))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	bus := intent.NewEventBus()
+	var times []time.Time
+	var latencies []time.Duration
+	bus.Subscribe(func(e intent.Event) {
+		switch e := e.(type) {
+		case intent.ParseStarted:
+			times = append(times, e.Time)
+		case intent.BackendCalled:
+			times = append(times, e.Time)
+			latencies = append(latencies, e.Latency)
+		case intent.CommandReady:
+			times = append(times, e.Time)
+		}
+	})
+	sink := &latencySink{}
+	p, _ := New("token", WithBaseURL(server.URL), WithClock(intent.NewStepClock(now, 0)), WithEvents(bus), WithMetrics(sink))
+
+	cmd, err := p.ParseCommand(context.Background(), "show my positions")
+	if err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+
+	if !cmd.Timestamp.Equal(now) {
+		t.Errorf("Timestamp = %v, want %v", cmd.Timestamp, now)
+	}
+	if len(times) != 3 {
+		t.Fatalf("events = %d, want parse_started, backend_called and command_ready", len(times))
+	}
+	for _, got := range times {
+		if !got.Equal(now) {
+			t.Errorf("event time = %v, want %v", got, now)
+		}
+	}
+	for _, got := range append(latencies, sink.latencies...) {
+		if got != 0 {
+			t.Errorf("latency = %v, want 0 on a fixed clock", got)
+		}
+	}
+}
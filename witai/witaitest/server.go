@@ -0,0 +1,200 @@
+// Package witaitest provides a fake Wit.ai server for integration tests.
+// It implements the /message contract with programmable responses, latency
+// injection and rate-limit simulation, so retries, timeouts and fallbacks
+// can be exercised without a live token.
+package witaitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/intent-go/witai"
+)
+
+// Token is the bearer token the server accepts
+const Token = "witaitest-token"
+
+// Request is a /message call received by the server
+type Request struct {
+	Query  string     // The q parameter
+	Params url.Values // All query parameters, e.g. context
+	Time   time.Time
+}
+
+// Server is a fake Wit.ai API. Create it with NewServer and point the
+// processor at it with witai.WithBaseURL(s.URL).
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses map[string]witai.WitAIResponse
+	fallback  *witai.WitAIResponse
+	failures  []int // Statuses returned by the next requests, in order
+	latency   time.Duration
+	limit     int
+	window    time.Duration
+	windowEnd time.Time
+	served    int // Requests within the current rate-limit window
+	requests  []Request
+}
+
+// NewServer starts a fake Wit.ai server. Close it when done.
+func NewServer() *Server {
+	s := &Server{responses: make(map[string]witai.WitAIResponse)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/message", s.handleMessage)
+	mux.HandleFunc("/intents", s.handleIntents)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Respond programs the response for input, matched case- and
+// whitespace-insensitively. The response text defaults to input.
+func (s *Server) Respond(input string, resp witai.WitAIResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if resp.Text == "" {
+		resp.Text = input
+	}
+	s.responses[normalize(input)] = resp
+}
+
+// RespondIntent programs input to resolve to intentName with the given
+// confidence, and entities keyed by the names the transformer reads
+// (see witai.CanonicalEntities)
+func (s *Server) RespondIntent(input, intentName string, confidence float64, entities map[string]string) {
+	resp := witai.WitAIResponse{
+		Intents:  []witai.WitAIIntent{{Name: intentName, Confidence: confidence}},
+		Entities: make(map[string][]witai.WitAIEntity),
+	}
+	for name, value := range entities {
+		resp.Entities[name] = []witai.WitAIEntity{{Name: name, Role: name, Value: value, Confidence: confidence}}
+	}
+	s.Respond(input, resp)
+}
+
+// RespondDefault sets the response for inputs without a programmed one.
+// Without a default they resolve to no intent.
+func (s *Server) RespondDefault(resp witai.WitAIResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fallback = &resp
+}
+
+// FailNext makes the next len(statuses) requests fail with the given HTTP
+// statuses, in order, e.g. FailNext(500, 503) before a success
+func (s *Server) FailNext(statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statuses...)
+}
+
+// SetLatency delays every response by d. A request whose context ends
+// first is abandoned, like a real slow backend.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// SetRateLimit answers 429 Too Many Requests once more than limit requests
+// arrive within window. A limit <= 0 disables rate limiting.
+func (s *Server) SetRateLimit(limit int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = limit
+	s.window = window
+	s.windowEnd = time.Time{}
+	s.served = 0
+}
+
+// Requests returns the /message requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) handleMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+Token {
+		http.Error(w, `{"error":"Bad auth, check token/params","code":"no-auth"}`, http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	now := time.Now()
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Query: query.Get("q"), Params: query, Time: now})
+	latency := s.latency
+	status := s.nextStatus(now)
+	resp, ok := s.responses[normalize(query.Get("q"))]
+	if !ok {
+		resp = witai.WitAIResponse{Text: query.Get("q"), Intents: []witai.WitAIIntent{}, Entities: map[string][]witai.WitAIEntity{}}
+		if s.fallback != nil {
+			resp = *s.fallback
+			resp.Text = query.Get("q")
+		}
+	}
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if status != http.StatusOK {
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		http.Error(w, `{"error":"simulated failure"}`, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// nextStatus applies programmed failures, then the rate limit. s.mu must be held.
+func (s *Server) nextStatus(now time.Time) int {
+	if len(s.failures) > 0 {
+		status := s.failures[0]
+		s.failures = s.failures[1:]
+		return status
+	}
+
+	if s.limit <= 0 {
+		return http.StatusOK
+	}
+	if now.After(s.windowEnd) {
+		s.windowEnd = now.Add(s.window)
+		s.served = 0
+	}
+	s.served++
+	if s.served > s.limit {
+		return http.StatusTooManyRequests
+	}
+	return http.StatusOK
+}
+
+// handleIntents answers the listing used by Processor.Ping
+func (s *Server) handleIntents(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+Token {
+		http.Error(w, `{"error":"Bad auth, check token/params","code":"no-auth"}`, http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("[]"))
+}
+
+func normalize(input string) string {
+	return strings.ToLower(strings.Join(strings.Fields(input), " "))
+}
@@ -0,0 +1,109 @@
+package witaitest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/intent-go"
+	"github.com/agatticelli/intent-go/witai"
+)
+
+func newProcessor(t *testing.T, s *Server) *witai.Processor {
+	t.Helper()
+	p, err := witai.New(Token, witai.WithBaseURL(s.URL))
+	if err != nil {
+		t.Fatalf("witai.New error: %v", err)
+	}
+	return p
+}
+
+func TestServer_ProgrammedResponses(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.RespondIntent("open long BTC at 45000", "open_position", 0.97, map[string]string{
+		"symbol":      "btc",
+		"side":        "long",
+		"entry_price": "45000",
+	})
+	p := newProcessor(t, s)
+
+	cmd, err := p.ParseCommand(context.Background(), "Open long  BTC at 45000")
+	if err != nil {
+		t.Fatalf("ParseCommand error: %v", err)
+	}
+	if cmd.Intent != intent.IntentOpenPosition || cmd.Symbol != "BTC-USDT" {
+		t.Errorf("got %v on %q, want open_position on BTC-USDT", cmd.Intent, cmd.Symbol)
+	}
+	if cmd.EntryPrice == nil || *cmd.EntryPrice != 45000 {
+		t.Errorf("EntryPrice = %v, want 45000", cmd.EntryPrice)
+	}
+
+	cmd, _ = p.ParseCommand(context.Background(), "something else")
+	if cmd.Valid || len(cmd.Alternatives) != 0 {
+		t.Errorf("unprogrammed input resolved to %v, want no intent", cmd.Intent)
+	}
+
+	if reqs := s.Requests(); len(reqs) != 2 || reqs[0].Query != "Open long  BTC at 45000" {
+		t.Errorf("Requests = %+v, want both queries recorded", reqs)
+	}
+	if err := p.Ping(context.Background()); err != nil {
+		t.Errorf("Ping error: %v", err)
+	}
+}
+
+func TestServer_FailNext(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.FailNext(500, 503)
+	p := newProcessor(t, s)
+
+	for range 2 {
+		if _, err := p.ParseCommand(context.Background(), "check balance"); err == nil {
+			t.Error("expected a simulated failure")
+		}
+	}
+	if _, err := p.ParseCommand(context.Background(), "check balance"); err != nil {
+		t.Errorf("ParseCommand after failures error: %v", err)
+	}
+}
+
+func TestServer_Latency(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetLatency(time.Second)
+	p := newProcessor(t, s)
+
+	ctx := intent.WithParseOptions(context.Background(), intent.WithTimeout(20*time.Millisecond))
+	if _, err := p.ParseCommand(ctx, "check balance"); !errors.Is(err, intent.ErrTimeout) {
+		t.Errorf("error = %v, want intent.ErrTimeout", err)
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetRateLimit(2, time.Hour)
+	p := newProcessor(t, s)
+
+	var failed int
+	for range 3 {
+		if _, err := p.ParseCommand(context.Background(), "check balance"); err != nil {
+			failed++
+		}
+	}
+	if failed != 1 {
+		t.Errorf("failed = %d, want 1 of 3 over a limit of 2", failed)
+	}
+}
+
+func TestServer_RejectsWrongToken(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	p, _ := witai.New("wrong", witai.WithBaseURL(s.URL))
+
+	if _, err := p.ParseCommand(context.Background(), "check balance"); err == nil {
+		t.Error("expected an auth failure")
+	}
+}